@@ -6,6 +6,7 @@ replace github.com/google/go-jsonnet => github.com/thequailman/go-jsonnet v0.0.0
 
 require (
 	filippo.io/edwards25519 v1.0.0
+	github.com/BurntSushi/toml v1.4.0
 	github.com/disintegration/imaging v1.6.2
 	github.com/gomarkdown/markdown v0.0.0-20230922105210-14b16010c2ee
 	github.com/google/go-cmp v0.5.9
@@ -23,7 +24,9 @@ require (
 	go.opentelemetry.io/otel/sdk v1.16.0
 	go.opentelemetry.io/otel/trace v1.16.0
 	golang.org/x/crypto v0.14.0
+	golang.org/x/sys v0.15.0
 	golang.org/x/term v0.13.0
+	sigs.k8s.io/yaml v1.3.0
 )
 
 require (
@@ -48,7 +51,6 @@ require (
 	go.opentelemetry.io/proto/otlp v0.19.0 // indirect
 	golang.org/x/image v0.10.0 // indirect
 	golang.org/x/net v0.17.0 // indirect
-	golang.org/x/sys v0.15.0 // indirect
 	golang.org/x/text v0.13.0 // indirect
 	google.golang.org/genproto v0.0.0-20230711160842-782d3b101e98 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20230711160842-782d3b101e98 // indirect
@@ -56,5 +58,4 @@ require (
 	google.golang.org/grpc v1.58.3 // indirect
 	google.golang.org/protobuf v1.31.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	sigs.k8s.io/yaml v1.3.0 // indirect
 )
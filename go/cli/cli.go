@@ -7,7 +7,6 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"sort"
 	"strings"
 	"sync"
 
@@ -28,15 +27,20 @@ type Config struct {
 	LogFormat     logger.Format `json:"logFormat"`
 	LogLevel      logger.Level  `json:"logLevel"`
 	NoColor       bool          `json:"noColor"`
+	OutputFormat  OutputFormat  `json:"outputFormat"`
+	configArgs    []string
+	container     ContainerBackend
 	runMock       *runMock
 	runMockEnable bool
 }
 
 type runMock struct {
-	inputs  []RunMockInput
-	errs    []error
-	mutex   *sync.Mutex
-	outputs []string
+	expectations []*RunMockExpectation
+	inputs       []RunMockInput
+	errs         []error
+	mutex        *sync.Mutex
+	results      []RunResult
+	unexpected   []string
 }
 
 // Command is a positional command to run.
@@ -47,17 +51,29 @@ type Command[T AppConfig[any]] struct {
 	/* Positional arguments required after command */
 	ArgumentsRequired []string
 
+	/* Subcommands of a command group, like app keys generate */
+	Commands map[string]Command[T]
+
+	/* Function returning completions for the next positional argument.  args[0] is the command name, followed by the positional arguments before current, the partial argument being completed */
+	Complete func(ctx context.Context, args []string, current string, config T) []string
+
+	/* Typed flags parsed after the command, retrieved using GetFlag.  If nil, flags are passed to Run as arguments */
+	Flags Flags
+
 	/* Override the command name in usage */
 	Name string
 
-	/* Function to run when calling the command */
+	/* Function to run when calling the command.  args[0] is the command name, followed by the positional arguments.  Optional for command groups */
 	Run func(ctx context.Context, args []string, config T) errs.Err
 
 	/* Usage information, omitting this hides the command */
 	Usage string
 }
 
-var ErrUnknownCommand = errs.ErrSenderNotFound.Wrap(errors.New("unknown command"))
+var (
+	ErrReloadNewConfig = errors.New("reloading the config requires NewConfig")
+	ErrUnknownCommand  = errs.ErrSenderNotFound.Wrap(errors.New("unknown command"))
+)
 
 // ConfigArgs is a list of config arguments.
 type ConfigArgs []string
@@ -80,6 +96,15 @@ type App[T AppConfig[any]] struct {
 	HideConfigFields []string
 	Name             string
 	NoParse          bool
+
+	/* NewConfig returns a config with the same default values set before calling Run, used by the shell reload command to parse the config again.  Reloading fails if it's nil */
+	NewConfig func() T
+
+	/* PluginDir is a directory searched for plugins before PATH.  It must be an absolute path */
+	PluginDir string
+
+	/* Plugins enables running plugin commands.  Plugins are executables named <app>-<command> in PluginDir or the absolute directories of PATH, which are run when a command is unknown */
+	Plugins bool
 }
 
 // AppConfig is a configuration that can be used with CLI.
@@ -93,68 +118,99 @@ func (a App[T]) Run() errs.Err {
 	ctx := context.Background()
 
 	flag.Usage = func() {
-		//nolint:forbidigo
-		fmt.Fprintf(logger.Stdout, `Usage: %s [flags] [command]
+		a.usage(nil, Command[T]{
+			Commands: a.Commands,
+			Usage:    a.Description,
+		})
+	}
+
+	c := ConfigArgs{}
 
-%s
+	a.setup(flag.CommandLine, &c)
 
-Commands:
-`, a.Name, a.Description)
+	flag.Parse()
 
-		c := []string{}
+	// Parse CLI environment early for logging options.
+	if err := config.ParseValues(ctx, a.Config, strings.ToUpper(a.Name)+"_cli_", os.Environ()); err != nil {
+		return logger.Error(ctx, errs.ErrReceiver.Wrap(config.ErrUpdateEnv, err))
+	}
 
-		for i := range a.Commands {
-			if a.Commands[i].Usage != "" {
-				c = append(c, i)
-			}
-		}
+	ctx = logger.SetFormat(ctx, a.Config.CLIConfig().LogFormat)
+	ctx = logger.SetLevel(ctx, a.Config.CLIConfig().LogLevel)
+	ctx = logger.SetNoColor(ctx, a.Config.CLIConfig().NoColor)
 
-		sort.Strings(c)
+	a.Config.CLIConfig().configArgs = c
 
-		for i := range c {
-			name := c[i]
-			if (a.Commands[c[i]]).Name != "" {
-				name = a.Commands[c[i]].Name
-			}
+	if !a.NoParse {
+		if err := a.Config.Parse(ctx, c); err != nil {
+			return err
+		}
+	}
+
+	outputFormat = a.Config.CLIConfig().OutputFormat
 
-			for _, arg := range a.Commands[c[i]].ArgumentsRequired {
-				name += fmt.Sprintf(" [%s]", arg)
-			}
+	args := flag.Args()
+	if len(args) < 1 {
+		flag.Usage()
 
-			for _, arg := range a.Commands[c[i]].ArgumentsOptional {
-				name += fmt.Sprintf(" [%s]", arg)
-			}
+		return ErrUnknownCommand
+	}
 
-			fmt.Fprintf(logger.Stdout, "  %s\n    	%s\n", name, a.Commands[c[i]].Usage) //nolint:forbidigo
+	if _, _, ok := findCommand(a.Commands, args[0]); !ok {
+		if p, ok := a.plugins()[args[0]]; ok {
+			return a.runPlugin(ctx, p, args[1:])
 		}
+	}
+
+	return a.runCommand(ctx, nil, Command[T]{
+		Commands: a.Commands,
+		Usage:    a.Description,
+	}, args)
+}
+
+// reparseConfig parses a new config from newConfig using the CLI options and config arguments of c.  c isn't modified.  newConfig is required, as a zero value would lose the defaults set by the app.
+func reparseConfig[T AppConfig[any]](ctx context.Context, c T, newConfig func() T) (T, errs.Err) {
+	if newConfig == nil {
+		return c, logger.Error(ctx, errs.ErrReceiver.Wrap(ErrReloadNewConfig))
+	}
+
+	n := newConfig()
 
-		//nolint: forbidigo
-		fmt.Fprintf(logger.Stdout, "\nFlags:\n")
+	// The CLI flags are only parsed by App.Run, so they're kept from the current config.
+	*n.CLIConfig() = *c.CLIConfig()
 
-		flag.CommandLine.SetOutput(logger.Stdout)
-		flag.PrintDefaults()
+	if err := n.Parse(ctx, c.CLIConfig().configArgs); err != nil {
+		return c, err
 	}
 
+	return n, nil
+}
+
+// setup adds the built in commands to the App and the global flags to fs.
+func (a App[T]) setup(fs *flag.FlagSet, c *ConfigArgs) {
+	a.Commands["completion"] = a.completionCommand()
+	a.Commands["docs"] = a.docsCommand()
+
 	a.Commands["jq"] = Command[T]{
+		ArgumentsOptional: []string{
+			"filter",
+			"files",
+		},
+		Flags: jqFlags,
 		Run:   jq[T],
-		Usage: "Query JSON from stdin using jq.  Supports standard JQ queries, and the -r flag to render raw values",
+		Usage: jqUsage,
 	}
 
-	c := ConfigArgs{}
+	a.Commands["shell"] = a.shellCommand()
 
 	if !a.NoParse {
-		a.Config.CLIConfig().ConfigPath = strings.ToLower(a.Name) + ".jsonnet"
-
-		flag.StringVar(&a.Config.CLIConfig().ConfigPath, "c", a.Config.CLIConfig().ConfigPath, "Path to JSON/Jsonnet configuration files separated by a comma")
-
+		a.Commands["config-schema"] = a.configSchemaCommand()
 		a.Commands["show-config"] = Command[T]{
 			Run: func(ctx context.Context, args []string, config T) errs.Err {
-				return printConfig(ctx, a)
+				return printConfig(ctx, a, config)
 			},
 			Usage: "Print the current configuration",
 		}
-
-		flag.Var(&c, "x", "Set config key=value (can be provided multiple times)")
 	}
 
 	a.Commands["version"] = Command[T]{
@@ -167,49 +223,20 @@ Commands:
 		Usage: "Print version information",
 	}
 
-	flag.StringVar((*string)(&a.Config.CLIConfig().LogFormat), "f", string(a.Config.CLIConfig().LogFormat), "Set log format (human, kv, raw, default: human)")
-	flag.StringVar((*string)(&a.Config.CLIConfig().LogLevel), "l", string(a.Config.CLIConfig().LogLevel), "Set minimum log level (none, debug, info, error, default: info)")
-	flag.BoolVar(&a.Config.CLIConfig().NoColor, "n", a.Config.CLIConfig().NoColor, "Disable colored logging")
-
-	flag.Parse()
-
-	// Parse CLI environment early for logging options.
-	if err := config.ParseValues(ctx, a.Config, strings.ToUpper(a.Name)+"_cli_", os.Environ()); err != nil {
-		return logger.Error(ctx, errs.ErrReceiver.Wrap(config.ErrUpdateEnv, err))
-	}
-
-	ctx = logger.SetFormat(ctx, a.Config.CLIConfig().LogFormat)
-	ctx = logger.SetLevel(ctx, a.Config.CLIConfig().LogLevel)
-	ctx = logger.SetNoColor(ctx, a.Config.CLIConfig().NoColor)
+	a.setupFlags(fs, a.Config.CLIConfig(), c)
+}
 
+// setupFlags adds the global flags to fs, setting the values in config.
+func (a App[T]) setupFlags(fs *flag.FlagSet, config *Config, c *ConfigArgs) {
 	if !a.NoParse {
-		if err := a.Config.Parse(ctx, c); err != nil {
-			return err
-		}
-	}
-
-	args := flag.Args()
-	if len(args) < 1 {
-		flag.Usage()
+		config.ConfigPath = strings.ToLower(a.Name) + ".jsonnet"
 
-		return ErrUnknownCommand
+		fs.StringVar(&config.ConfigPath, "c", config.ConfigPath, "Path to JSON/Jsonnet configuration files separated by a comma")
+		fs.Var(c, "x", "Set config key=value (can be provided multiple times)")
 	}
 
-	for k, v := range a.Commands {
-		if k == args[0] || strings.Split(v.Name, " ")[0] == args[0] {
-			if len(v.ArgumentsRequired) != 0 && (len(args)-1) < len(v.ArgumentsRequired) {
-				logger.Error(ctx, errs.ErrReceiver.Wrap(errors.New("missing arguments: ["+strings.Join(v.ArgumentsRequired[0+len(args)-1:], "] [")+"]\n"))) //nolint:errcheck
-
-				flag.Usage()
-
-				return ErrUnknownCommand
-			}
-
-			return v.Run(ctx, args, a.Config)
-		}
-	}
-
-	flag.Usage()
-
-	return ErrUnknownCommand
+	fs.StringVar((*string)(&config.LogFormat), "f", string(config.LogFormat), "Set log format (human, kv, raw, default: human)")
+	fs.StringVar((*string)(&config.LogLevel), "l", string(config.LogLevel), "Set minimum log level (none, debug, info, error, default: info)")
+	fs.BoolVar(&config.NoColor, "n", config.NoColor, "Disable colored logging")
+	fs.StringVar((*string)(&config.OutputFormat), "o", string(config.OutputFormat), "Set output format (env, json, json-compact, jsonnet, table, toml, yaml, default: json)")
 }
@@ -67,12 +67,16 @@ func TestAppRun(t *testing.T) {
 Does things
 
 Commands:
+  config-schema
+    	Print the JSON Schema of the configuration, for editor completion and validation
   fail
     	Fails the thing
   hello world [arg1] [arg2]
     	Does the thing
-  jq
-    	Query JSON from stdin using jq.  Supports standard JQ queries, and the -r flag to render raw values
+  jq [filter] [files]
+    	Query JSON from stdin or files using jq.  Supports standard JQ queries
+  shell
+    	Start an interactive shell for running commands and evaluating Jsonnet with the current config
   show-config
     	Print the current configuration
   version
@@ -86,6 +90,8 @@ Flags:
   -l string
     	Set minimum log level (none, debug, info, error, default: info)
   -n	Disable colored logging
+  -o string
+    	Set output format (env, json, json-compact, jsonnet, table, toml, yaml, default: json)
   -x value
     	Set config key=value (can be provided multiple times)
 `,
@@ -96,6 +102,18 @@ Flags:
   "Show": {
     "Message": "Hello World"
   }`,
+		},
+		"config-schema": {
+			args: []string{"-n", "-c", "./testdata/config.json", "config-schema"},
+			output: `
+    "Hide": {
+      "properties": {
+        "Message": {
+          "type": "string"
+        }
+      },
+      "type": "object"
+    },`,
 		},
 		"world": {
 			args: []string{"-n", "-c", "./testdata/config.json", "hello", "world"},
@@ -119,8 +137,10 @@ Commands:
     	Fails the thing
   hello world [arg1] [arg2]
     	Does the thing
-  jq
-    	Query JSON from stdin using jq.  Supports standard JQ queries, and the -r flag to render raw values
+  jq [filter] [files]
+    	Query JSON from stdin or files using jq.  Supports standard JQ queries
+  shell
+    	Start an interactive shell for running commands and evaluating Jsonnet with the current config
   version
     	Print version information
 
@@ -148,6 +168,7 @@ Build Date: %s`, date),
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
+			delete(a.Commands, "config-schema")
 			delete(a.Commands, "show-config")
 			BuildDate = tc.buildDate
 			a.Config.CLI.NoColor = false
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/candiddev/shared/go/errs"
+	"github.com/candiddev/shared/go/logger"
+)
+
+// findCommand returns the command matching the name or the first word of the command Name.
+func findCommand[T AppConfig[any]](commands map[string]Command[T], name string) (string, Command[T], bool) {
+	for k, v := range commands {
+		if k == name || strings.Split(v.Name, " ")[0] == name {
+			return k, v, true
+		}
+	}
+
+	return "", Command[T]{}, false
+}
+
+// runCommand finds and runs a command of parent from args, descending into command groups.  path is the list of command groups above parent.
+func (a App[T]) runCommand(ctx context.Context, path []string, parent Command[T], args []string) errs.Err {
+	k, c, ok := findCommand(parent.Commands, args[0])
+	if !ok {
+		if parent.Name != "" {
+			logger.Error(ctx, errs.ErrReceiver.Wrap(errors.New("unknown command: "+args[0]+"\n"))) //nolint:errcheck
+
+			a.usage(path, parent)
+		} else {
+			flag.Usage()
+		}
+
+		return ErrUnknownCommand
+	}
+
+	if c.Name == "" {
+		c.Name = k
+	}
+
+	// Path to c, used for usage.
+	if parent.Name != "" {
+		path = append(append([]string{}, path...), strings.Split(parent.Name, " ")[0])
+	}
+
+	if len(c.Commands) > 0 {
+		if len(args) > 1 {
+			if _, _, ok := findCommand(c.Commands, args[1]); ok || c.Run == nil {
+				return a.runCommand(ctx, path, c, args[1:])
+			}
+		}
+
+		if c.Run == nil {
+			a.usage(path, c)
+
+			return ErrUnknownCommand
+		}
+	}
+
+	positional := args[1:]
+
+	if c.Flags != nil {
+		values, p, err := c.Flags.parse(args[0], args[1:])
+		if err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				a.usage(path, c)
+
+				return nil
+			}
+
+			logger.Error(ctx, errs.ErrReceiver.Wrap(errors.New(err.Error()+"\n"))) //nolint:errcheck
+
+			a.usage(path, c)
+
+			return ErrInvalidFlag
+		}
+
+		ctx = context.WithValue(ctx, ctxFlags, values)
+		positional = p
+	}
+
+	if len(c.ArgumentsRequired) != 0 && len(positional) < len(c.ArgumentsRequired) {
+		logger.Error(ctx, errs.ErrReceiver.Wrap(errors.New("missing arguments: ["+strings.Join(c.ArgumentsRequired[len(positional):], "] [")+"]\n"))) //nolint:errcheck
+
+		if len(path) > 0 || c.Flags != nil {
+			a.usage(path, c)
+		} else {
+			flag.Usage()
+		}
+
+		return ErrUnknownCommand
+	}
+
+	return c.Run(ctx, append([]string{args[0]}, positional...), a.Config)
+}
+
+// usage prints the usage of a command level.  The top level is a Command containing the App Commands with no path or Name.
+func (a App[T]) usage(path []string, c Command[T]) {
+	u := append([]string{a.Name, "[flags]"}, path...)
+
+	if c.Name != "" {
+		u = append(u, strings.Split(c.Name, " ")[0])
+	}
+
+	if len(c.Commands) > 0 {
+		u = append(u, "[command]")
+	} else {
+		if c.Flags != nil {
+			u = append(u, "[flags]")
+		}
+
+		for _, arg := range c.ArgumentsRequired {
+			u = append(u, fmt.Sprintf("[%s]", arg))
+		}
+
+		for _, arg := range c.ArgumentsOptional {
+			u = append(u, fmt.Sprintf("[%s]", arg))
+		}
+	}
+
+	//nolint:forbidigo
+	fmt.Fprintf(logger.Stdout, "Usage: %s\n\n%s\n", strings.Join(u, " "), c.Usage)
+
+	if len(c.Commands) > 0 {
+		fmt.Fprintf(logger.Stdout, "\nCommands:\n") //nolint:forbidigo
+
+		names := []string{}
+
+		for i := range c.Commands {
+			if c.Commands[i].Usage != "" {
+				names = append(names, i)
+			}
+		}
+
+		sort.Strings(names)
+
+		for i := range names {
+			s := c.Commands[names[i]]
+
+			name := names[i]
+			if s.Name != "" {
+				name = s.Name
+			}
+
+			if len(s.Commands) > 0 {
+				name += " [command]"
+			}
+
+			for _, arg := range s.ArgumentsRequired {
+				name += fmt.Sprintf(" [%s]", arg)
+			}
+
+			for _, arg := range s.ArgumentsOptional {
+				name += fmt.Sprintf(" [%s]", arg)
+			}
+
+			fmt.Fprintf(logger.Stdout, "  %s\n    	%s\n", name, s.Usage) //nolint:forbidigo
+		}
+	}
+
+	if path == nil && c.Name == "" {
+		if p := a.plugins(); len(p) > 0 {
+			fmt.Fprintf(logger.Stdout, "\nPlugins:\n") //nolint:forbidigo
+
+			for _, name := range pluginNames(p) {
+				fmt.Fprintf(logger.Stdout, "  %s\n    	%s\n", name, p[name]) //nolint:forbidigo
+			}
+		}
+	}
+
+	flag.CommandLine.SetOutput(logger.Stdout)
+
+	if path == nil && c.Name == "" {
+		fmt.Fprintf(logger.Stdout, "\nFlags:\n") //nolint:forbidigo
+		flag.PrintDefaults()
+
+		return
+	}
+
+	if c.Flags != nil {
+		fmt.Fprintf(logger.Stdout, "\nFlags:\n") //nolint:forbidigo
+		c.Flags.printDefaults(logger.Stdout)
+	}
+
+	fmt.Fprintf(logger.Stdout, "\nGlobal Flags:\n") //nolint:forbidigo
+	flag.PrintDefaults()
+}
@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/errs"
+	"github.com/candiddev/shared/go/logger"
+)
+
+func TestAppRunCommands(t *testing.T) {
+	var gotArgs []string
+
+	var gotBits int
+
+	var gotTags []string
+
+	var gotTimeout time.Duration
+
+	var gotVerbose bool
+
+	a := App[*C]{
+		Commands: map[string]Command[*C]{
+			"keys": {
+				Commands: map[string]Command[*C]{
+					"generate": {
+						ArgumentsOptional: []string{
+							"comment",
+						},
+						ArgumentsRequired: []string{
+							"name",
+						},
+						Flags: Flags{
+							"bits": {
+								Default: 2048,
+								Usage:   "Key `size`",
+							},
+							"tag": {
+								Default: []string{},
+								Usage:   "Key tag (can be provided multiple times)",
+							},
+							"timeout": {
+								Default:  time.Second,
+								Required: true,
+								Usage:    "Generation timeout",
+							},
+							"v": {
+								Default: false,
+								Usage:   "Verbose output",
+							},
+						},
+						Run: func(ctx context.Context, args []string, config *C) errs.Err {
+							gotArgs = args
+							gotBits = GetFlag[int](ctx, "bits")
+							gotTags = GetFlag[[]string](ctx, "tag")
+							gotTimeout = GetFlag[time.Duration](ctx, "timeout")
+							gotVerbose = GetFlag[bool](ctx, "v")
+
+							return nil
+						},
+						Usage: "Generate a key",
+					},
+					"list": {
+						Run: func(ctx context.Context, args []string, config *C) errs.Err {
+							gotArgs = args
+
+							return nil
+						},
+						Usage: "List keys",
+					},
+				},
+				Usage: "Manage keys",
+			},
+		},
+		Config:      &C{},
+		Description: "Does things",
+		Name:        "App",
+		NoParse:     true,
+	}
+
+	tests := map[string]struct {
+		args        []string
+		err         error
+		output      string
+		wantArgs    []string
+		wantBits    int
+		wantTags    []string
+		wantTimeout time.Duration
+		wantVerbose bool
+	}{
+		"usage": {
+			err: ErrUnknownCommand,
+			output: `Commands:
+  jq [filter] [files]
+    	Query JSON from stdin or files using jq.  Supports standard JQ queries
+  keys [command]
+    	Manage keys
+`,
+		},
+		"group usage": {
+			args: []string{"keys"},
+			err:  ErrUnknownCommand,
+			output: `Usage: App [flags] keys [command]
+
+Manage keys
+
+Commands:
+  generate [name] [comment]
+    	Generate a key
+  list
+    	List keys
+
+Global Flags:
+`,
+		},
+		"group unknown": {
+			args:   []string{"keys", "delete"},
+			err:    ErrUnknownCommand,
+			output: "Usage: App [flags] keys [command]",
+		},
+		"help": {
+			args: []string{"keys", "generate", "-h"},
+			output: `Usage: App [flags] keys generate [flags] [name] [comment]
+
+Generate a key
+
+Flags:
+  -bits size
+    	Key size (default 2048)
+  -tag value
+    	Key tag (can be provided multiple times)
+  -timeout duration
+    	Generation timeout (required) (default 1s)
+  -v	Verbose output
+
+Global Flags:
+`,
+		},
+		"list": {
+			args:     []string{"keys", "list", "-a"},
+			wantArgs: []string{"list", "-a"},
+		},
+		"generate": {
+			args:        []string{"keys", "generate", "-timeout", "1m", "name", "-bits=4096", "-tag", "a", "-tag", "b", "-v", "--", "-comment"},
+			wantArgs:    []string{"generate", "name", "-comment"},
+			wantBits:    4096,
+			wantTags:    []string{"a", "b"},
+			wantTimeout: time.Minute,
+			wantVerbose: true,
+		},
+		"generate defaults": {
+			args:        []string{"keys", "generate", "name", "-timeout", "1s"},
+			wantArgs:    []string{"generate", "name"},
+			wantBits:    2048,
+			wantTags:    []string{},
+			wantTimeout: time.Second,
+		},
+		"generate missing argument": {
+			args:   []string{"keys", "generate", "-timeout", "1s"},
+			err:    ErrUnknownCommand,
+			output: "Usage: App [flags] keys generate [flags] [name] [comment]",
+		},
+		"generate missing flag": {
+			args:   []string{"keys", "generate", "name"},
+			err:    ErrInvalidFlag,
+			output: "Usage: App [flags] keys generate [flags] [name] [comment]",
+		},
+		"generate invalid flag": {
+			args: []string{"keys", "generate", "-timeout", "1s", "-bits", "a", "name"},
+			err:  ErrInvalidFlag,
+		},
+		"generate unknown flag": {
+			args: []string{"keys", "generate", "-timeout", "1s", "-size", "1", "name"},
+			err:  ErrInvalidFlag,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotArgs = nil
+			gotBits = 0
+			gotTags = nil
+			gotTimeout = 0
+			gotVerbose = false
+
+			os.Args = append([]string{"app", "-n"}, tc.args...)
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+			logger.SetStd()
+
+			assert.HasErr(t, a.Run(), tc.err)
+			assert.Contains(t, logger.ReadStd(), tc.output)
+			assert.Equal(t, gotArgs, tc.wantArgs)
+			assert.Equal(t, gotBits, tc.wantBits)
+			assert.Equal(t, gotTags, tc.wantTags)
+			assert.Equal(t, gotTimeout, tc.wantTimeout)
+			assert.Equal(t, gotVerbose, tc.wantVerbose)
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/candiddev/shared/go/errs"
+	"github.com/candiddev/shared/go/logger"
+)
+
+var ErrUnknownShell = errs.ErrSenderBadRequest.Wrap(errors.New("unknown shell, must be bash, zsh, or fish"))
+
+const completionBash = `# bash completion for {{ .Name }}
+_{{ .Function }}() {
+	local IFS=$'\n'
+	COMPREPLY=($({{ .Name }} -l none completion __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+
+	if [[ ${#COMPREPLY[@]} -eq 1 && ${COMPREPLY[0]} == *= ]]; then
+		compopt -o nospace
+	fi
+}
+
+complete -F _{{ .Function }} {{ .Name }}
+`
+
+const completionFish = `# fish completion for {{ .Name }}
+function __{{ .Function }}_complete
+	set -l tokens (commandline -opc) (commandline -ct)
+	{{ .Name }} -l none completion __complete $tokens[2..-1] 2>/dev/null
+end
+
+complete -c {{ .Name }} -f -a '(__{{ .Function }}_complete)'
+`
+
+const completionZsh = `#compdef {{ .Name }}
+_{{ .Function }}() {
+	local -a completions
+	completions=("${(@f)$({{ .Name }} -l none completion __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+	compadd -S '' -- "${(@)completions:#*[^=]}"
+	compadd -- "${(@)completions:#*=}"
+}
+
+compdef _{{ .Function }} {{ .Name }}
+`
+
+// globalFlagValues are the completions for global flags that accept a value.  Values for -c and -x are determined by completeGlobalFlag.
+var globalFlagValues = map[string][]string{ //nolint:gochecknoglobals
+	"-c": nil,
+	"-f": {string(logger.FormatHuman), string(logger.FormatKV), string(logger.FormatRaw)},
+	"-l": {string(logger.LevelNone), string(logger.LevelDebug), string(logger.LevelInfo), string(logger.LevelError)},
+	"-o": {string(OutputFormatEnv), string(OutputFormatJSON), string(OutputFormatJSONCompact), string(OutputFormatJsonnet), string(OutputFormatTable), string(OutputFormatTOML), string(OutputFormatYAML)},
+	"-x": {},
+}
+
+func (a App[T]) completionCommand() Command[T] {
+	return Command[T]{
+		ArgumentsRequired: []string{
+			"bash|zsh|fish",
+		},
+		Run: func(ctx context.Context, args []string, _ T) errs.Err {
+			var s string
+
+			switch args[1] {
+			case "__complete":
+				for _, c := range a.complete(ctx, args[2:]) {
+					logger.Raw(c + "\n")
+				}
+
+				return nil
+			case "bash":
+				s = completionBash
+			case "fish":
+				s = completionFish
+			case "zsh":
+				s = completionZsh
+			default:
+				return logger.Error(ctx, ErrUnknownShell)
+			}
+
+			n := strings.ToLower(a.Name)
+
+			logger.Raw(strings.NewReplacer("{{ .Function }}", regexp.MustCompile(`[^a-z0-9]`).ReplaceAllString(n, "_"), "{{ .Name }}", n).Replace(s))
+
+			return nil
+		},
+	}
+}
+
+// complete returns the completions for the last word of words, which are the arguments after the App name.
+func (a App[T]) complete(ctx context.Context, words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+
+	current := words[len(words)-1]
+	words = words[:len(words)-1]
+
+	// Global flags
+	for len(words) > 0 && strings.HasPrefix(words[0], "-") {
+		if _, ok := globalFlagValues[words[0]]; ok {
+			if len(words) == 1 {
+				return filterPrefix(a.completeGlobalFlag(words[0], current), current)
+			}
+
+			words = words[1:]
+		}
+
+		words = words[1:]
+	}
+
+	if len(words) == 0 {
+		if strings.HasPrefix(current, "-") {
+			f := []string{"-n"}
+
+			for k := range globalFlagValues {
+				if a.NoParse && (k == "-c" || k == "-x") {
+					continue
+				}
+
+				f = append(f, k)
+			}
+
+			return filterPrefix(f, current)
+		}
+
+		return filterPrefix(append(commandNames(a.Commands), pluginNames(a.plugins())...), current)
+	}
+
+	// Commands
+	name, c, ok := findCommand(a.Commands, words[0])
+	if !ok {
+		return nil
+	}
+
+	words = words[1:]
+
+	for len(c.Commands) > 0 && len(words) > 0 {
+		n, s, ok := findCommand(c.Commands, words[0])
+		if !ok {
+			break
+		}
+
+		name = n
+		c = s
+		words = words[1:]
+	}
+
+	if len(c.Commands) > 0 && len(words) == 0 && !strings.HasPrefix(current, "-") {
+		return filterPrefix(commandNames(c.Commands), current)
+	}
+
+	positional := []string{name}
+
+	for i := 0; i < len(words); i++ {
+		if f, ok := c.Flags[strings.TrimLeft(words[i], "-")]; ok && strings.HasPrefix(words[i], "-") && !strings.Contains(words[i], "=") {
+			n := max(f.Arguments, 1)
+			if _, ok := f.Default.(bool); ok {
+				n = 0
+			}
+
+			for j := 0; j < n; j++ {
+				if i == len(words)-1 {
+					return nil
+				}
+
+				i++
+			}
+
+			continue
+		}
+
+		positional = append(positional, words[i])
+	}
+
+	if strings.HasPrefix(current, "-") && c.Flags != nil {
+		f := []string{}
+
+		for _, n := range c.Flags.names() {
+			f = append(f, "-"+n)
+		}
+
+		return filterPrefix(f, current)
+	}
+
+	if c.Complete != nil {
+		return filterPrefix(c.Complete(ctx, positional, current, a.Config), current)
+	}
+
+	return nil
+}
+
+func (a App[T]) completeGlobalFlag(flag, current string) []string {
+	switch flag {
+	case "-c":
+		f, _ := filepath.Glob(current + "*")
+
+		return f
+	case "-x":
+		if a.NoParse {
+			return nil
+		}
+
+		p := []string{}
+
+		for _, f := range configFields(nil, reflect.ValueOf(a.Config)) {
+			p = append(p, strings.Join(f.Path, "_")+"=")
+		}
+
+		return p
+	}
+
+	return globalFlagValues[flag]
+}
+
+func commandNames[T AppConfig[any]](commands map[string]Command[T]) []string {
+	n := []string{}
+
+	for k, v := range commands {
+		if v.Usage == "" {
+			continue
+		}
+
+		if v.Name != "" {
+			k = strings.Split(v.Name, " ")[0]
+		}
+
+		n = append(n, k)
+	}
+
+	return n
+}
+
+func filterPrefix(s []string, prefix string) []string {
+	out := []string{}
+
+	for i := range s {
+		if strings.HasPrefix(s[i], prefix) {
+			out = append(out, s[i])
+		}
+	}
+
+	sort.Strings(out)
+
+	return out
+}
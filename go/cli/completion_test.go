@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/logger"
+)
+
+func TestAppComplete(t *testing.T) {
+	var gotArgs []string
+
+	a := App[*C]{
+		Commands: map[string]Command[*C]{
+			"hidden": {},
+			"keys": {
+				Commands: map[string]Command[*C]{
+					"generate": {
+						Complete: func(ctx context.Context, args []string, current string, config *C) []string {
+							gotArgs = args
+
+							return []string{"a", "b", "c"}
+						},
+						Flags: Flags{
+							"bits": {
+								Default: 2048,
+							},
+							"v": {
+								Default: false,
+							},
+						},
+						Usage: "Generate a key",
+					},
+					"list": {
+						Name:  "ls",
+						Usage: "List keys",
+					},
+				},
+				Usage: "Manage keys",
+			},
+			"version": {
+				Usage: "Print version information",
+			},
+		},
+		Config: &C{
+			CLI: Config{
+				LogLevel: logger.LevelInfo,
+			},
+		},
+		Name: "App",
+	}
+
+	tests := map[string]struct {
+		inputNoParse bool
+		inputWords   []string
+		want         []string
+		wantArgs     []string
+	}{
+		"empty": {
+			want: []string{"keys", "version"},
+		},
+		"command prefix": {
+			inputWords: []string{"k"},
+			want:       []string{"keys"},
+		},
+		"global flags": {
+			inputWords: []string{"-"},
+			want:       []string{"-c", "-f", "-l", "-n", "-o", "-x"},
+		},
+		"global flags no parse": {
+			inputNoParse: true,
+			inputWords:   []string{"-"},
+			want:         []string{"-f", "-l", "-n", "-o"},
+		},
+		"global flag value": {
+			inputWords: []string{"-l", ""},
+			want:       []string{"debug", "error", "info", "none"},
+		},
+		"config keys": {
+			inputWords: []string{"-n", "-x", "CLI_log"},
+			want:       []string{"CLI_logFormat=", "CLI_logLevel="},
+		},
+		"config keys all": {
+			inputWords: []string{"-x", ""},
+			want:       []string{"CLI_configPath=", "CLI_logFormat=", "CLI_logLevel=", "CLI_noColor=", "CLI_outputFormat=", "Hide_Message=", "Other_Message=", "Show_Message="},
+		},
+		"config file": {
+			inputWords: []string{"-c", "testdata/"},
+			want:       []string{"testdata/config.json"},
+		},
+		"after global flags": {
+			inputWords: []string{"-l", "debug", "-n", "v"},
+			want:       []string{"version"},
+		},
+		"subcommands": {
+			inputWords: []string{"keys", ""},
+			want:       []string{"generate", "ls"},
+		},
+		"command flags": {
+			inputWords: []string{"keys", "generate", "-"},
+			want:       []string{"-bits", "-v"},
+		},
+		"command flag value": {
+			inputWords: []string{"keys", "generate", "-bits", ""},
+		},
+		"dynamic": {
+			inputWords: []string{"keys", "generate", "-bits", "1", "-v", "name", "b"},
+			want:       []string{"b"},
+			wantArgs:   []string{"generate", "name"},
+		},
+		"no completions": {
+			inputWords: []string{"version", ""},
+		},
+		"unknown": {
+			inputWords: []string{"unknown", ""},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotArgs = nil
+			a.NoParse = tc.inputNoParse
+
+			assert.Equal(t, a.complete(context.Background(), tc.inputWords), tc.want)
+			assert.Equal(t, gotArgs, tc.wantArgs)
+		})
+	}
+}
+
+func TestAppCompletion(t *testing.T) {
+	a := App[*C]{
+		Commands: map[string]Command[*C]{},
+		Config:   &C{},
+		Name:     "My-App",
+		NoParse:  true,
+	}
+
+	tests := map[string]struct {
+		args   []string
+		err    error
+		output string
+	}{
+		"bash": {
+			args:   []string{"bash"},
+			output: `COMPREPLY=($(my-app -l none completion __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))`,
+		},
+		"fish": {
+			args:   []string{"fish"},
+			output: `complete -c my-app -f -a '(__my_app_complete)'`,
+		},
+		"zsh": {
+			args:   []string{"zsh"},
+			output: "compdef _my_app my-app",
+		},
+		"complete": {
+			args:   []string{"__complete", "ver"},
+			output: "version\n",
+		},
+		"unknown": {
+			args: []string{"powershell"},
+			err:  ErrUnknownShell,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Args = append([]string{"app", "-n", "completion"}, tc.args...)
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+			logger.SetStd()
+
+			assert.HasErr(t, a.Run(), tc.err)
+			assert.Contains(t, logger.ReadStd(), tc.output)
+		})
+	}
+}
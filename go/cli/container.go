@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// ContainerRuntime is an enum for determining which runtime to use.
+type ContainerRuntime string
+
+// ContainerRuntime is an enum for determining which runtime to use.
+const (
+	ContainerRuntimeNone    ContainerRuntime = ""
+	ContainerRuntimeDocker  ContainerRuntime = "docker"
+	ContainerRuntimeNerdctl ContainerRuntime = "nerdctl"
+	ContainerRuntimePodman  ContainerRuntime = "podman"
+)
+
+// Container image pull policies for RunOpts.ContainerPull.
+const (
+	ContainerPullAlways  = "always"
+	ContainerPullMissing = "missing"
+	ContainerPullNever   = "never"
+)
+
+var ErrContainerNoImage = errors.New("container image or exec container is required")
+
+var ErrContainerNoRuntime = errors.New("no container runtime found")
+
+// ContainerBackend creates container commands for Config.Run.
+type ContainerBackend interface {
+	/* Command returns the command and arguments to run RunOpts in a new container, or exec into RunOpts.ContainerExec */
+	Command(opts RunOpts) (string, []string, error)
+
+	/* Runtime returns the ContainerRuntime of the backend */
+	Runtime() ContainerRuntime
+}
+
+// containerCLI is a ContainerBackend for runtimes with a docker compatible CLI.
+type containerCLI struct {
+	runtime ContainerRuntime
+}
+
+// NewContainerBackend returns a ContainerBackend for a ContainerRuntime.  Docker, nerdctl, and podman share the docker compatible CLI.  New containers are named using RunOpts.ContainerName, or by the runtime if it's empty.
+func NewContainerBackend(runtime ContainerRuntime) ContainerBackend {
+	return &containerCLI{
+		runtime: runtime,
+	}
+}
+
+func (c *containerCLI) Command(opts RunOpts) (string, []string, error) {
+	if opts.ContainerExec != "" {
+		return string(c.runtime), c.exec(opts), nil
+	}
+
+	if opts.ContainerImage == "" {
+		return "", nil, ErrContainerNoImage
+	}
+
+	return string(c.runtime), c.run(opts), nil
+}
+
+func (c *containerCLI) Runtime() ContainerRuntime {
+	return c.runtime
+}
+
+func (*containerCLI) exec(opts RunOpts) []string {
+	args := []string{
+		"exec",
+		"-i",
+	}
+
+	for i := range opts.Environment {
+		args = append(args, "-e"+opts.Environment[i])
+	}
+
+	for i := range opts.ContainerEnvFiles {
+		args = append(args, "--env-file", opts.ContainerEnvFiles[i])
+	}
+
+	if opts.ContainerPrivileged {
+		args = append(args, "--privileged")
+	}
+
+	if opts.ContainerUser != "" {
+		args = append(args, "-u", opts.ContainerUser)
+	}
+
+	if opts.ContainerWorkDir != "" {
+		args = append(args, "-w", opts.ContainerWorkDir)
+	}
+
+	args = append(args, opts.ContainerExec)
+
+	if opts.Command != "" {
+		args = append(args, opts.Command)
+	}
+
+	return append(args, opts.Args...)
+}
+
+func (*containerCLI) run(opts RunOpts) []string {
+	args := []string{
+		"run",
+		"-i",
+		"--rm",
+	}
+
+	if opts.ContainerName != "" {
+		args = append(args, "--name", opts.ContainerName)
+	}
+
+	for i := range opts.Environment {
+		args = append(args, "-e"+opts.Environment[i])
+	}
+
+	if opts.ContainerEntrypoint != "" {
+		args = append(args, "--entrypoint", opts.ContainerEntrypoint)
+	}
+
+	if opts.ContainerPrivileged {
+		args = append(args, "--privileged")
+	}
+
+	if opts.ContainerPull != "" {
+		args = append(args, "--pull", opts.ContainerPull)
+	}
+
+	if opts.ContainerUser != "" {
+		args = append(args, "-u", opts.ContainerUser)
+	}
+
+	for i := range opts.ContainerVolumes {
+		args = append(args, "-v", opts.ContainerVolumes[i])
+	}
+
+	if opts.ContainerWorkDir != "" {
+		args = append(args, "-w", opts.ContainerWorkDir)
+	}
+
+	if opts.ContainerCPUs != "" {
+		args = append(args, "--cpus", opts.ContainerCPUs)
+	}
+
+	for i := range opts.ContainerEnvFiles {
+		args = append(args, "--env-file", opts.ContainerEnvFiles[i])
+	}
+
+	for i := range opts.ContainerLabels {
+		args = append(args, "--label", opts.ContainerLabels[i])
+	}
+
+	if opts.ContainerMemory != "" {
+		args = append(args, "--memory", opts.ContainerMemory)
+	}
+
+	if opts.ContainerNetwork != "" {
+		args = append(args, "--network", opts.ContainerNetwork)
+	}
+
+	if opts.ContainerReadOnly {
+		args = append(args, "--read-only")
+	}
+
+	for i := range opts.ContainerTmpfs {
+		args = append(args, "--tmpfs", opts.ContainerTmpfs[i])
+	}
+
+	args = append(args, opts.ContainerImage)
+
+	if opts.Command != "" {
+		args = append(args, opts.Command)
+	}
+
+	return append(args, opts.Args...)
+}
+
+// SetContainerBackend sets the ContainerBackend used by Run, instead of detecting one from the PATH.
+func (c *Config) SetContainerBackend(backend ContainerBackend) {
+	c.container = backend
+}
+
+func getContainerRuntime() (ContainerRuntime, error) {
+	for _, r := range []ContainerRuntime{
+		ContainerRuntimePodman,
+		ContainerRuntimeDocker,
+		ContainerRuntimeNerdctl,
+	} {
+		if _, err := exec.LookPath(string(r)); err == nil {
+			return r, nil
+		}
+	}
+
+	return ContainerRuntimeNone, ErrContainerNoRuntime
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"sync"
+)
+
+// ContainerBackendMock is a ContainerBackend for testing.  It records the RunOpts passed to Command and returns Exec and Args, or Err.
+type ContainerBackendMock struct {
+	/* Args are the arguments returned by Command */
+	Args []string
+
+	/* ContainerRuntime is returned by Runtime */
+	ContainerRuntime ContainerRuntime
+
+	/* Err is returned by Command */
+	Err error
+
+	/* Exec is the command returned by Command */
+	Exec string
+
+	inputs []RunOpts
+	mutex  sync.Mutex
+}
+
+func (c *ContainerBackendMock) Command(opts RunOpts) (string, []string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.inputs = append(c.inputs, opts)
+
+	if c.Err != nil {
+		return "", nil, c.Err
+	}
+
+	return c.Exec, c.Args, nil
+}
+
+// Inputs returns the RunOpts passed to Command.
+func (c *ContainerBackendMock) Inputs() []RunOpts {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.inputs
+}
+
+func (c *ContainerBackendMock) Runtime() ContainerRuntime {
+	return c.ContainerRuntime
+}
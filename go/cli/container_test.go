@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/logger"
+)
+
+func TestContainerBackend(t *testing.T) {
+	tests := map[string]struct {
+		opts        RunOpts
+		runtime     ContainerRuntime
+		wantArgs    string
+		wantCommand string
+		wantErr     error
+	}{
+		"run": {
+			opts: RunOpts{
+				Args: []string{
+					"a",
+				},
+				Command:             "b",
+				ContainerCPUs:       "1.5",
+				ContainerEntrypoint: "/bin/sh",
+				ContainerEnvFiles: []string{
+					"a.env",
+				},
+				ContainerImage: "image",
+				ContainerLabels: []string{
+					"a=b",
+					"c=d",
+				},
+				ContainerMemory:   "512m",
+				ContainerName:     "name",
+				ContainerNetwork:  "none",
+				ContainerPull:     ContainerPullNever,
+				ContainerReadOnly: true,
+				ContainerTmpfs: []string{
+					"/tmp",
+				},
+				ContainerUser: "1000",
+				ContainerVolumes: []string{
+					"/a:/a",
+				},
+				ContainerWorkDir: "/a",
+				Environment: []string{
+					"A=b",
+				},
+			},
+			runtime:     ContainerRuntimeNerdctl,
+			wantArgs:    "run -i --rm --name name -eA=b --entrypoint /bin/sh --pull never -u 1000 -v /a:/a -w /a --cpus 1.5 --env-file a.env --label a=b --label c=d --memory 512m --network none --read-only --tmpfs /tmp image b a",
+			wantCommand: "nerdctl",
+		},
+		"exec": {
+			opts: RunOpts{
+				Args: []string{
+					"-c",
+					"true",
+				},
+				Command:             "sh",
+				ContainerExec:       "running",
+				ContainerImage:      "ignored",
+				ContainerPrivileged: true,
+				ContainerUser:       "root",
+				ContainerWorkDir:    "/a",
+				Environment: []string{
+					"A=b",
+				},
+			},
+			runtime:     ContainerRuntimeDocker,
+			wantArgs:    "exec -i -eA=b --privileged -u root -w /a running sh -c true",
+			wantCommand: "docker",
+		},
+		"no image": {
+			opts: RunOpts{
+				Command: "sh",
+			},
+			runtime: ContainerRuntimePodman,
+			wantErr: ErrContainerNoImage,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := NewContainerBackend(tc.runtime)
+			assert.Equal(t, b.Runtime(), tc.runtime)
+
+			c, args, err := b.Command(tc.opts)
+			assert.HasErr(t, err, tc.wantErr)
+			assert.Equal(t, c, tc.wantCommand)
+			assert.Equal(t, strings.Join(args, " "), tc.wantArgs)
+		})
+	}
+}
+
+func TestContainerBackendMock(t *testing.T) {
+	ctx := logger.UseTestLogger(t)
+
+	b := &ContainerBackendMock{
+		Args: []string{
+			"exec",
+			"app",
+			"ls",
+		},
+		ContainerRuntime: ContainerRuntimePodman,
+		Exec:             "runtime",
+	}
+
+	c := Config{}
+	c.RunMock()
+	c.SetContainerBackend(b)
+	c.RunMockExpect(RunMockExpectation{
+		ExecRegex: "^runtime exec app ls$",
+		Result: RunResult{
+			Stdout: "file",
+		},
+	})
+
+	out, err := c.Run(ctx, RunOpts{
+		Command:       "ls",
+		ContainerExec: "app",
+	})
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, out, "file")
+	assert.HasErr(t, c.RunMockVerify(), nil)
+	assert.Equal(t, b.Runtime(), ContainerRuntimePodman)
+
+	b.Err = ErrContainerNoImage
+
+	_, err = c.Run(ctx, RunOpts{
+		Command:        "ls",
+		ContainerImage: "image",
+		NoErrorLog:     true,
+	})
+	assert.HasErr(t, err, ErrContainerNoImage)
+
+	i := b.Inputs()
+	assert.Equal(t, len(i), 2)
+	assert.Equal(t, i[0].ContainerExec, "app")
+	assert.Equal(t, i[1].ContainerImage, "image")
+}
@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/candiddev/shared/go/errs"
+	"github.com/candiddev/shared/go/logger"
+	"golang.org/x/sys/unix"
+)
+
+var (
+	ErrDaemonNotify          = errors.New("error sending systemd notification")
+	ErrDaemonPIDFile         = errors.New("error writing PID file")
+	ErrDaemonShutdownHook    = errors.New("error running shutdown hook")
+	ErrDaemonShutdownTimeout = errors.New("daemon shutdown timed out")
+)
+
+// DaemonShutdownTimeoutDefault is the default Daemon.ShutdownTimeout.
+const DaemonShutdownTimeoutDefault = 30 * time.Second
+
+// Daemon manages the lifecycle of a long-running command, like a server:
+//
+//   - The context passed to Run is cancelled on SIGINT or SIGTERM.  A second SIGINT or SIGTERM stops waiting for the shutdown.
+//   - Config is reloaded on SIGHUP by parsing a new config from NewConfig using AppConfig.Parse with the same config arguments as App.Run, and replacing Config if it succeeds.
+//   - The process ID is written to PIDFile while running.
+//   - systemd is notified of readiness, reloading, and stopping, and watchdog keep-alives are sent, when NOTIFY_SOCKET is set.
+type Daemon[T AppConfig[any]] struct {
+	/* Config is replaced by a new config when the daemon receives SIGHUP.  Use GetConfig to read it while the daemon is running */
+	Config T
+
+	/* NewConfig returns a config with the same default values as Config before it was parsed, to parse when reloading.  Reloading fails if it's nil */
+	NewConfig func() T
+
+	/* NotifyReadyManual disables notifying systemd of readiness when Run starts.  Readiness should be sent using DaemonNotify("READY=1") instead */
+	NotifyReadyManual bool
+
+	/* OnReload is called with the new config after it's parsed successfully, and should be used to apply the new values.  If it returns an error, Config isn't replaced */
+	OnReload func(ctx context.Context, config T) errs.Err
+
+	/* PIDFile is a path to write the process ID to while the daemon is running */
+	PIDFile string
+
+	/* ShutdownTimeout is the maximum duration to wait for Run to return and the shutdown hooks to complete, defaults to DaemonShutdownTimeoutDefault */
+	ShutdownTimeout time.Duration
+
+	hooks []daemonHook
+	mutex sync.RWMutex
+}
+
+type daemonHook struct {
+	name string
+	run  func(ctx context.Context) errs.Err
+}
+
+// GetConfig returns the current Config.
+func (d *Daemon[T]) GetConfig() T {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	return d.Config
+}
+
+// OnShutdown adds a hook to run after the context passed to Run is cancelled and Run returns.  Hooks run in the reverse order they were added, like defer, so components started last are stopped first.
+func (d *Daemon[T]) OnShutdown(name string, f func(ctx context.Context) errs.Err) {
+	d.hooks = append(d.hooks, daemonHook{
+		name: name,
+		run:  f,
+	})
+}
+
+// Run runs f until it returns, ctx is cancelled, or the daemon receives SIGINT or SIGTERM, and then runs the shutdown hooks.
+func (d *Daemon[T]) Run(ctx context.Context, f func(ctx context.Context) errs.Err) errs.Err { //nolint:gocognit
+	if d.PIDFile != "" {
+		if err := os.WriteFile(d.PIDFile, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil { //nolint:gosec
+			return logger.Error(ctx, errs.ErrReceiver.Wrap(ErrDaemonPIDFile, err))
+		}
+
+		defer os.Remove(d.PIDFile)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
+
+	defer signal.Stop(signals)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan errs.Err, 1)
+
+	go func() {
+		done <- f(runCtx)
+	}()
+
+	if !d.NotifyReadyManual {
+		daemonNotify(ctx, "READY=1")
+	}
+
+	var watchdog <-chan time.Time
+
+	if i := daemonWatchdogInterval(); i > 0 {
+		t := time.NewTicker(i / 2)
+		defer t.Stop()
+
+		watchdog = t.C
+	}
+
+	var err errs.Err
+
+	running := true
+
+	for running {
+		select {
+		case <-ctx.Done():
+			running = false
+		case err = <-done:
+			done = nil
+			running = false
+		case s := <-signals:
+			if s == syscall.SIGHUP {
+				d.reload(ctx)
+
+				continue
+			}
+
+			logger.Info(ctx, fmt.Sprintf("Received %s, shutting down", s))
+
+			running = false
+		case <-watchdog:
+			daemonNotify(ctx, "WATCHDOG=1")
+		}
+	}
+
+	daemonNotify(ctx, "STOPPING=1")
+	cancel()
+
+	timeout := d.ShutdownTimeout
+	if timeout == 0 {
+		timeout = DaemonShutdownTimeoutDefault
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer shutdownCancel()
+
+	hooks := d.hooks
+	stopped := make(chan errs.Err, 1)
+
+	go func() {
+		e := err
+
+		if done != nil {
+			e = <-done
+		}
+
+		for i := len(hooks) - 1; i >= 0; i-- {
+			logger.Debug(ctx, "Running shutdown hook "+hooks[i].name)
+
+			if err := hooks[i].run(shutdownCtx); err != nil {
+				logger.Error(ctx, errs.ErrReceiver.Wrap(ErrDaemonShutdownHook, fmt.Errorf("%s: %w", hooks[i].name, err))) //nolint:errcheck
+			}
+		}
+
+		stopped <- e
+	}()
+
+	waiting := true
+
+	for waiting {
+		select {
+		case err := <-stopped:
+			return err
+		case <-shutdownCtx.Done():
+			waiting = false
+		case s := <-signals:
+			// SIGHUP is sent for routine reloads, like log rotation, so only a second SIGINT or SIGTERM stops waiting.
+			if s == syscall.SIGHUP {
+				continue
+			}
+
+			logger.Info(ctx, fmt.Sprintf("Received %s, stopping shutdown", s))
+
+			waiting = false
+		}
+	}
+
+	return logger.Error(ctx, errs.ErrReceiver.Wrap(ErrDaemonShutdownTimeout))
+}
+
+func (d *Daemon[T]) reload(ctx context.Context) {
+	logger.Info(ctx, "Received SIGHUP, reloading config")
+
+	daemonNotify(ctx, fmt.Sprintf("RELOADING=1\nMONOTONIC_USEC=%d", daemonMonotonic()))
+
+	defer daemonNotify(ctx, "READY=1")
+
+	c, err := reparseConfig(ctx, d.GetConfig(), d.NewConfig)
+	if err != nil {
+		logger.Error(ctx, err) //nolint:errcheck
+
+		return
+	}
+
+	if d.OnReload != nil {
+		if err := d.OnReload(ctx, c); err != nil {
+			logger.Error(ctx, err) //nolint:errcheck
+
+			return
+		}
+	}
+
+	d.mutex.Lock()
+	d.Config = c
+	d.mutex.Unlock()
+}
+
+// DaemonNotify sends a state to systemd using the socket in NOTIFY_SOCKET, like "READY=1" or "STATUS=Running".  It does nothing if NOTIFY_SOCKET isn't set.
+func DaemonNotify(state string) error {
+	s := os.Getenv("NOTIFY_SOCKET")
+	if s == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(s, "@") {
+		s = "\x00" + s[1:]
+	}
+
+	c, err := net.DialUnix("unixgram", nil, &net.UnixAddr{
+		Name: s,
+		Net:  "unixgram",
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDaemonNotify, err)
+	}
+
+	defer c.Close()
+
+	if _, err := c.Write([]byte(state)); err != nil {
+		return fmt.Errorf("%w: %w", ErrDaemonNotify, err)
+	}
+
+	return nil
+}
+
+// daemonMonotonic returns CLOCK_MONOTONIC in microseconds, used by systemd to match reloads.
+func daemonMonotonic() int64 {
+	var t unix.Timespec
+
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &t); err != nil {
+		return 0
+	}
+
+	return t.Nano() / 1000
+}
+
+func daemonNotify(ctx context.Context, state string) {
+	if err := DaemonNotify(state); err != nil {
+		logger.Error(ctx, errs.ErrReceiver.Wrap(err)) //nolint:errcheck
+	}
+}
+
+// daemonWatchdogInterval returns the systemd watchdog interval from WATCHDOG_USEC, if it applies to this process.
+func daemonWatchdogInterval() time.Duration {
+	if p := os.Getenv("WATCHDOG_PID"); p != "" && p != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	u, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || u <= 0 {
+		return 0
+	}
+
+	return time.Duration(u) * time.Microsecond
+}
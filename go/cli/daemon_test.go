@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/errs"
+	"github.com/candiddev/shared/go/logger"
+)
+
+type daemonConfig struct {
+	CLI    Config
+	Value  string
+	fail   bool
+	mutex  sync.Mutex
+	parses [][]string
+}
+
+func (d *daemonConfig) CLIConfig() *Config {
+	return &d.CLI
+}
+
+func (d *daemonConfig) Parse(_ context.Context, configArgs []string) errs.Err {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.parses = append(d.parses, configArgs)
+
+	if d.fail {
+		return errs.ErrReceiver.Wrap(errors.New("parse failed"))
+	}
+
+	d.Value = "parsed"
+
+	return nil
+}
+
+func TestDaemon(t *testing.T) {
+	ctx := logger.UseTestLogger(t)
+
+	dir := t.TempDir()
+	notify := filepath.Join(dir, "notify")
+
+	l, err := net.ListenUnixgram("unixgram", &net.UnixAddr{
+		Name: notify,
+		Net:  "unixgram",
+	})
+	assert.HasErr(t, err, nil)
+
+	defer l.Close()
+
+	t.Setenv("NOTIFY_SOCKET", notify)
+	t.Setenv("WATCHDOG_PID", "")
+	t.Setenv("WATCHDOG_USEC", "20000")
+
+	messages := make(chan string, 100)
+
+	go func() {
+		b := make([]byte, 1024)
+
+		for {
+			n, err := l.Read(b)
+			if err != nil {
+				return
+			}
+
+			messages <- string(b[:n])
+		}
+	}()
+
+	c := &daemonConfig{
+		Value: "old",
+	}
+	c.CLI.ConfigPath = "app.jsonnet"
+	c.CLI.configArgs = []string{
+		"a=b",
+	}
+
+	reloaded := make(chan struct{}, 1)
+	order := []string{}
+	configs := 0
+
+	d := Daemon[*daemonConfig]{
+		Config: c,
+		NewConfig: func() *daemonConfig {
+			configs++
+
+			return &daemonConfig{
+				fail: configs > 1,
+			}
+		},
+		OnReload: func(_ context.Context, config *daemonConfig) errs.Err {
+			assert.Equal(t, config.Value, "parsed")
+
+			reloaded <- struct{}{}
+
+			return nil
+		},
+		PIDFile: filepath.Join(dir, "pid"),
+	}
+	d.OnShutdown("first", func(ctx context.Context) errs.Err {
+		order = append(order, "first")
+
+		return nil
+	})
+	d.OnShutdown("second", func(ctx context.Context) errs.Err {
+		order = append(order, "second")
+
+		return nil
+	})
+
+	started := make(chan struct{})
+	stopped := false
+
+	go func() {
+		<-started
+
+		b, err := os.ReadFile(d.PIDFile)
+		assert.HasErr(t, err, nil)
+		assert.Equal(t, strings.TrimSpace(string(b)), strconv.Itoa(os.Getpid()))
+
+		syscall.Kill(os.Getpid(), syscall.SIGHUP)
+		<-reloaded
+		time.Sleep(50 * time.Millisecond)
+
+		// A failed reload keeps the current config.
+		syscall.Kill(os.Getpid(), syscall.SIGHUP)
+		time.Sleep(50 * time.Millisecond)
+		syscall.Kill(os.Getpid(), syscall.SIGTERM)
+	}()
+
+	assert.HasErr(t, d.Run(ctx, func(ctx context.Context) errs.Err {
+		close(started)
+		<-ctx.Done()
+		stopped = true
+
+		return nil
+	}), nil)
+
+	assert.Equal(t, stopped, true)
+	assert.Equal(t, order, []string{"second", "first"})
+	assert.Equal(t, c.parses, nil)
+	assert.Equal(t, c.Value, "old")
+	assert.Equal(t, configs, 2)
+
+	r := d.GetConfig()
+	assert.Equal(t, r.Value, "parsed")
+	assert.Equal(t, r.CLI.ConfigPath, "app.jsonnet")
+	assert.Equal(t, r.parses, [][]string{{"a=b"}})
+
+	_, err = os.Stat(d.PIDFile)
+	assert.Equal(t, os.IsNotExist(err), true)
+
+	m := []string{}
+
+	for len(m) == 0 || m[len(m)-1] != "STOPPING=1" {
+		select {
+		case s := <-messages:
+			m = append(m, s)
+		case <-time.After(time.Second):
+			t.Fatal("missing STOPPING=1")
+		}
+	}
+
+	s := strings.Join(m, "\n")
+	assert.Equal(t, m[0], "READY=1")
+	assert.Contains(t, s, "RELOADING=1\nMONOTONIC_USEC=")
+	assert.Contains(t, s, "WATCHDOG=1")
+
+	// Shutdown timeout
+	d = Daemon[*daemonConfig]{
+		Config:          c,
+		ShutdownTimeout: 10 * time.Millisecond,
+	}
+	d.OnShutdown("slow", func(ctx context.Context) errs.Err {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+
+		return nil
+	})
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	assert.HasErr(t, d.Run(cctx, func(ctx context.Context) errs.Err {
+		return nil
+	}), ErrDaemonShutdownTimeout)
+
+	// SIGHUP doesn't stop the shutdown
+	d = Daemon[*daemonConfig]{
+		Config:          c,
+		ShutdownTimeout: time.Second,
+	}
+	hooked := false
+	d.OnShutdown("hup", func(ctx context.Context) errs.Err {
+		syscall.Kill(os.Getpid(), syscall.SIGHUP)
+		time.Sleep(50 * time.Millisecond)
+		hooked = true
+
+		return nil
+	})
+
+	cctx, cancel = context.WithCancel(ctx)
+	cancel()
+
+	assert.HasErr(t, d.Run(cctx, func(ctx context.Context) errs.Err {
+		return nil
+	}), nil)
+	assert.Equal(t, hooked, true)
+
+	// Run returns
+	d = Daemon[*daemonConfig]{
+		Config: c,
+	}
+
+	assert.HasErr(t, d.Run(ctx, func(ctx context.Context) errs.Err {
+		return errs.ErrSenderBadRequest
+	}), errs.ErrSenderBadRequest)
+}
@@ -0,0 +1,411 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/candiddev/shared/go/errs"
+	"github.com/candiddev/shared/go/logger"
+)
+
+var ErrUnknownDocsFormat = errs.ErrSenderBadRequest.Wrap(errors.New("unknown docs format, must be man or markdown"))
+
+type configField struct {
+	Default any
+	Path    []string
+	Type    string
+}
+
+type docCommand struct {
+	Flags []docFlag
+	Name  string
+	Title string
+	Usage string
+}
+
+type docFlag struct {
+	Default string
+	Name    string
+	Usage   string
+}
+
+func (a App[T]) docsCommand() Command[T] {
+	return Command[T]{
+		ArgumentsRequired: []string{
+			"man|markdown",
+		},
+		Run: func(ctx context.Context, args []string, _ T) errs.Err {
+			switch args[1] {
+			case "man":
+				logger.Raw(a.ManPage())
+			case "markdown":
+				logger.Raw(a.Markdown())
+			default:
+				return logger.Error(ctx, ErrUnknownDocsFormat)
+			}
+
+			return nil
+		},
+	}
+}
+
+// ManPage renders the App commands, flags, and configuration as a man page using the man(7) macros.  Configuration defaults are the current values of Config.
+func (a App[T]) ManPage() string {
+	n := strings.ToLower(a.Name)
+	e := strings.ToUpper(a.Name) + "_"
+	fs := a.docsFlagSet()
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, ".TH %s 1 %q %q %q\n", strings.ToUpper(manEscape(n)), BuildDate, manEscape(a.Name+" "+BuildVersion), manEscape(a.Name+" Manual"))
+	fmt.Fprintf(&b, ".SH NAME\n%s \\- %s\n", manEscape(n), manEscape(a.Description))
+	fmt.Fprintf(&b, ".SH SYNOPSIS\n.B %s\n[flags] [command]\n", manEscape(n))
+	fmt.Fprintf(&b, ".SH DESCRIPTION\n%s\n", manEscape(a.Description))
+
+	if c := a.docCommands(nil, a.Commands); len(c) > 0 {
+		b.WriteString(".SH COMMANDS\n")
+
+		for _, c := range c {
+			fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", manEscape(c.Name), manEscape(c.Usage))
+
+			if len(c.Flags) > 0 {
+				b.WriteString(".RS\n")
+				manFlags(&b, c.Flags)
+				b.WriteString(".RE\n")
+			}
+		}
+	}
+
+	b.WriteString(".SH OPTIONS\n")
+	manFlags(&b, docFlags(fs))
+
+	if !a.NoParse {
+		b.WriteString(".SH CONFIGURATION\n")
+		fmt.Fprintf(&b, "Configuration values can be set using a JSON/Jsonnet file (\\-c), environment variables, or \\-x key=value, where key is the environment variable without the %s prefix.\n", manEscape(e))
+
+		for _, f := range a.docConfigFields() {
+			fmt.Fprintf(&b, ".TP\n.B %s\nType: %s, default: %s, environment variable: %s\n", manEscape(strings.Join(f.Path, ".")), f.Type, manEscape(docDefault(f.Default)), manEscape(e+strings.Join(f.Path, "_")))
+		}
+
+		fmt.Fprintf(&b, ".SH ENVIRONMENT\n.TP\n.B %sconfig\nJsonnet configuration, rendered after configuration files.\n", manEscape(e))
+	}
+
+	return b.String()
+}
+
+// Markdown renders the App commands, flags, and configuration as a Markdown reference page.  Configuration defaults are the current values of Config.
+func (a App[T]) Markdown() string {
+	n := strings.ToLower(a.Name)
+	e := strings.ToUpper(a.Name) + "_"
+	fs := a.docsFlagSet()
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n%s\n\n## Usage\n\n```\n%s [flags] [command]\n```\n", n, a.Description, n)
+
+	if c := a.docCommands(nil, a.Commands); len(c) > 0 {
+		b.WriteString("\n## Commands\n")
+
+		for _, c := range c {
+			fmt.Fprintf(&b, "\n### %s\n\n`%s %s`\n\n%s\n", c.Title, n, c.Name, c.Usage)
+
+			if len(c.Flags) > 0 {
+				b.WriteString("\n")
+				markdownFlags(&b, c.Flags)
+			}
+		}
+	}
+
+	b.WriteString("\n## Global Flags\n\n")
+	markdownFlags(&b, docFlags(fs))
+
+	if !a.NoParse {
+		fmt.Fprintf(&b, "\n## Configuration\n\nConfiguration values can be set using a JSON/Jsonnet file (`-c`), environment variables, or `-x key=value`, where key is the environment variable without the `%s` prefix.  Jsonnet configuration can also be provided using the `%sconfig` environment variable.\n\n", e, e)
+		b.WriteString("| Key | Type | Default | Environment Variable |\n|-----|------|---------|----------------------|\n")
+
+		for _, f := range a.docConfigFields() {
+			d := docDefault(f.Default)
+			if d != "" {
+				d = "`" + markdownEscape(d) + "`"
+			}
+
+			fmt.Fprintf(&b, "| `%s` | %s | %s | `%s` |\n", strings.Join(f.Path, "."), f.Type, d, e+strings.Join(f.Path, "_"))
+		}
+	}
+
+	return b.String()
+}
+
+// configFields returns the leaf fields of a config value, using the JSON key names as the path.
+func configFields(path []string, v reflect.Value) []configField {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			if v.Kind() == reflect.Interface {
+				if len(path) == 0 {
+					return nil
+				}
+
+				return []configField{
+					{
+						Path: path,
+						Type: "any",
+					},
+				}
+			}
+
+			v = reflect.New(v.Type().Elem())
+		}
+
+		v = v.Elem()
+	}
+
+	f := []configField{}
+
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+
+		for _, k := range keys {
+			f = append(f, configFields(docPath(path, k.String()), v.MapIndex(k))...)
+		}
+	case reflect.Struct:
+		t := v.Type()
+
+		for i := 0; i < t.NumField(); i++ {
+			s := t.Field(i)
+			if !s.IsExported() {
+				continue
+			}
+
+			n, _, _ := strings.Cut(s.Tag.Get("json"), ",")
+			if n == "-" {
+				continue
+			}
+
+			if n == "" {
+				if s.Anonymous {
+					f = append(f, configFields(path, v.Field(i))...)
+
+					continue
+				}
+
+				n = s.Name
+			}
+
+			f = append(f, configFields(docPath(path, n), v.Field(i))...)
+		}
+	}
+
+	if len(f) > 0 || len(path) == 0 {
+		return f
+	}
+
+	c := configField{
+		Path: path,
+		Type: docType(v.Kind()),
+	}
+
+	if v.CanInterface() {
+		c.Default = v.Interface()
+	}
+
+	return []configField{
+		c,
+	}
+}
+
+// docConfigFields returns the configFields of Config, removing the defaults of HideConfigFields.
+func (a App[T]) docConfigFields() []configField {
+	f := configFields(nil, reflect.ValueOf(a.Config))
+
+	for i := range f {
+		p := strings.Join(f[i].Path, ".")
+
+		for _, h := range a.HideConfigFields {
+			if p == h || strings.HasPrefix(p, h+".") {
+				f[i].Default = nil
+			}
+		}
+	}
+
+	return f
+}
+
+// docCommands returns the visible commands, including subcommands, sorted by name.
+func (a App[T]) docCommands(path []string, commands map[string]Command[T]) []docCommand {
+	names := []string{}
+
+	for k := range commands {
+		if commands[k].Usage != "" {
+			names = append(names, k)
+		}
+	}
+
+	sort.Strings(names)
+
+	out := []docCommand{}
+
+	for _, k := range names {
+		c := commands[k]
+
+		n := k
+		if c.Name != "" {
+			n = c.Name
+		}
+
+		p := docPath(path, strings.Split(n, " ")[0])
+		u := append(append([]string{}, path...), n)
+
+		if len(c.Commands) > 0 {
+			u = append(u, "[command]")
+		} else {
+			if c.Flags != nil {
+				u = append(u, "[flags]")
+			}
+
+			for _, arg := range c.ArgumentsRequired {
+				u = append(u, fmt.Sprintf("[%s]", arg))
+			}
+
+			for _, arg := range c.ArgumentsOptional {
+				u = append(u, fmt.Sprintf("[%s]", arg))
+			}
+		}
+
+		d := docCommand{
+			Name:  strings.Join(u, " "),
+			Title: strings.Join(p, " "),
+			Usage: c.Usage,
+		}
+
+		if c.Flags != nil {
+			if fs, err := c.Flags.flagSet(k); err == nil {
+				d.Flags = docFlags(fs)
+			}
+		}
+
+		out = append(out, d)
+		out = append(out, a.docCommands(p, c.Commands)...)
+	}
+
+	return out
+}
+
+// docsFlagSet returns a flag.FlagSet containing the global flags, using a copy of the CLI config so the App isn't modified.
+func (a App[T]) docsFlagSet() *flag.FlagSet {
+	c := *a.Config.CLIConfig()
+
+	fs := flag.NewFlagSet(a.Name, flag.ContinueOnError)
+	a.setupFlags(fs, &c, &ConfigArgs{})
+
+	return fs
+}
+
+func docDefault(v any) string {
+	if v == nil {
+		return ""
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(b)
+}
+
+func docFlags(fs *flag.FlagSet) []docFlag {
+	f := []docFlag{}
+
+	fs.VisitAll(func(fl *flag.Flag) {
+		n, u := flag.UnquoteUsage(fl)
+
+		d := docFlag{
+			Name:  strings.TrimSpace("-" + fl.Name + " " + n),
+			Usage: u,
+		}
+
+		if fl.DefValue != "" && fl.DefValue != "0" && fl.DefValue != "false" {
+			d.Default = fl.DefValue
+		}
+
+		f = append(f, d)
+	})
+
+	return f
+}
+
+func docPath(path []string, name string) []string {
+	return append(append([]string{}, path...), name)
+}
+
+func docType(k reflect.Kind) string {
+	switch k { //nolint:exhaustive
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Array, reflect.Slice:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.String:
+		return "string"
+	}
+
+	return "any"
+}
+
+func manEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+
+	return s
+}
+
+func manFlags(b *strings.Builder, flags []docFlag) {
+	for _, f := range flags {
+		u := f.Usage
+
+		if f.Default != "" {
+			u += fmt.Sprintf(" (default %s)", f.Default)
+		}
+
+		fmt.Fprintf(b, ".TP\n.B %s\n%s\n", manEscape(f.Name), manEscape(u))
+	}
+}
+
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+func markdownFlags(b *strings.Builder, flags []docFlag) {
+	b.WriteString("| Flag | Default | Usage |\n|------|---------|-------|\n")
+
+	for _, f := range flags {
+		d := ""
+		if f.Default != "" {
+			d = "`" + markdownEscape(f.Default) + "`"
+		}
+
+		fmt.Fprintf(b, "| `%s` | %s | %s |\n", f.Name, d, markdownEscape(f.Usage))
+	}
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/logger"
+)
+
+func TestAppDocs(t *testing.T) {
+	a := App[*C]{
+		Commands: map[string]Command[*C]{
+			"keys": {
+				Commands: map[string]Command[*C]{
+					"generate": {
+						ArgumentsRequired: []string{
+							"name",
+						},
+						Flags: Flags{
+							"bits": {
+								Default: 2048,
+								Usage:   "Key `size`",
+							},
+						},
+						Usage: "Generate a key",
+					},
+				},
+				Usage: "Manage keys",
+			},
+		},
+		Config: &C{
+			Hide: msg{
+				Message: "secret",
+			},
+			Show: msg{
+				Message: "a|b",
+			},
+		},
+		Description: "Does things",
+		HideConfigFields: []string{
+			"Hide",
+		},
+		Name: "App",
+	}
+
+	tests := map[string]struct {
+		args   []string
+		err    error
+		output []string
+	}{
+		"man": {
+			args: []string{"man"},
+			output: []string{
+				`.TH APP 1 "`,
+				".SH NAME\napp \\- Does things\n",
+				".TP\n.B keys [command]\nManage keys\n.TP\n.B keys generate [flags] [name]\nGenerate a key\n.RS\n.TP\n.B \\-bits size\nKey size (default 2048)\n.RE\n",
+				".TP\n.B \\-c string\nPath to JSON/Jsonnet configuration files separated by a comma (default app.jsonnet)\n",
+				".TP\n.B CLI.logLevel\nType: string, default: \"\", environment variable: APP_CLI_logLevel\n",
+				".TP\n.B Hide.Message\nType: string, default: , environment variable: APP_Hide_Message\n",
+				".B APP_config\n",
+			},
+		},
+		"markdown": {
+			args: []string{"markdown"},
+			output: []string{
+				"# app\n\nDoes things\n",
+				"### keys generate\n\n`app keys generate [flags] [name]`\n\nGenerate a key\n\n| Flag | Default | Usage |\n|------|---------|-------|\n| `-bits size` | `2048` | Key size |\n",
+				"| `-n` |  | Disable colored logging |\n",
+				"| `CLI.noColor` | boolean | `false` | `APP_CLI_noColor` |\n",
+				"| `Hide.Message` | string |  | `APP_Hide_Message` |\n",
+				"| `Show.Message` | string | `\"a\\|b\"` | `APP_Show_Message` |\n",
+			},
+		},
+		"unknown": {
+			args: []string{"html"},
+			err:  ErrUnknownDocsFormat,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Args = append([]string{"app", "docs"}, tc.args...)
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+			logger.SetStd()
+
+			assert.HasErr(t, a.Run(), tc.err)
+
+			out := logger.ReadStd()
+
+			for _, o := range tc.output {
+				assert.Contains(t, out, o)
+			}
+		})
+	}
+
+	// Docs don't modify the App.
+	a.Config.CLI.ConfigPath = "config.jsonnet"
+	delete(a.Commands, "version")
+
+	assert.Equal(t, a.docsFlagSet().Lookup("c").DefValue, "app.jsonnet")
+	assert.Equal(t, a.Config.CLI.ConfigPath, "config.jsonnet")
+
+	_, ok := a.Commands["version"]
+	assert.Equal(t, ok, false)
+}
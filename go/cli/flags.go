@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/candiddev/shared/go/errs"
+)
+
+var ErrInvalidFlag = errs.ErrSenderBadRequest.Wrap(errors.New("invalid flag"))
+
+// Flag is a typed command flag.
+type Flag struct {
+	/* Arguments is the number of values a flag takes, like -arg name value.  Flags with more than one argument can be provided multiple times, and GetFlag returns a [][]string of the values */
+	Arguments int
+
+	/* Default value, the type of which determines the flag type: bool, float64, int, string, time.Duration, or []string.  Defaults to string. */
+	Default any
+
+	/* Required flags must be set on the command line */
+	Required bool
+
+	/* Usage information.  A `backquoted` word is used as the value name, like the flag package */
+	Usage string
+}
+
+// Flags are command flags, keyed by the flag name without a dash.
+type Flags map[string]Flag
+
+type flagsKey string
+
+const ctxFlags flagsKey = "flags"
+
+type flagValues map[string]any
+
+type flagStrings []string
+
+func (f *flagStrings) Get() any {
+	return []string(*f)
+}
+
+func (f *flagStrings) Set(value string) error {
+	*f = append(*f, value)
+
+	return nil
+}
+
+func (f *flagStrings) String() string {
+	if f == nil {
+		return ""
+	}
+
+	return strings.Join(*f, ",")
+}
+
+// GetFlag returns the value of a command flag from the context passed to Command.Run.  Returns the zero value of V if the flag doesn't exist or is a different type.
+func GetFlag[V any](ctx context.Context, name string) V {
+	var v V
+
+	f, ok := ctx.Value(ctxFlags).(flagValues)
+	if !ok {
+		return v
+	}
+
+	if r, ok := f[name].(V); ok {
+		return r
+	}
+
+	return v
+}
+
+// flagSet creates a flag.FlagSet from Flags.
+func (f Flags) flagSet(name string) (*flag.FlagSet, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	for _, k := range f.names() {
+		v := f[k]
+
+		u := v.Usage
+
+		if v.Required {
+			u += " (required)"
+		}
+
+		if v.Arguments > 1 {
+			fs.Var(&flagStrings{}, k, u)
+
+			continue
+		}
+
+		switch d := v.Default.(type) {
+		case nil:
+			fs.String(k, "", u)
+		case bool:
+			fs.Bool(k, d, u)
+		case float64:
+			fs.Float64(k, d, u)
+		case int:
+			fs.Int(k, d, u)
+		case string:
+			fs.String(k, d, u)
+		case time.Duration:
+			fs.Duration(k, d, u)
+		case []string:
+			s := flagStrings(append([]string{}, d...))
+			fs.Var(&s, k, u)
+		default:
+			return nil, fmt.Errorf("flag %s has unsupported type %T", k, v.Default)
+		}
+	}
+
+	return fs, nil
+}
+
+func (f Flags) names() []string {
+	n := make([]string, 0, len(f))
+
+	for k := range f {
+		n = append(n, k)
+	}
+
+	sort.Strings(n)
+
+	return n
+}
+
+// parse parses flags anywhere within args and returns the flag values and positional arguments.  Arguments after "--" are always positional.
+func (f Flags) parse(name string, args []string) (flagValues, []string, error) {
+	fs, err := f.flagSet(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args, values, err := f.parseArguments(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	positional := []string{}
+
+	for len(args) > 0 {
+		if err := fs.Parse(args); err != nil {
+			return nil, nil, err
+		}
+
+		rest := fs.Args()
+
+		if c := len(args) - len(rest); c > 0 && args[c-1] == "--" {
+			positional = append(positional, rest...)
+
+			break
+		}
+
+		if len(rest) == 0 {
+			break
+		}
+
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+
+	set := map[string]bool{}
+
+	fs.Visit(func(fl *flag.Flag) {
+		set[fl.Name] = true
+	})
+
+	for k, v := range f {
+		if v.Arguments > 1 {
+			if v.Required && len(values[k].([][]string)) == 0 { //nolint:forcetypeassert
+				return nil, nil, fmt.Errorf("flag -%s is required", k)
+			}
+
+			continue
+		}
+
+		if v.Required && !set[k] {
+			return nil, nil, fmt.Errorf("flag -%s is required", k)
+		}
+
+		if g, ok := fs.Lookup(k).Value.(flag.Getter); ok {
+			values[k] = g.Get()
+		}
+	}
+
+	return values, positional, nil
+}
+
+// parseArguments removes flags with multiple Arguments from args, since the flag package only supports one value, and returns the remaining args and the flag values.
+func (f Flags) parseArguments(args []string) ([]string, flagValues, error) {
+	out := []string{}
+	values := flagValues{}
+
+	for k, v := range f {
+		if v.Arguments > 1 {
+			values[k] = [][]string{}
+		}
+	}
+
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			out = append(out, args[i:]...)
+
+			break
+		}
+
+		if n := strings.TrimLeft(args[i], "-"); n != args[i] {
+			if v, ok := f[n]; ok && v.Arguments > 1 {
+				if i+v.Arguments >= len(args) {
+					return nil, nil, fmt.Errorf("flag -%s requires %d arguments", n, v.Arguments)
+				}
+
+				values[n] = append(values[n].([][]string), args[i+1:i+1+v.Arguments]) //nolint:forcetypeassert
+				i += v.Arguments
+
+				continue
+			}
+		}
+
+		out = append(out, args[i])
+	}
+
+	return out, values, nil
+}
+
+// printDefaults writes the usage of Flags to w.
+func (f Flags) printDefaults(w io.Writer) {
+	fs, err := f.flagSet("")
+	if err != nil {
+		return
+	}
+
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+}
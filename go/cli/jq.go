@@ -1,77 +1,363 @@
 package cli
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"strconv"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/candiddev/shared/go/errs"
 	"github.com/candiddev/shared/go/logger"
 	"github.com/itchyny/gojq"
+	"sigs.k8s.io/yaml"
 )
 
+var ErrJQExitStatus = errs.ErrSenderBadRequest.Wrap(errors.New("last output was false, null, or missing"))
+
 var errJQ = errors.New("error querying JSON")
 
-func jq[T AppConfig[any]](ctx context.Context, args []string, _ T) errs.Err {
-	s := "."
-	raw := false
+const jqUsage = "Query JSON from stdin or files using jq.  Supports standard JQ queries"
 
-	for i := range args {
-		if i == 0 {
-			continue
-		}
+var jqFlags = Flags{ //nolint:gochecknoglobals
+	"arg": {
+		Arguments: 2,
+		Usage:     "Set $name to the string value, provided as `name value`",
+	},
+	"argjson": {
+		Arguments: 2,
+		Usage:     "Set $name to the JSON value, provided as `name json`",
+	},
+	"c": {
+		Default: false,
+		Usage:   "Output compact JSON",
+	},
+	"e": {
+		Default: false,
+		Usage:   "Exit with an error if the last output is false, null, or missing",
+	},
+	"n": {
+		Default: false,
+		Usage:   "Use null as the input, inputs can be read using input or inputs",
+	},
+	"r": {
+		Default: false,
+		Usage:   "Output strings without quotes",
+	},
+	"s": {
+		Default: false,
+		Usage:   "Read all inputs into an array",
+	},
+	"yaml": {
+		Default: false,
+		Usage:   "Read and output YAML",
+	},
+	"yaml-input": {
+		Default: false,
+		Usage:   "Read YAML",
+	},
+	"yaml-output": {
+		Default: false,
+		Usage:   "Output YAML",
+	},
+}
+
+type jqOptions struct {
+	compact    bool
+	exitStatus bool
+	files      []string
+	filter     string
+	names      []string
+	nullInput  bool
+	raw        bool
+	slurp      bool
+	values     []any
+	yamlInput  bool
+	yamlOutput bool
+}
+
+// jqInputs iterates over JSON values from a decoder.
+type jqInputs struct {
+	decoder *json.Decoder
+	done    bool
+}
+
+func (j *jqInputs) Next() (any, bool) {
+	if j.done {
+		return nil, false
+	}
+
+	var v any
+
+	if err := j.decoder.Decode(&v); err != nil {
+		j.done = true
 
-		if args[i] == "-r" {
-			raw = true
-		} else {
-			s = args[i]
+		if errors.Is(err, io.EOF) {
+			return nil, false
 		}
+
+		return err, true
 	}
 
-	q, err := gojq.Parse(s)
+	return v, true
+}
+
+func jq[T AppConfig[any]](ctx context.Context, args []string, _ T) errs.Err {
+	o, err := jqParseOptions(ctx, args[1:])
 	if err != nil {
 		return logger.Error(ctx, errs.ErrReceiver.Wrap(errJQ, err))
 	}
 
-	var v any
+	q, err := gojq.Parse(o.filter)
+	if err != nil {
+		return logger.Error(ctx, errs.ErrReceiver.Wrap(errJQ, err))
+	}
 
-	if err := json.Unmarshal([]byte(ReadStdin()), &v); err != nil {
+	r, err := jqReader(o)
+	if err != nil {
 		return logger.Error(ctx, errs.ErrReceiver.Wrap(errJQ, err))
 	}
 
-	iter := q.Run(v)
+	d := json.NewDecoder(r)
+	d.UseNumber()
 
-	for {
-		v, ok := iter.Next()
-		if !ok {
-			break
+	var inputs gojq.Iter = &jqInputs{
+		decoder: d,
+	}
+
+	if o.slurp {
+		s := []any{}
+
+		for {
+			v, ok := inputs.Next()
+			if !ok {
+				break
+			}
+
+			if err, ok := v.(error); ok {
+				return logger.Error(ctx, errs.ErrReceiver.Wrap(errJQ, err))
+			}
+
+			s = append(s, v)
 		}
 
-		if err, ok := v.(error); ok {
-			return logger.Error(ctx, errs.ErrReceiver.Wrap(errJQ, err))
+		inputs = gojq.NewIter([]any{s}...)
+	}
+
+	code, err := gojq.Compile(q, gojq.WithEnvironLoader(os.Environ), gojq.WithInputIter(inputs), gojq.WithVariables(o.names))
+	if err != nil {
+		return logger.Error(ctx, errs.ErrReceiver.Wrap(errJQ, err))
+	}
+
+	var last any
+
+	outputs := 0
+
+	run := func(v any) errs.Err {
+		iter := code.RunWithContext(ctx, v, o.values...)
+
+		for {
+			v, ok := iter.Next()
+			if !ok {
+				return nil
+			}
+
+			if err, ok := v.(error); ok {
+				if h, ok := err.(interface{ IsEmptyError() bool }); ok && h.IsEmptyError() { //nolint:errorlint
+					return nil
+				}
+
+				return logger.Error(ctx, errs.ErrReceiver.Wrap(errJQ, err))
+			}
+
+			s, err := jqFormat(o, v, outputs)
+			if err != nil {
+				return logger.Error(ctx, errs.ErrReceiver.Wrap(errJQ, err))
+			}
+
+			logger.Raw(s)
+
+			last = v
+			outputs++
 		}
+	}
 
-		m, err := json.MarshalIndent(v, "", "  ")
-		if err != nil {
-			return logger.Error(ctx, errs.ErrReceiver.Wrap(errJQ, err))
+	if o.nullInput {
+		if err := run(nil); err != nil {
+			return err
 		}
+	} else {
+		for {
+			v, ok := inputs.Next()
+			if !ok {
+				break
+			}
 
-		s := string(m)
+			if err, ok := v.(error); ok {
+				return logger.Error(ctx, errs.ErrReceiver.Wrap(errJQ, err))
+			}
 
-		if raw {
-			if strings.HasPrefix(s, `"`) {
-				s, err = strconv.Unquote(s)
-				if err != nil {
-					return logger.Error(ctx, errs.ErrReceiver.Wrap(errJQ, err))
-				}
+			if err := run(v); err != nil {
+				return err
 			}
 		}
+	}
 
-		logger.Raw(fmt.Sprintf("%s\n", s))
+	if o.exitStatus && (outputs == 0 || last == nil || last == false) {
+		return ErrJQExitStatus
 	}
 
 	return nil
 }
+
+// jqFormat renders a jq output value.  i is the number of previous outputs.
+func jqFormat(o jqOptions, v any, i int) (string, error) {
+	if s, ok := v.(string); ok && o.raw {
+		return s + "\n", nil
+	}
+
+	if o.yamlOutput {
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+
+		if i > 0 {
+			return "---\n" + string(b), nil
+		}
+
+		return string(b), nil
+	}
+
+	var b bytes.Buffer
+
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+
+	if !o.compact {
+		e.SetIndent("", "  ")
+	}
+
+	if err := e.Encode(v); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// jqReader returns a reader of JSON values from the files or stdin, converting YAML documents to JSON if necessary.
+func jqReader(o jqOptions) (io.Reader, error) {
+	readers := []io.Reader{}
+
+	if len(o.files) == 0 {
+		readers = append(readers, os.Stdin)
+	}
+
+	for _, f := range o.files {
+		if f == "-" {
+			readers = append(readers, os.Stdin)
+
+			continue
+		}
+
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		readers = append(readers, bytes.NewReader(b), strings.NewReader("\n"))
+	}
+
+	r := io.MultiReader(readers...)
+
+	if !o.yamlInput {
+		return r, nil
+	}
+
+	var out bytes.Buffer
+
+	var doc bytes.Buffer
+
+	convert := func() error {
+		if strings.TrimSpace(doc.String()) == "" {
+			return nil
+		}
+
+		j, err := yaml.YAMLToJSON(doc.Bytes())
+		if err != nil {
+			return err
+		}
+
+		out.Write(j)
+		out.WriteString("\n")
+		doc.Reset()
+
+		return nil
+	}
+
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1024*1024*64)
+
+	for s.Scan() {
+		if strings.TrimRight(s.Text(), " ") == "---" {
+			if err := convert(); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		doc.WriteString(s.Text() + "\n")
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return &out, convert()
+}
+
+// jqParseOptions returns the jqOptions from the command flags and arguments.  The first argument is the filter, the remaining are files.
+func jqParseOptions(ctx context.Context, args []string) (jqOptions, error) {
+	yaml := GetFlag[bool](ctx, "yaml")
+
+	o := jqOptions{
+		compact:    GetFlag[bool](ctx, "c"),
+		exitStatus: GetFlag[bool](ctx, "e"),
+		filter:     ".",
+		names:      []string{},
+		nullInput:  GetFlag[bool](ctx, "n"),
+		raw:        GetFlag[bool](ctx, "r"),
+		slurp:      GetFlag[bool](ctx, "s"),
+		values:     []any{},
+		yamlInput:  yaml || GetFlag[bool](ctx, "yaml-input"),
+		yamlOutput: yaml || GetFlag[bool](ctx, "yaml-output"),
+	}
+
+	for _, a := range GetFlag[[][]string](ctx, "arg") {
+		o.names = append(o.names, "$"+a[0])
+		o.values = append(o.values, a[1])
+	}
+
+	for _, a := range GetFlag[[][]string](ctx, "argjson") {
+		var v any
+
+		if err := json.Unmarshal([]byte(a[1]), &v); err != nil {
+			return o, fmt.Errorf("invalid JSON for --argjson %s: %w", a[0], err)
+		}
+
+		o.names = append(o.names, "$"+a[0])
+		o.values = append(o.values, v)
+	}
+
+	if len(args) > 0 {
+		o.filter = args[0]
+		o.files = args[1:]
+	}
+
+	return o, nil
+}
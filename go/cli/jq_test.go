@@ -8,6 +8,21 @@ import (
 	"github.com/candiddev/shared/go/logger"
 )
 
+func TestJQFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{
+			"-x",
+		},
+		{
+			"--arg",
+			"a",
+		},
+	} {
+		_, _, err := jqFlags.parse("jq", args)
+		assert.Equal(t, err != nil, true)
+	}
+}
+
 func TestJQ(t *testing.T) {
 	c := &C{}
 	ctx := context.Background()
@@ -15,6 +30,7 @@ func TestJQ(t *testing.T) {
 
 	tests := map[string]struct {
 		args    []string
+		stdin   string
 		wantOut string
 		wantErr error
 	}{
@@ -69,13 +85,143 @@ func TestJQ(t *testing.T) {
 			wantErr: errJQ,
 			wantOut: "ERROR error querying JSON: function not defined: oops/0\n",
 		},
+		"compact": {
+			args: []string{
+				"",
+				"-c",
+				".nested",
+			},
+			wantOut: `[{"bool":true,"int":10,"string":"value"}]` + "\n",
+		},
+		"args": {
+			args: []string{
+				"",
+				"-c",
+				"--arg",
+				"a",
+				"1",
+				"--argjson",
+				"b",
+				`{"c":1}`,
+				"[$a, $b]",
+			},
+			wantOut: `["1",{"c":1}]` + "\n",
+		},
+		"argjson invalid": {
+			args: []string{
+				"",
+				"--argjson",
+				"b",
+				"{",
+				".",
+			},
+			wantErr: errJQ,
+			wantOut: "ERROR error querying JSON: invalid JSON for --argjson b: unexpected end of JSON input\n",
+		},
+		"ndjson": {
+			args: []string{
+				"",
+				"-r",
+				".a",
+			},
+			stdin:   "{\"a\":\"1\"}\n{\"a\":\"2\"}\n",
+			wantOut: "1\n2\n",
+		},
+		"slurp": {
+			args: []string{
+				"",
+				"-s",
+				"-c",
+				"map(.a)",
+			},
+			stdin:   "{\"a\":1}\n{\"a\":2}\n",
+			wantOut: "[1,2]\n",
+		},
+		"null input": {
+			args: []string{
+				"",
+				"-n",
+				"-c",
+				"[inputs.a]",
+			},
+			stdin:   "{\"a\":1}\n{\"a\":2}\n",
+			wantOut: "[1,2]\n",
+		},
+		"yaml": {
+			args: []string{
+				"",
+				"--yaml",
+				".a",
+			},
+			stdin: `a:
+  b: 1
+---
+a:
+  b: 2
+`,
+			wantOut: "b: 1\n---\nb: 2\n",
+		},
+		"file": {
+			args: []string{
+				"",
+				"-r",
+				".Show.Message",
+				"testdata/config.json",
+				"testdata/config.json",
+			},
+			wantOut: "Hello World\nHello World\n",
+		},
+		"exit status": {
+			args: []string{
+				"",
+				"-e",
+				".missing",
+			},
+			wantErr: ErrJQExitStatus,
+			wantOut: "null\n",
+		},
+		"exit status true": {
+			args: []string{
+				"",
+				"-e",
+				".nested[0].bool",
+			},
+			wantOut: "true\n",
+		},
+		"flags after filter": {
+			args: []string{
+				"",
+				".a",
+				"--arg",
+				"a",
+				"1",
+				"-c",
+			},
+			stdin:   `{"a":[1]}`,
+			wantOut: "[1]\n",
+		},
+		"positional after separator": {
+			args: []string{
+				"",
+				"--",
+				"-1",
+			},
+			wantOut: "-1\n",
+		},
 	}
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			SetStdin(`{"nested":[{"string":"value","int":10,"bool":true}]}`)
+			if tc.stdin == "" {
+				tc.stdin = `{"nested":[{"string":"value","int":10,"bool":true}]}`
+			}
+
+			values, args, err := jqFlags.parse("jq", tc.args[1:])
+			assert.HasErr(t, err, nil)
+
+			SetStdin(tc.stdin)
 			logger.SetStd()
-			assert.HasErr(t, jq(ctx, tc.args, c), tc.wantErr)
+			assert.HasErr(t, jq(context.WithValue(ctx, ctxFlags, values), append([]string{""}, args...), c), tc.wantErr)
 			assert.Equal(t, logger.ReadStd(), tc.wantOut)
 		})
 	}
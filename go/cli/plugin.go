@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/candiddev/shared/go/config"
+	"github.com/candiddev/shared/go/errs"
+	"github.com/candiddev/shared/go/logger"
+)
+
+var ErrPlugin = errors.New("error running plugin")
+
+// plugins returns the plugin commands of the App, keyed by command name, with the path to the plugin executable.  Plugins are executables named <app>-<command>, found in PluginDir and then PATH if Plugins is enabled.  Relative directories are skipped, like exec.LookPath does with exec.ErrDot, so plugins aren't run from the current directory.  Plugins can't override App Commands.
+func (a App[T]) plugins() map[string]string {
+	out := map[string]string{}
+
+	if !a.Plugins {
+		return out
+	}
+
+	prefix := strings.ToLower(a.Name) + "-"
+	dirs := filepath.SplitList(os.Getenv("PATH"))
+
+	if a.PluginDir != "" {
+		dirs = append([]string{a.PluginDir}, dirs...)
+	}
+
+	for _, dir := range dirs {
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, e := range entries {
+			name, ok := strings.CutPrefix(e.Name(), prefix)
+			if !ok || name == "" {
+				continue
+			}
+
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+
+			if _, ok := out[name]; ok {
+				continue
+			}
+
+			if _, _, ok := findCommand(a.Commands, name); ok {
+				continue
+			}
+
+			p := filepath.Join(dir, e.Name())
+
+			if s, err := os.Stat(p); err != nil || s.IsDir() || (runtime.GOOS != "windows" && s.Mode().Perm()&0111 == 0) {
+				continue
+			}
+
+			out[name] = p
+		}
+	}
+
+	return out
+}
+
+// pluginNames returns the sorted names of plugins.
+func pluginNames(p map[string]string) []string {
+	n := make([]string, 0, len(p))
+
+	for k := range p {
+		n = append(n, k)
+	}
+
+	sort.Strings(n)
+
+	return n
+}
+
+// runPlugin runs the plugin at path with args, attached to the current stdin, stdout, and stderr.  The config is masked using HideConfigFields and passed as JSON using the <APP>_PLUGIN_CONFIG environment variable.
+func (a App[T]) runPlugin(ctx context.Context, path string, args []string) errs.Err {
+	env := os.Environ()
+	prefix := strings.ToUpper(a.Name) + "_PLUGIN_"
+
+	if !a.NoParse {
+		out, err := config.Mask(ctx, a.Config, a.HideConfigFields)
+		if err != nil {
+			return logger.Error(ctx, err)
+		}
+
+		b, e := json.Marshal(out)
+		if e != nil {
+			return logger.Error(ctx, errs.ErrReceiver.Wrap(ErrPlugin, e))
+		}
+
+		env = append(env, prefix+"CONFIG="+string(b))
+	}
+
+	if exe, err := os.Executable(); err == nil {
+		env = append(env, prefix+"EXECUTABLE="+exe)
+	}
+
+	logger.Debug(ctx, fmt.Sprintf("Running plugin %s %s", path, strings.Join(args, " ")))
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Env = env
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = logger.Stdout
+
+	if err := cmd.Run(); err != nil {
+		return logger.Error(ctx, errs.ErrReceiver.Wrap(ErrPlugin, fmt.Errorf("%s: %w", filepath.Base(path), err)))
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/logger"
+)
+
+func TestAppPlugins(t *testing.T) {
+	dir := t.TempDir()
+	path := t.TempDir()
+
+	for _, f := range []struct {
+		dir  string
+		mode os.FileMode
+		name string
+		run  string
+	}{
+		{
+			dir:  dir,
+			mode: 0700,
+			name: "app-hello",
+			run:  `echo "hello $@ $APP_PLUGIN_CONFIG"`,
+		},
+		{
+			dir:  dir,
+			mode: 0700,
+			name: "app-fail",
+			run:  "exit 2",
+		},
+		{
+			dir:  dir,
+			mode: 0600,
+			name: "app-noexec",
+		},
+		{
+			dir:  dir,
+			mode: 0700,
+			name: "app-version",
+		},
+		{
+			dir:  path,
+			mode: 0700,
+			name: "app-hello",
+			run:  "echo path",
+		},
+		{
+			dir:  path,
+			mode: 0700,
+			name: "app-path",
+			run:  "echo path",
+		},
+	} {
+		assert.HasErr(t, os.WriteFile(filepath.Join(f.dir, f.name), []byte("#!/bin/sh\n"+f.run+"\n"), f.mode), nil)
+	}
+
+	// Relative PATH entries are ignored.
+	rel := t.TempDir()
+	assert.HasErr(t, os.WriteFile(filepath.Join(rel, "app-relative"), []byte("#!/bin/sh\necho relative\n"), 0700), nil)
+
+	wd, err := os.Getwd()
+	assert.HasErr(t, err, nil)
+
+	rel, err = filepath.Rel(wd, rel)
+	assert.HasErr(t, err, nil)
+
+	t.Setenv("PATH", strings.Join([]string{rel, "", path, os.Getenv("PATH")}, string(os.PathListSeparator)))
+
+	a := App[*C]{
+		Commands:    map[string]Command[*C]{},
+		Config:      &C{},
+		Description: "Does things",
+		HideConfigFields: []string{
+			"Hide",
+		},
+		Name:      "App",
+		PluginDir: dir,
+		Plugins:   true,
+	}
+
+	tests := map[string]struct {
+		args      []string
+		err       error
+		noPlugins bool
+		output    string
+	}{
+		"usage": {
+			err: ErrUnknownCommand,
+			output: `
+Plugins:
+  fail
+    	` + filepath.Join(dir, "app-fail") + `
+  hello
+    	` + filepath.Join(dir, "app-hello") + `
+  path
+    	` + filepath.Join(path, "app-path") + `
+`,
+		},
+		"hello": {
+			args:   []string{"-c", "./testdata/config.json", "hello", "-a", "world"},
+			output: `hello -a world {"CLI":{"configPath":"./testdata/config.json","logFormat":"","logLevel":"","noColor":false,"outputFormat":""},"Other":{},"Show":{"Message":"Hello World"}}`,
+		},
+		"fail": {
+			args: []string{"-c", "./testdata/config.json", "fail"},
+			err:  ErrPlugin,
+		},
+		"disabled": {
+			args:      []string{"-c", "./testdata/config.json", "hello"},
+			err:       ErrUnknownCommand,
+			noPlugins: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			a.Plugins = !tc.noPlugins
+
+			os.Args = append([]string{"app"}, tc.args...)
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+			logger.SetStd()
+
+			err := a.Run()
+
+			assert.HasErr(t, err, tc.err)
+			assert.Contains(t, logger.ReadStd(), tc.output)
+		})
+	}
+
+	a.Plugins = true
+
+	assert.Equal(t, a.plugins(), map[string]string{
+		"fail":  filepath.Join(dir, "app-fail"),
+		"hello": filepath.Join(dir, "app-hello"),
+		"path":  filepath.Join(path, "app-path"),
+	})
+
+	assert.Equal(t, a.complete(logger.UseTestLogger(t), []string{"h"}), []string{"hello"})
+}
@@ -1,35 +1,319 @@
 package cli
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
 
+	"github.com/BurntSushi/toml"
 	"github.com/candiddev/shared/go/config"
 	"github.com/candiddev/shared/go/errs"
+	"github.com/candiddev/shared/go/jsonnet"
 	"github.com/candiddev/shared/go/logger"
-	"github.com/candiddev/shared/go/types"
+	"sigs.k8s.io/yaml"
 )
 
-// Print takes any input, marshals to JSON, and prints it to stdout.
+// OutputFormat is a format used by Print.
+type OutputFormat string
+
+// OutputFormats for Print.
+const (
+	OutputFormatEnv         OutputFormat = "env"
+	OutputFormatJSON        OutputFormat = "json"
+	OutputFormatJSONCompact OutputFormat = "json-compact"
+	OutputFormatJsonnet     OutputFormat = "jsonnet"
+	OutputFormatTable       OutputFormat = "table"
+	OutputFormatTOML        OutputFormat = "toml"
+	OutputFormatYAML        OutputFormat = "yaml"
+)
+
+var ErrOutputFormat = errs.ErrSenderBadRequest.Wrap(errors.New("unknown output format, must be env, json, json-compact, jsonnet, table, toml, or yaml"))
+
+var errPrint = errors.New("error printing")
+
+// outputFormat is the OutputFormat used by Print, set by App.Run.
+var outputFormat OutputFormat //nolint:gochecknoglobals
+
+// Print takes any input, renders it using the output format (-o), and prints it to stdout.  Defaults to indented JSON.
 func Print(out any) errs.Err {
-	o, err := json.MarshalIndent(out, "", "  ")
+	s, err := render(outputFormat, "", out)
 	if err != nil {
-		return errs.ErrReceiver.Wrap(errors.New("error printing"), err)
+		return err
 	}
 
-	logger.Raw(string(o) + "\n")
+	logger.Raw(s)
 
 	return nil
 }
 
-func printConfig[T AppConfig[any]](ctx context.Context, a App[T]) errs.Err {
-	out, err := config.Mask(ctx, a.Config, a.HideConfigFields)
+// configSchemaCommand returns a command printing the JSON Schema of Config, using the values of Config before it's parsed as the defaults.
+func (a App[T]) configSchemaCommand() Command[T] {
+	b, e := json.MarshalIndent(config.NewSchema(a.Config, a.HideConfigFields), "", "  ")
+
+	return Command[T]{
+		Run: func(ctx context.Context, _ []string, _ T) errs.Err {
+			if e != nil {
+				return logger.Error(ctx, errs.ErrReceiver.Wrap(errPrint, e))
+			}
+
+			logger.Raw(string(b) + "\n")
+
+			return nil
+		},
+		Usage: "Print the JSON Schema of the configuration, for editor completion and validation",
+	}
+}
+
+// printConfig prints c, which is the config passed to the command, as it may have been reloaded since the App was set up.
+func printConfig[T AppConfig[any]](ctx context.Context, a App[T], c T) errs.Err {
+	out, err := config.Mask(ctx, c, a.HideConfigFields)
 	if err != nil {
 		return logger.Error(ctx, err)
 	}
 
-	logger.Info(logger.SetFormat(ctx, logger.FormatRaw), types.JSONToString(out))
+	s, err := render(c.CLIConfig().OutputFormat, strings.ToUpper(a.Name)+"_", out)
+	if err != nil {
+		return logger.Error(ctx, err)
+	}
+
+	logger.Raw(s)
 
 	return logger.Error(ctx, nil)
 }
+
+// render converts out to a string using format.  envPrefix is prepended to the keys of OutputFormatEnv.
+func render(format OutputFormat, envPrefix string, out any) (string, errs.Err) {
+	var b []byte
+
+	var err error
+
+	switch format {
+	case "", OutputFormatJSON:
+		b, err = json.MarshalIndent(out, "", "  ")
+	case OutputFormatJSONCompact:
+		b, err = json.Marshal(out)
+	case OutputFormatEnv, OutputFormatTable, OutputFormatTOML:
+		return renderMap(format, envPrefix, out)
+	case OutputFormatJsonnet:
+		s, e := jsonnet.Convert(context.Background(), out)
+		if e != nil {
+			return "", errs.ErrReceiver.Wrap(errPrint, e)
+		}
+
+		return s, nil
+	case OutputFormatYAML:
+		b, err = yaml.Marshal(out)
+		if err == nil {
+			return string(b), nil
+		}
+	default:
+		return "", ErrOutputFormat
+	}
+
+	if err != nil {
+		return "", errs.ErrReceiver.Wrap(errPrint, err)
+	}
+
+	return string(b) + "\n", nil
+}
+
+// renderMap renders the formats that operate on the JSON representation of out.
+func renderMap(format OutputFormat, envPrefix string, out any) (string, errs.Err) {
+	j, err := json.Marshal(out)
+	if err != nil {
+		return "", errs.ErrReceiver.Wrap(errPrint, err)
+	}
+
+	var v any
+
+	d := json.NewDecoder(bytes.NewReader(j))
+	d.UseNumber()
+
+	if err := d.Decode(&v); err != nil {
+		return "", errs.ErrReceiver.Wrap(errPrint, err)
+	}
+
+	v = renderNumbers(v)
+
+	var b strings.Builder
+
+	switch format { //nolint:exhaustive
+	case OutputFormatEnv:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return "", errs.ErrReceiver.Wrap(errPrint, errors.New("env output requires an object"))
+		}
+
+		renderEnv(&b, envPrefix, m)
+	case OutputFormatTable:
+		renderTable(&b, j, v)
+	case OutputFormatTOML:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return "", errs.ErrReceiver.Wrap(errPrint, errors.New("toml output requires an object"))
+		}
+
+		if err := toml.NewEncoder(&b).Encode(m); err != nil {
+			return "", errs.ErrReceiver.Wrap(errPrint, err)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// renderEnv writes KEY='value' lines, using the same key names as config.ParseValues.  Values are single quoted so they can be evaluated by a shell.
+func renderEnv(b *strings.Builder, prefix string, m map[string]any) {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if v, ok := m[k].(map[string]any); ok && len(v) > 0 {
+			renderEnv(b, prefix+k+"_", v)
+
+			continue
+		}
+
+		fmt.Fprintf(b, "%s%s='%s'\n", prefix, k, strings.ReplaceAll(renderValue(m[k]), "'", `'\''`))
+	}
+}
+
+// renderTable writes an aligned table for a list of objects or a single object.  Columns are ordered by their first appearance in the JSON j.
+func renderTable(b *strings.Builder, j []byte, v any) {
+	rows := []map[string]any{}
+
+	switch t := v.(type) {
+	case []any:
+		for i := range t {
+			if m, ok := t[i].(map[string]any); ok {
+				rows = append(rows, m)
+			}
+		}
+	case map[string]any:
+		rows = append(rows, t)
+	}
+
+	if len(rows) == 0 {
+		fmt.Fprintln(b, renderValue(v))
+
+		return
+	}
+
+	columns := jsonKeys(j)
+
+	var o strings.Builder
+
+	w := tabwriter.NewWriter(&o, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(columns, "\t")))
+
+	for _, r := range rows {
+		values := make([]string, len(columns))
+
+		for i, c := range columns {
+			values[i] = renderValue(r[c])
+		}
+
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+
+	w.Flush()
+
+	t := strings.Split(o.String(), "\n")
+	for i := range t {
+		t[i] = strings.TrimRight(t[i], " ")
+	}
+
+	b.WriteString(strings.Join(t, "\n"))
+}
+
+// renderNumbers converts json.Number to int64 or float64 so integers aren't rendered as floats.
+func renderNumbers(v any) any {
+	switch t := v.(type) {
+	case []any:
+		for i := range t {
+			t[i] = renderNumbers(t[i])
+		}
+	case map[string]any:
+		for k := range t {
+			t[k] = renderNumbers(t[k])
+		}
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+
+		f, _ := t.Float64()
+
+		return f
+	}
+
+	return v
+}
+
+// renderValue returns strings as is and other values as compact JSON.
+func renderValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(b)
+}
+
+// jsonKeys returns the keys of the object j, or the objects within the list j, in order of first appearance.
+func jsonKeys(j []byte) []string {
+	objects := []json.RawMessage{}
+	if err := json.Unmarshal(j, &objects); err != nil {
+		objects = []json.RawMessage{
+			j,
+		}
+	}
+
+	keys := []string{}
+	seen := map[string]bool{}
+
+	for _, o := range objects {
+		d := json.NewDecoder(bytes.NewReader(o))
+
+		if t, err := d.Token(); err != nil || t != json.Delim('{') {
+			continue
+		}
+
+		for d.More() {
+			t, err := d.Token()
+			if err != nil {
+				break
+			}
+
+			var v json.RawMessage
+
+			if err := d.Decode(&v); err != nil {
+				break
+			}
+
+			if k, ok := t.(string); ok && !seen[k] {
+				keys = append(keys, k)
+				seen[k] = true
+			}
+		}
+	}
+
+	return keys
+}
@@ -66,14 +66,15 @@ func TestPrintConfig(t *testing.T) {
 			"Hide",
 			"Other",
 		},
-	}), nil)
+	}, &c), nil)
 
 	assert.Equal(t, logger.ReadStd(), `{
   "CLI": {
     "configPath": "",
     "logFormat": "",
     "logLevel": "",
-    "noColor": false
+    "noColor": false,
+    "outputFormat": ""
   },
   "Show": {
     "Message": "World"
@@ -81,3 +82,140 @@ func TestPrintConfig(t *testing.T) {
 }
 `)
 }
+
+func TestRender(t *testing.T) {
+	type row struct {
+		Name  string   `json:"name"`
+		Count int      `json:"count"`
+		Tags  []string `json:"tags"`
+	}
+
+	obj := map[string]any{
+		"a": "e",
+		"b": true,
+		"c": map[string]any{
+			"d": 1,
+			"e": []string{
+				"f",
+			},
+		},
+	}
+
+	rows := []row{
+		{
+			Name:  "first",
+			Count: 1,
+			Tags: []string{
+				"a",
+			},
+		},
+		{
+			Name:  "second-row",
+			Count: 20,
+		},
+	}
+
+	tests := map[string]struct {
+		err    error
+		format OutputFormat
+		input  any
+		want   string
+	}{
+		"env": {
+			format: OutputFormatEnv,
+			input:  obj,
+			want: `APP_a='e'
+APP_b='true'
+APP_c_d='1'
+APP_c_e='["f"]'
+`,
+		},
+		"env quoted": {
+			format: OutputFormatEnv,
+			input: map[string]any{
+				"a": "line 1\nit's $HOME\n",
+				"b": nil,
+			},
+			want: `APP_a='line 1
+it'\''s $HOME
+'
+APP_b=''
+`,
+		},
+		"env list": {
+			err:    errPrint,
+			format: OutputFormatEnv,
+			input:  rows,
+		},
+		"json compact": {
+			format: OutputFormatJSONCompact,
+			input:  obj,
+			want: `{"a":"e","b":true,"c":{"d":1,"e":["f"]}}
+`,
+		},
+		"jsonnet": {
+			format: OutputFormatJsonnet,
+			input:  obj,
+			want: `{
+  a: 'e',
+  b: true,
+  c: {
+    d: 1,
+    e: [
+      'f',
+    ],
+  },
+}
+`,
+		},
+		"table": {
+			format: OutputFormatTable,
+			input:  rows,
+			want: `NAME        COUNT  TAGS
+first       1      ["a"]
+second-row  20
+`,
+		},
+		"table value": {
+			format: OutputFormatTable,
+			input:  "a",
+			want: `a
+`,
+		},
+		"toml": {
+			format: OutputFormatTOML,
+			input:  obj,
+			want: `a = "e"
+b = true
+
+[c]
+  d = 1
+  e = ["f"]
+`,
+		},
+		"yaml": {
+			format: OutputFormatYAML,
+			input:  obj,
+			want: `a: e
+b: true
+c:
+  d: 1
+  e:
+  - f
+`,
+		},
+		"unknown": {
+			err:    ErrOutputFormat,
+			format: "xml",
+			input:  obj,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := render(tc.format, "APP_", tc.input)
+			assert.HasErr(t, err, tc.err)
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
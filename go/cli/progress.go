@@ -0,0 +1,368 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/candiddev/shared/go/logger"
+	"golang.org/x/term"
+)
+
+// ProgressStatus is the status of a ProgressTask.
+type ProgressStatus string
+
+// ProgressStatus values.
+const (
+	ProgressStatusDone    ProgressStatus = "done"
+	ProgressStatusFailed  ProgressStatus = "failed"
+	ProgressStatusRunning ProgressStatus = "running"
+)
+
+const (
+	progressBarWidth    = 30
+	progressIntervalLog = 5 * time.Second
+	progressIntervalTTY = 100 * time.Millisecond
+)
+
+var progressSpinner = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"} //nolint:gochecknoglobals
+
+// progressTTY returns whether logger.Stderr is a terminal.
+var progressTTY = func() bool { //nolint:gochecknoglobals
+	return term.IsTerminal(int(logger.Stderr.Fd()))
+}
+
+// Progress reports the progress of long-running tasks.  When logger.Stderr is a terminal and the log format is human, the tasks are rendered as spinners and bars in place.  Otherwise, the progress of running tasks is logged periodically using logger.Info.
+type Progress struct {
+	ctx      context.Context //nolint:containedctx
+	done     chan struct{}
+	frame    int
+	interval time.Duration
+	lines    int
+	mutex    sync.Mutex
+	stop     chan struct{}
+	tasks    []*ProgressTask
+	tty      bool
+}
+
+// ProgressTask is a task within Progress.  A ProgressTask with a total renders as a bar, otherwise it renders as a spinner.
+type ProgressTask struct {
+	bytes    bool
+	current  int64
+	err      error
+	finished time.Time
+	message  string
+	name     string
+	progress *Progress
+	start    time.Time
+	status   ProgressStatus
+	total    int64
+}
+
+// progressWriter writes to an io.Writer and adds the bytes written to a ProgressTask.  It implements get.ProgressWriter.
+type progressWriter struct {
+	task   *ProgressTask
+	writer io.Writer
+}
+
+func (p *progressWriter) SetTotal(total int64) {
+	p.task.SetTotal(total)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.writer.Write(b)
+	p.task.Add(int64(n))
+
+	return n, err
+}
+
+// NewProgress creates a Progress and starts rendering it.  Stop must be called once all tasks are finished.
+func NewProgress(ctx context.Context) *Progress {
+	p := &Progress{
+		ctx:      ctx,
+		done:     make(chan struct{}),
+		interval: progressIntervalLog,
+		stop:     make(chan struct{}),
+		tty:      logger.GetFormat(ctx) == logger.FormatHuman && logger.GetLevel(ctx) != logger.LevelNone && progressTTY(),
+	}
+
+	if p.tty {
+		p.interval = progressIntervalTTY
+	}
+
+	go p.run()
+
+	return p
+}
+
+// Stop stops rendering Progress and renders the final status of each task.
+func (p *Progress) Stop() {
+	close(p.stop)
+	<-p.done
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.tty {
+		p.render()
+	}
+}
+
+// Task adds a ProgressTask to Progress.  total is the expected value of the task when it's done, or zero if it's unknown.
+func (p *Progress) Task(name string, total int64) *ProgressTask {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	t := &ProgressTask{
+		name:     name,
+		progress: p,
+		start:    time.Now(),
+		status:   ProgressStatusRunning,
+		total:    total,
+	}
+
+	p.tasks = append(p.tasks, t)
+
+	return t
+}
+
+func (p *Progress) run() {
+	t := time.NewTicker(p.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			close(p.done)
+
+			return
+		case <-t.C:
+			p.mutex.Lock()
+
+			if p.tty {
+				p.render()
+			} else {
+				for _, t := range p.tasks {
+					if t.status == ProgressStatusRunning {
+						t.log()
+					}
+				}
+			}
+
+			p.mutex.Unlock()
+		}
+	}
+}
+
+// render redraws all tasks over the previous render.
+func (p *Progress) render() {
+	var b strings.Builder
+
+	if p.lines > 0 {
+		fmt.Fprintf(&b, "\033[%dF\033[J", p.lines)
+	}
+
+	width := 0
+
+	for _, t := range p.tasks {
+		if len(t.name) > width {
+			width = len(t.name)
+		}
+	}
+
+	for _, t := range p.tasks {
+		b.WriteString(t.line(p.frame, width, !logger.GetNoColor(p.ctx)) + "\n")
+	}
+
+	p.frame++
+	p.lines = len(p.tasks)
+
+	fmt.Fprint(logger.Stderr, b.String()) //nolint:forbidigo
+}
+
+// Add adds n to the current value of the task.
+func (t *ProgressTask) Add(n int64) {
+	t.progress.mutex.Lock()
+	defer t.progress.mutex.Unlock()
+
+	t.current += n
+}
+
+// Done marks the task as done.
+func (t *ProgressTask) Done() {
+	t.finish(ProgressStatusDone, nil)
+}
+
+// Fail marks the task as failed with err.
+func (t *ProgressTask) Fail(err error) {
+	t.finish(ProgressStatusFailed, err)
+}
+
+// Set sets the current value of the task.
+func (t *ProgressTask) Set(current int64) {
+	t.progress.mutex.Lock()
+	defer t.progress.mutex.Unlock()
+
+	t.current = current
+}
+
+// SetMessage sets a message displayed after the task progress.
+func (t *ProgressTask) SetMessage(message string) {
+	t.progress.mutex.Lock()
+	defer t.progress.mutex.Unlock()
+
+	t.message = message
+}
+
+// SetTotal sets the expected value of the task when it's done.  Setting a total of zero renders the task as a spinner.
+func (t *ProgressTask) SetTotal(total int64) {
+	t.progress.mutex.Lock()
+	defer t.progress.mutex.Unlock()
+
+	t.total = total
+}
+
+// Writer returns an io.Writer that writes to w and adds the bytes written to the task.  The io.Writer can be passed to get.File to report download progress.
+func (t *ProgressTask) Writer(w io.Writer) io.Writer {
+	t.progress.mutex.Lock()
+	defer t.progress.mutex.Unlock()
+
+	t.bytes = true
+
+	return &progressWriter{
+		task:   t,
+		writer: w,
+	}
+}
+
+func (t *ProgressTask) finish(status ProgressStatus, err error) {
+	t.progress.mutex.Lock()
+	defer t.progress.mutex.Unlock()
+
+	if t.status != ProgressStatusRunning {
+		return
+	}
+
+	t.err = err
+	t.finished = time.Now()
+	t.status = status
+
+	if !t.progress.tty {
+		t.log()
+	}
+}
+
+// line renders the task for a terminal.
+func (t *ProgressTask) line(frame, width int, color bool) string {
+	name := fmt.Sprintf("%-*s", width, t.name)
+
+	var s string
+
+	switch t.status {
+	case ProgressStatusDone:
+		s = fmt.Sprintf("✓ %s  done in %s", name, t.finished.Sub(t.start).Round(time.Millisecond))
+		if color {
+			s = logger.ColorGreen + s + logger.ColorReset
+		}
+
+		return s
+	case ProgressStatusFailed:
+		s = fmt.Sprintf("✗ %s  failed: %s", name, t.err)
+		if color {
+			s = logger.ColorRed + s + logger.ColorReset
+		}
+
+		return s
+	case ProgressStatusRunning:
+	}
+
+	if t.total > 0 {
+		done := int(min(t.current, t.total) * progressBarWidth / t.total)
+		bar := strings.Repeat("=", done)
+
+		if done < progressBarWidth {
+			bar += ">" + strings.Repeat(" ", progressBarWidth-done-1)
+		}
+
+		s = fmt.Sprintf("  %s [%s] %s", name, bar, t.value())
+	} else {
+		s = fmt.Sprintf("%s %s", progressSpinner[frame%len(progressSpinner)], name)
+
+		if v := t.value(); v != "" {
+			s += "  " + v
+		}
+	}
+
+	if t.message != "" {
+		s += "  " + t.message
+	}
+
+	return s
+}
+
+// log logs the status of the task.
+func (t *ProgressTask) log() {
+	s := t.name + ": "
+
+	switch t.status {
+	case ProgressStatusDone:
+		s += fmt.Sprintf("done in %s", t.finished.Sub(t.start).Round(time.Millisecond))
+	case ProgressStatusFailed:
+		s += fmt.Sprintf("failed: %s", t.err)
+	case ProgressStatusRunning:
+		s += "running"
+
+		if v := t.value(); v != "" {
+			s += " " + v
+		}
+
+		if t.message != "" {
+			s += " " + t.message
+		}
+	}
+
+	logger.Info(logger.SetAttribute(t.progress.ctx, "task", t.name), s)
+}
+
+// value renders the current and total values of the task.
+func (t *ProgressTask) value() string {
+	f := func(n int64) string {
+		return fmt.Sprint(n)
+	}
+
+	if t.bytes {
+		f = progressBytes
+	}
+
+	switch {
+	case t.total > 0:
+		return fmt.Sprintf("%d%% (%s/%s)", min(t.current, t.total)*100/t.total, f(t.current), f(t.total))
+	case t.current > 0:
+		return f(t.current)
+	}
+
+	return ""
+}
+
+// progressBytes renders n using binary units.
+func progressBytes(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	v := float64(n)
+	u := ""
+
+	for _, u = range []string{"KiB", "MiB", "GiB", "TiB"} {
+		v /= 1024
+
+		if v < 1024 {
+			break
+		}
+	}
+
+	return fmt.Sprintf("%.1f %s", v, u)
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/logger"
+)
+
+func TestProgress(t *testing.T) {
+	tty := progressTTY
+	progressTTY = func() bool {
+		return true
+	}
+
+	defer func() {
+		progressTTY = tty
+	}()
+
+	logger.SetStd()
+
+	ctx := logger.SetFormat(context.Background(), logger.FormatHuman)
+	ctx = logger.SetNoColor(ctx, true)
+
+	p := NewProgress(ctx)
+	assert.Equal(t, p.tty, true)
+
+	download := p.Task("download", 0)
+
+	var b bytes.Buffer
+
+	w := download.Writer(&b)
+	w.(interface{ SetTotal(int64) }).SetTotal(2048)
+	w.Write(make([]byte, 1024))
+
+	migrate := p.Task("migrate", 0)
+	migrate.Add(3)
+	migrate.SetMessage("001.sql")
+
+	render := p.Task("render", 10)
+	render.Set(5)
+	render.Fail(errors.New("bad"))
+
+	p.mutex.Lock()
+	p.render()
+	p.mutex.Unlock()
+
+	download.Done()
+	migrate.Done()
+	p.Stop()
+
+	out := logger.ReadStd()
+
+	assert.Equal(t, b.Len(), 1024)
+	assert.Contains(t, out, "  download [===============>              ] 50% (1.0 KiB/2.0 KiB)\n⠋ migrate   3  001.sql\n✗ render    failed: bad\n")
+	assert.Contains(t, out, "\033[3F\033[J✓ download  done in ")
+	assert.Equal(t, strings.Contains(out, logger.ColorGreen), false)
+
+	progressTTY = func() bool {
+		return false
+	}
+
+	logger.SetStd()
+
+	ctx = logger.SetFormat(ctx, logger.FormatKV)
+	p = NewProgress(ctx)
+	assert.Equal(t, p.tty, false)
+
+	task := p.Task("task", 4)
+	task.Add(1)
+
+	p.mutex.Lock()
+	task.log()
+	p.mutex.Unlock()
+
+	task.Done()
+	p.Stop()
+
+	out = logger.ReadStd()
+
+	assert.Contains(t, out, `task="task" message="task: running 25% (1/4)"`)
+	assert.Contains(t, out, `message="task: done in `)
+}
+
+func TestProgressBytes(t *testing.T) {
+	for n, want := range map[int64]string{
+		10:              "10 B",
+		1536:            "1.5 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+	} {
+		assert.Equal(t, progressBytes(n), want)
+	}
+}
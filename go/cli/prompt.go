@@ -0,0 +1,331 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/candiddev/shared/go/logger"
+	"golang.org/x/term"
+)
+
+var (
+	ErrPromptEditor   = errors.New("error running editor")
+	ErrPromptInvalid  = errors.New("invalid input")
+	ErrPromptMismatch = errors.New("values do not match")
+	ErrPromptNoInput  = errors.New("no input available from stdin")
+)
+
+// promptStdin buffers os.Stdin between prompts, and is reset when os.Stdin changes, like after SetStdin.
+var promptStdin struct { //nolint:gochecknoglobals
+	file   *os.File
+	reader *bufio.Reader
+}
+
+// promptTTY returns whether os.Stdin is a terminal.  When it isn't, like when using SetStdin, prompts read a line from stdin and return an error for invalid input instead of prompting again.
+var promptTTY = func() bool { //nolint:gochecknoglobals
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// PromptConfirm prompts the user for a yes or no answer.  An empty answer returns def.  Prompts shouldn't include trailing punctuation.
+func PromptConfirm(prompt string, def bool) (bool, error) {
+	d := "y/N"
+	if def {
+		d = "Y/n"
+	}
+
+	for {
+		s, err := promptLine(fmt.Sprintf("%s [%s]:", prompt, d))
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+
+		if err := promptInvalid(fmt.Errorf("%w: must be y or n", ErrPromptInvalid)); err != nil {
+			return false, err
+		}
+	}
+}
+
+// PromptEditor opens $VISUAL or $EDITOR, defaulting to vi, on a temporary file containing initial and returns the saved contents.  ext is the file extension of the temporary file, like .json, for editor syntax highlighting.  If stdin is not a terminal, the remaining stdin is returned instead.
+func PromptEditor(prompt, initial, ext string) (string, error) {
+	if !promptTTY() {
+		fmt.Fprintf(logger.Stderr, "%s\n", prompt) //nolint:forbidigo
+
+		b, err := io.ReadAll(promptReader())
+		if err != nil {
+			return "", fmt.Errorf("error reading value: %w", err)
+		}
+
+		return string(b), nil
+	}
+
+	args := strings.Fields(os.Getenv("VISUAL"))
+	if len(args) == 0 {
+		args = strings.Fields(os.Getenv("EDITOR"))
+	}
+
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+
+	f, err := os.CreateTemp("", "prompt*"+ext)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrPromptEditor, err)
+	}
+
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(initial)
+	f.Close()
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrPromptEditor, err)
+	}
+
+	fmt.Fprintf(logger.Stderr, "%s\n", prompt) //nolint:forbidigo
+
+	cmd := exec.Command(args[0], append(args[1:], f.Name())...) //nolint:gosec
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrPromptEditor, err)
+	}
+
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrPromptEditor, err)
+	}
+
+	return string(b), nil
+}
+
+// PromptInput prompts the user for a line of input.  An empty answer returns def.  If validate returns an error, the user is prompted again.
+func PromptInput(prompt, def string, validate func(string) error) (string, error) {
+	if def != "" {
+		prompt = fmt.Sprintf("%s [%s]", prompt, def)
+	}
+
+	for {
+		s, err := promptLine(prompt + ":")
+		if err != nil {
+			return "", err
+		}
+
+		if s == "" {
+			s = def
+		}
+
+		if validate == nil {
+			return s, nil
+		}
+
+		err = validate(s)
+		if err == nil {
+			return s, nil
+		}
+
+		if err := promptInvalid(fmt.Errorf("%w: %w", ErrPromptInvalid, err)); err != nil {
+			return "", err
+		}
+	}
+}
+
+// PromptMultiSelect prompts the user to select zero or more options, and returns the selected indexes.  An empty answer returns defaults.
+func PromptMultiSelect(prompt string, options []string, defaults []int) ([]int, error) {
+	d := []string{}
+
+	for _, i := range defaults {
+		d = append(d, strconv.Itoa(i+1))
+	}
+
+	for {
+		promptOptions(options)
+
+		s, err := promptLine(fmt.Sprintf("%s (numbers separated by commas) [%s]:", prompt, strings.Join(d, ",")))
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.TrimSpace(s) == "" {
+			return defaults, nil
+		}
+
+		out := []int{}
+
+		for _, v := range strings.FieldsFunc(s, func(r rune) bool {
+			return r == ',' || r == ' '
+		}) {
+			i, e := promptOption(v, options)
+			if e != nil {
+				err = e
+
+				break
+			}
+
+			if !slices.Contains(out, i) {
+				out = append(out, i)
+			}
+		}
+
+		if err == nil {
+			return out, nil
+		}
+
+		if err := promptInvalid(err); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// PromptPassword prompts the user for a value without echo.  If confirm is true and stdin is a terminal, the user must enter the value twice.
+func PromptPassword(prompt string, confirm bool) ([]byte, error) {
+	if !promptTTY() {
+		s, err := promptLine(prompt + ":")
+		if err != nil {
+			return nil, err
+		}
+
+		return []byte(s), nil
+	}
+
+	for {
+		v, err := promptPassword(prompt + ":")
+		if err != nil || !confirm {
+			return v, err
+		}
+
+		c, err := promptPassword("Confirm " + prompt + ":")
+		if err != nil {
+			return nil, err
+		}
+
+		if string(c) == string(v) {
+			return v, nil
+		}
+
+		fmt.Fprintf(logger.Stderr, "%s\n", ErrPromptMismatch) //nolint:forbidigo
+	}
+}
+
+// PromptSelect prompts the user to select an option by number or value, and returns the selected index.  An empty answer returns def, or prompts again if def is negative.
+func PromptSelect(prompt string, options []string, def int) (int, error) {
+	d := ""
+	if def >= 0 && def < len(options) {
+		d = fmt.Sprintf(" [%d]", def+1)
+	}
+
+	for {
+		promptOptions(options)
+
+		s, err := promptLine(prompt + d + ":")
+		if err != nil {
+			return 0, err
+		}
+
+		if strings.TrimSpace(s) == "" && d != "" {
+			return def, nil
+		}
+
+		i, err := promptOption(s, options)
+		if err == nil {
+			return i, nil
+		}
+
+		if err := promptInvalid(err); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// promptInvalid returns err if stdin is not a terminal, otherwise it displays err so the user can be prompted again.
+func promptInvalid(err error) error {
+	if !promptTTY() {
+		return err
+	}
+
+	fmt.Fprintf(logger.Stderr, "%s\n", err) //nolint:forbidigo
+
+	return nil
+}
+
+// promptLine displays prompt and reads a line from stdin.
+func promptLine(prompt string) (string, error) {
+	fmt.Fprintf(logger.Stderr, "%s ", prompt) //nolint:forbidigo
+
+	s, err := promptReader().ReadString('\n')
+
+	if !promptTTY() {
+		fmt.Fprintf(logger.Stderr, "\n") //nolint:forbidigo
+	}
+
+	if err != nil && (!errors.Is(err, io.EOF) || s == "") {
+		if errors.Is(err, io.EOF) {
+			return "", ErrPromptNoInput
+		}
+
+		return "", fmt.Errorf("error reading value: %w", err)
+	}
+
+	return strings.TrimRight(s, "\r\n"), nil
+}
+
+// promptOption returns the index of an option by number or value.
+func promptOption(s string, options []string) (int, error) {
+	s = strings.TrimSpace(s)
+
+	if i, err := strconv.Atoi(s); err == nil && i > 0 && i <= len(options) {
+		return i - 1, nil
+	}
+
+	if i := slices.Index(options, s); i >= 0 {
+		return i, nil
+	}
+
+	return 0, fmt.Errorf("%w: %s is not an option", ErrPromptInvalid, s)
+}
+
+func promptOptions(options []string) {
+	for i := range options {
+		fmt.Fprintf(logger.Stderr, "%d) %s\n", i+1, options[i]) //nolint:forbidigo
+	}
+}
+
+func promptPassword(prompt string) ([]byte, error) {
+	fmt.Fprintf(logger.Stderr, "%s ", prompt) //nolint:forbidigo
+
+	v, err := term.ReadPassword(int(os.Stdin.Fd()))
+
+	fmt.Fprintf(logger.Stderr, "\n") //nolint:forbidigo
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading value: %w", err)
+	}
+
+	return v, nil
+}
+
+func promptReader() *bufio.Reader {
+	if promptStdin.file != os.Stdin {
+		promptStdin.file = os.Stdin
+		promptStdin.reader = bufio.NewReader(os.Stdin)
+	}
+
+	return promptStdin.reader
+}
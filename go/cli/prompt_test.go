@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/logger"
+)
+
+func TestPromptConfirm(t *testing.T) {
+	logger.SetStd()
+
+	tests := map[string]struct {
+		def     bool
+		input   string
+		want    bool
+		wantErr error
+	}{
+		"yes": {
+			input: "Yes",
+			want:  true,
+		},
+		"no": {
+			def:   true,
+			input: "n",
+		},
+		"invalid": {
+			input:   "maybe",
+			wantErr: ErrPromptInvalid,
+		},
+		"no_input": {
+			wantErr: ErrPromptNoInput,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			SetStdin(tc.input)
+
+			got, err := PromptConfirm("Continue?", tc.def)
+			assert.HasErr(t, err, tc.wantErr)
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
+
+func TestPromptEditor(t *testing.T) {
+	logger.SetStd()
+
+	SetStdin("hello\nworld")
+
+	out, err := PromptEditor("Message", "", ".txt")
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, out, "hello\nworld")
+
+	tty := promptTTY
+	promptTTY = func() bool {
+		return true
+	}
+
+	defer func() {
+		promptTTY = tty
+	}()
+
+	e := filepath.Join(t.TempDir(), "editor")
+	os.WriteFile(e, []byte("#!/bin/sh\ncase $1 in *.json) sed -i s/a/b/ $1;; esac"), 0700) //nolint:gosec
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", e)
+
+	out, err = PromptEditor("Message", `{"a":true}`, ".json")
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, out, `{"b":true}`)
+
+	// Blank editors use vi.
+	path := os.Getenv("PATH")
+
+	os.Rename(e, filepath.Join(filepath.Dir(e), "vi"))
+	t.Setenv("PATH", filepath.Dir(e)+string(os.PathListSeparator)+path)
+	t.Setenv("VISUAL", " ")
+	t.Setenv("EDITOR", " ")
+
+	out, err = PromptEditor("Message", `{"a":true}`, ".json")
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, out, `{"b":true}`)
+
+	t.Setenv("PATH", path)
+	t.Setenv("EDITOR", "false")
+
+	_, err = PromptEditor("Message", "", "")
+	assert.HasErr(t, err, ErrPromptEditor)
+}
+
+func TestPromptInput(t *testing.T) {
+	logger.SetStd()
+
+	validate := func(s string) error {
+		if s == "bad" {
+			return errors.New("bad value")
+		}
+
+		return nil
+	}
+
+	SetStdin("a\n\nbad")
+
+	out, err := PromptInput("Name", "", validate)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, out, "a")
+
+	out, err = PromptInput("Name", "default", validate)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, out, "default")
+
+	out, err = PromptInput("Name", "", validate)
+	assert.HasErr(t, err, ErrPromptInvalid)
+	assert.Equal(t, out, "")
+
+	tty := promptTTY
+	promptTTY = func() bool {
+		return true
+	}
+
+	defer func() {
+		promptTTY = tty
+	}()
+
+	SetStdin("bad\ngood")
+
+	out, err = PromptInput("Name", "", validate)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, out, "good")
+	assert.Contains(t, logger.ReadStd(), "invalid input: bad value\nName:")
+}
+
+func TestPromptPassword(t *testing.T) {
+	logger.SetStd()
+
+	SetStdin("secret")
+
+	out, err := PromptPassword("Password", true)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, string(out), "secret")
+}
+
+func TestPromptSelect(t *testing.T) {
+	logger.SetStd()
+
+	options := []string{
+		"a",
+		"b",
+		"c",
+	}
+
+	SetStdin("2\n\nc\nd\n")
+
+	for _, want := range []int{
+		1,
+		0,
+		2,
+	} {
+		got, err := PromptSelect("Option", options, 0)
+		assert.HasErr(t, err, nil)
+		assert.Equal(t, got, want)
+	}
+
+	_, err := PromptSelect("Option", options, -1)
+	assert.HasErr(t, err, ErrPromptInvalid)
+	assert.Contains(t, logger.ReadStd(), "1) a\n2) b\n3) c\nOption [1]:")
+
+	SetStdin("3, a\n\nb,4")
+
+	got, err := PromptMultiSelect("Options", options, nil)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, got, []int{2, 0})
+
+	got, err = PromptMultiSelect("Options", options, []int{1})
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, got, []int{1})
+
+	_, err = PromptMultiSelect("Options", options, nil)
+	assert.HasErr(t, err, ErrPromptInvalid)
+}
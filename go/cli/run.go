@@ -6,6 +6,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"os/user"
@@ -13,20 +14,10 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/candiddev/shared/go/errs"
 	"github.com/candiddev/shared/go/logger"
-	"github.com/candiddev/shared/go/types"
-)
-
-// ContainerRuntime is an enum for determining which runtime to use.
-type ContainerRuntime string
-
-// ContainerRuntime is an enum for determining which runtime to use.
-const (
-	ContainerRuntimeNone   ContainerRuntime = ""
-	ContainerRuntimeDocker ContainerRuntime = "docker"
-	ContainerRuntimePodman ContainerRuntime = "podman"
 )
 
 var (
@@ -35,6 +26,9 @@ var (
 	ErrRunLookupUser  = errors.New("error looking up user")
 )
 
+// runWaitDelay is how long to wait for output after a command times out.
+const runWaitDelay = time.Second
+
 // CmdOutput is a string of the command exec output.
 type CmdOutput string
 
@@ -46,28 +40,23 @@ func (c CmdOutput) String() string {
 	return ""
 }
 
-func getContainerRuntime() (ContainerRuntime, error) {
-	_, err := exec.LookPath("podman")
-	if err != nil {
-		_, err := exec.LookPath("docker")
-		if err != nil {
-			return ContainerRuntimeNone, errors.New("no container runtime found")
-		}
-
-		return ContainerRuntimeDocker, nil
-	}
-
-	return ContainerRuntimePodman, nil
-}
-
 // RunOpts are options for running a CLI command.
 type RunOpts struct {
 	Args                []string
 	Command             string
+	ContainerCPUs       string
 	ContainerEntrypoint string
+	ContainerEnvFiles   []string
+	ContainerExec       string
 	ContainerImage      string
+	ContainerLabels     []string
+	ContainerMemory     string
+	ContainerName       string
+	ContainerNetwork    string
 	ContainerPull       string
 	ContainerPrivileged bool
+	ContainerReadOnly   bool
+	ContainerTmpfs      []string
 	ContainerUser       string
 	ContainerVolumes    []string
 	ContainerWorkDir    string
@@ -78,80 +67,129 @@ type RunOpts struct {
 	User                string
 	Stdin               string
 	WorkDir             string
+
+	/* Sandbox runs the command within Linux namespaces instead of a container, see RunSandbox */
+	Sandbox *RunSandbox
+
+	/* StdinReader is used for stdin instead of Stdin if set */
+	StdinReader io.Reader
+
+	/* StreamLogger logs each line of output using logger.Info while the command runs */
+	StreamLogger bool
+
+	/* StreamWriter receives stdout and stderr while the command runs */
+	StreamWriter io.Writer
+
+	/* Timeout stops the command and any processes it started after a duration */
+	Timeout time.Duration
+
+	sandboxRoot string
 }
 
-func (r *RunOpts) getCmd(ctx context.Context) (*exec.Cmd, errs.Err) {
-	var args []string
+// RunResult is the result of running a CLI command.
+type RunResult struct {
+	Duration time.Duration
+	ExitCode int
 
-	var cmd string
+	/* Output is stdout and stderr combined in the order they were written */
+	Output CmdOutput
+	Stderr CmdOutput
+	Stdout CmdOutput
+}
 
-	if r.ContainerImage == "" {
-		cmd = r.Command
-		args = r.Args
-	} else {
-		cri, err := getContainerRuntime()
-		if err != nil {
-			return nil, errs.ErrReceiver.Wrap(err)
-		}
+// runWriter synchronizes writes to the combined output and streams.
+type runWriter struct {
+	ctx     context.Context //nolint:containedctx
+	line    []byte
+	logger  bool
+	mutex   *sync.Mutex
+	output  *bytes.Buffer
+	writers []io.Writer
+}
 
-		if cri != "" {
-			cmd = string(cri)
-			args = []string{
-				"run",
-				"-i",
-				"--rm",
-				"--name",
-				fmt.Sprintf("etcha_%s", types.RandString(10)),
-			}
+func (r *runWriter) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 
-			if len(r.Environment) > 0 {
-				for i := range r.Environment {
-					args = append(args, "-e"+r.Environment[i])
-				}
-			}
+	r.output.Write(p)
 
-			if r.ContainerEntrypoint != "" {
-				args = append(args, "--entrypoint", r.ContainerEntrypoint)
-			}
+	for _, w := range r.writers {
+		w.Write(p) //nolint:errcheck
+	}
 
-			if r.ContainerPrivileged {
-				args = append(args, "--privileged")
-			}
+	if r.logger {
+		r.line = append(r.line, p...)
 
-			if r.ContainerPull != "" {
-				args = append(args, "--pull", r.ContainerPull)
+		for {
+			i := bytes.IndexByte(r.line, '\n')
+			if i < 0 {
+				break
 			}
 
-			if r.ContainerUser != "" {
-				args = append(args, "-u", r.ContainerUser)
-			}
+			logger.Info(r.ctx, string(r.line[:i]))
+			r.line = r.line[i+1:]
+		}
+	}
 
-			for i := range r.ContainerVolumes {
-				args = append(args, "-v", r.ContainerVolumes[i])
-			}
+	return len(p), nil
+}
 
-			if r.ContainerWorkDir != "" {
-				args = append(args, "-w", r.ContainerWorkDir)
-			}
+func (r *runWriter) flush() {
+	if r.logger && len(r.line) > 0 {
+		logger.Info(r.ctx, string(r.line))
+		r.line = nil
+	}
+}
 
-			args = append(args, r.ContainerImage)
+func (r *RunOpts) getCmd(ctx context.Context, backend ContainerBackend) (*exec.Cmd, errs.Err) {
+	if r.Sandbox != nil {
+		return r.sandboxCmd(ctx)
+	}
 
-			if r.Command != "" {
-				args = append(args, r.Command)
-			}
+	if r.ContainerImage == "" && r.ContainerExec == "" {
+		return exec.CommandContext(ctx, r.Command, r.Args...), nil
+	}
 
-			args = append(args, r.Args...)
+	if backend == nil {
+		cri, err := getContainerRuntime()
+		if err != nil {
+			return nil, errs.ErrReceiver.Wrap(err)
 		}
+
+		backend = NewContainerBackend(cri)
+	}
+
+	cmd, args, err := backend.Command(*r)
+	if err != nil {
+		return nil, errs.ErrReceiver.Wrap(err)
 	}
 
 	return exec.CommandContext(ctx, cmd, args...), nil
 }
 
-// Run uses RunOpts to run CLI commands.
-func (c *Config) Run(ctx context.Context, opts RunOpts) (out CmdOutput, err errs.Err) { //nolint:gocognit
-	cmd, err := opts.getCmd(ctx)
+// Run uses RunOpts to run CLI commands and returns the combined stdout and stderr.
+func (c *Config) Run(ctx context.Context, opts RunOpts) (out CmdOutput, err errs.Err) {
+	r, err := c.RunWithResult(ctx, opts)
+
+	return r.Output, err
+}
+
+// RunWithResult uses RunOpts to run CLI commands and returns a RunResult.
+func (c *Config) RunWithResult(ctx context.Context, opts RunOpts) (result RunResult, err errs.Err) { //nolint:gocognit,gocyclo
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	cmd, err := opts.getCmd(ctx, c.container)
+	if opts.sandboxRoot != "" {
+		defer os.Remove(opts.sandboxRoot)
+	}
+
 	if err != nil {
-		return "", logger.Error(ctx, errs.ErrReceiver.Wrap(err))
+		return result, logger.Error(ctx, err)
 	}
 
 	var e error
@@ -163,12 +201,12 @@ func (c *Config) Run(ctx context.Context, opts RunOpts) (out CmdOutput, err errs
 		if e != nil {
 			g, e := user.Lookup(opts.Group)
 			if e != nil {
-				return "", logger.Error(ctx, errs.ErrReceiver.Wrap(fmt.Errorf("%w: %s", ErrRunLookupGroup, opts.Group)))
+				return result, logger.Error(ctx, errs.ErrReceiver.Wrap(fmt.Errorf("%w: %s", ErrRunLookupGroup, opts.Group)))
 			}
 
 			gid, e = strconv.ParseUint(g.Gid, 10, 32)
 			if e != nil {
-				return "", logger.Error(ctx, errs.ErrReceiver.Wrap(fmt.Errorf("%w: %s", ErrRunLookupUser, opts.Group)))
+				return result, logger.Error(ctx, errs.ErrReceiver.Wrap(fmt.Errorf("%w: %s", ErrRunLookupUser, opts.Group)))
 			}
 		}
 
@@ -184,12 +222,12 @@ func (c *Config) Run(ctx context.Context, opts RunOpts) (out CmdOutput, err errs
 		if e != nil {
 			u, e := user.Lookup(opts.User)
 			if e != nil {
-				return "", logger.Error(ctx, errs.ErrReceiver.Wrap(fmt.Errorf("%w: %s", ErrRunLookupUser, opts.User)))
+				return result, logger.Error(ctx, errs.ErrReceiver.Wrap(fmt.Errorf("%w: %s", ErrRunLookupUser, opts.User)))
 			}
 
 			uid, e = strconv.ParseUint(u.Uid, 10, 32)
 			if e != nil {
-				return "", logger.Error(ctx, errs.ErrReceiver.Wrap(fmt.Errorf("%w: %s", ErrRunLookupUser, opts.User)))
+				return result, logger.Error(ctx, errs.ErrReceiver.Wrap(fmt.Errorf("%w: %s", ErrRunLookupUser, opts.User)))
 			}
 		}
 
@@ -206,128 +244,115 @@ func (c *Config) Run(ctx context.Context, opts RunOpts) (out CmdOutput, err errs
 		}
 	}
 
+	if opts.Timeout > 0 {
+		// Commands run in their own process group so the timeout stops any children that are still holding stdout and stderr.
+		if cmd.SysProcAttr == nil {
+			cmd.SysProcAttr = &syscall.SysProcAttr{}
+		}
+
+		cmd.SysProcAttr.Setpgid = true
+		cmd.Cancel = func() error {
+			return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		}
+		cmd.WaitDelay = runWaitDelay
+	}
+
 	cmd.Dir = opts.WorkDir
 
-	if opts.Stdin != "" {
+	if opts.StdinReader != nil {
+		cmd.Stdin = opts.StdinReader
+	} else if opts.Stdin != "" {
 		b := bytes.NewBufferString(opts.Stdin)
 		cmd.Stdin = b
 	}
 
 	logger.Debug(ctx, "Running commands:\n"+cmd.String())
 
-	var o []byte
-
-	if c.runMockEnable {
-		c.runMockLock()
+	var output, stderr, stdout bytes.Buffer
 
-		if len(c.runMock.errs) > 0 {
-			e = c.runMock.errs[0]
-		}
+	m := &sync.Mutex{}
+	streams := []io.Writer{}
 
-		if len(c.runMock.outputs) > 0 {
-			o = []byte(c.runMock.outputs[0])
-		}
+	if opts.StreamWriter != nil {
+		streams = append(streams, opts.StreamWriter)
+	}
 
-		if len(c.runMock.errs) > 0 {
-			c.runMock.errs = c.runMock.errs[1:]
-		} else {
-			c.runMock.errs = nil
-		}
+	wErr := &runWriter{
+		ctx:     logger.SetAttribute(ctx, "stream", "stderr"),
+		logger:  opts.StreamLogger,
+		mutex:   m,
+		output:  &output,
+		writers: append([]io.Writer{&stderr}, streams...),
+	}
+	wOut := &runWriter{
+		ctx:     logger.SetAttribute(ctx, "stream", "stdout"),
+		logger:  opts.StreamLogger,
+		mutex:   m,
+		output:  &output,
+		writers: append([]io.Writer{&stdout}, streams...),
+	}
 
-		gid := uint32(0)
-		uid := uint32(0)
+	start := time.Now()
 
-		if cmd.SysProcAttr != nil && cmd.SysProcAttr.Credential != nil {
-			gid = cmd.SysProcAttr.Credential.Gid
-			uid = cmd.SysProcAttr.Credential.Uid
-		}
+	if c.runMockEnable {
+		var r RunResult
 
-		c.runMock.inputs = append(c.runMock.inputs, RunMockInput{
-			Environment: opts.Environment,
-			Exec:        cmd.String(),
-			GID:         gid,
-			UID:         uid,
-			WorkDir:     opts.WorkDir,
-		})
+		r, e = c.runMockRun(opts, cmd)
 
-		if len(c.runMock.outputs) > 0 {
-			c.runMock.outputs = c.runMock.outputs[1:]
+		if r.Stdout == "" && r.Stderr == "" {
+			wOut.Write([]byte(r.Output)) //nolint:errcheck
 		} else {
-			c.runMock.outputs = nil
+			wOut.Write([]byte(r.Stdout)) //nolint:errcheck
+			wErr.Write([]byte(r.Stderr)) //nolint:errcheck
 		}
 
-		c.runMock.mutex.Unlock()
+		result.Duration = r.Duration
+		result.ExitCode = r.ExitCode
 	} else {
 		if opts.EnvironmentInherit {
-			cmd.Env = os.Environ()
+			cmd.Env = append(cmd.Env, os.Environ()...)
 		}
 
 		cmd.Env = append(cmd.Env, opts.Environment...)
-		o, e = cmd.CombinedOutput()
-	}
+		cmd.Stderr = wErr
+		cmd.Stdout = wOut
+		e = cmd.Run()
 
-	out = CmdOutput(o)
+		if opts.Sandbox != nil {
+			e = sandboxError(e)
+		}
 
-	if e != nil {
-		err := errs.ErrReceiver.Wrap(ErrRun, e)
+		result.Duration = time.Since(start)
 
-		if !opts.NoErrorLog {
-			logger.Error(ctx, err, out.String()) //nolint:errcheck
+		if cmd.ProcessState != nil {
+			result.ExitCode = cmd.ProcessState.ExitCode()
+		} else if e != nil {
+			result.ExitCode = -1
 		}
 
-		return CmdOutput(o), err
+		if e != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			e = fmt.Errorf("%w: %w", context.DeadlineExceeded, e)
+		}
 	}
 
-	return out, logger.Error(ctx, err, out.String())
-}
-
-// RunMockInput is a log of things that were inputted into the RunMock.
-type RunMockInput struct {
-	Environment []string
-	Exec        string
-	GID         uint32
-	UID         uint32
-	WorkDir     string
-}
+	wErr.flush()
+	wOut.flush()
 
-// RunMock makes the CLI Run use a mock.
-func (c *Config) RunMock() {
-	c.runMockEnable = true
-	c.runMock = &runMock{}
-}
+	result.Output = CmdOutput(output.String())
+	result.Stderr = CmdOutput(stderr.String())
+	result.Stdout = CmdOutput(stdout.String())
 
-// RunMockErrors sets errors to respond to a CLI Run command.
-func (c *Config) RunMockErrors(err []error) {
-	c.runMockLock()
-	c.runMock.errs = err
-	c.runMock.mutex.Unlock()
-}
-
-// RunMockInputs returns a list of RunMockInputs.
-func (c *Config) RunMockInputs() []RunMockInput {
-	c.runMockLock()
-	defer c.runMock.mutex.Unlock()
-
-	i := c.runMock.inputs
-
-	c.runMock.inputs = nil
-
-	return i
-}
+	if e != nil {
+		err := errs.ErrReceiver.Wrap(ErrRun, e)
 
-// RunMockOutputs sets the outputs to respond to a CLI Run command.
-func (c *Config) RunMockOutputs(outputs []string) {
-	c.runMockLock()
-	c.runMock.outputs = outputs
-	c.runMock.mutex.Unlock()
-}
+		if !opts.NoErrorLog {
+			logger.Error(ctx, err, result.Output.String()) //nolint:errcheck
+		}
 
-func (c *Config) runMockLock() {
-	if c.runMock.mutex == nil {
-		c.runMock.mutex = &sync.Mutex{}
+		return result, err
 	}
 
-	c.runMock.mutex.Lock()
+	return result, logger.Error(ctx, err, result.Output.String())
 }
 
 // RunMain wraps a main function with args to parse the output.
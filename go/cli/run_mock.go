@@ -0,0 +1,273 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrRunMockUnexpected = errors.New("unexpected command")
+
+var ErrRunMockVerify = errors.New("run mock expectations were not met")
+
+// RunMockExpectation matches CLI Run commands and responds with a Result.  Empty fields match any value.
+type RunMockExpectation struct {
+	/* Args must equal the command arguments */
+	Args []string
+
+	/* Command must equal the command */
+	Command string
+
+	/* Environment values must all be present in the command environment */
+	Environment []string
+
+	/* Err is returned by Run */
+	Err error
+
+	/* ExecRegex must match the full command line, like RunMockInput.Exec */
+	ExecRegex string
+
+	/* Group must equal the command group */
+	Group string
+
+	/* Result is returned by Run.  A non-zero ExitCode returns an error if Err is nil */
+	Result RunResult
+
+	/* Times is the number of calls expected by RunMockVerify.  Zero expects at least one call */
+	Times int
+
+	/* User must equal the command user */
+	User string
+
+	/* WorkDir must equal the command working directory */
+	WorkDir string
+
+	calls int
+	regex *regexp.Regexp
+}
+
+// Calls returns the number of times the expectation was matched.
+func (r *RunMockExpectation) Calls() int {
+	return r.calls
+}
+
+func (r *RunMockExpectation) String() string {
+	s := []string{}
+
+	if r.Command != "" {
+		s = append(s, "command="+r.Command)
+	}
+
+	if r.Args != nil {
+		s = append(s, fmt.Sprintf("args=%q", r.Args))
+	}
+
+	if r.ExecRegex != "" {
+		s = append(s, "exec=~"+r.ExecRegex)
+	}
+
+	return "{" + strings.Join(s, " ") + "}"
+}
+
+func (r *RunMockExpectation) match(opts RunOpts, exec string) bool {
+	if r.Args != nil && !slices.Equal(r.Args, opts.Args) {
+		return false
+	}
+
+	if (r.Command != "" && r.Command != opts.Command) || (r.Group != "" && r.Group != opts.Group) || (r.User != "" && r.User != opts.User) || (r.WorkDir != "" && r.WorkDir != opts.WorkDir) {
+		return false
+	}
+
+	for _, e := range r.Environment {
+		if !slices.Contains(opts.Environment, e) {
+			return false
+		}
+	}
+
+	return r.regex == nil || r.regex.MatchString(exec)
+}
+
+// RunMockInput is a log of things that were inputted into the RunMock.
+type RunMockInput struct {
+	Environment []string
+	Exec        string
+	GID         uint32
+	Stdin       string
+	Timeout     time.Duration
+	UID         uint32
+	WorkDir     string
+}
+
+// RunMock makes the CLI Run use a mock.
+func (c *Config) RunMock() {
+	c.runMockEnable = true
+	c.runMock = &runMock{}
+}
+
+// RunMockErrors sets errors to respond to a CLI Run command.
+func (c *Config) RunMockErrors(err []error) {
+	c.runMockLock()
+	c.runMock.errs = err
+	c.runMock.mutex.Unlock()
+}
+
+// RunMockExpect adds an expectation to the RunMock.  Commands are matched against the expectations in the order they were added, preferring expectations that haven't been called Times, before falling back to RunMockOutputs and RunMockErrors.
+func (c *Config) RunMockExpect(e RunMockExpectation) *RunMockExpectation {
+	if e.ExecRegex != "" {
+		e.regex = regexp.MustCompile(e.ExecRegex)
+	}
+
+	c.runMockLock()
+	c.runMock.expectations = append(c.runMock.expectations, &e)
+	c.runMock.mutex.Unlock()
+
+	return &e
+}
+
+// RunMockInputs returns a list of RunMockInputs.
+func (c *Config) RunMockInputs() []RunMockInput {
+	c.runMockLock()
+	defer c.runMock.mutex.Unlock()
+
+	i := c.runMock.inputs
+
+	c.runMock.inputs = nil
+
+	return i
+}
+
+// RunMockOutputs sets the outputs to respond to a CLI Run command.
+func (c *Config) RunMockOutputs(outputs []string) {
+	r := make([]RunResult, len(outputs))
+
+	for i := range outputs {
+		r[i].Output = CmdOutput(outputs[i])
+	}
+
+	c.RunMockResults(r)
+}
+
+// RunMockResults sets the RunResults to respond to a CLI Run command.  If Stdout and Stderr are empty, Output is used as stdout.  A non-zero ExitCode returns an error if RunMockErrors doesn't provide one.
+func (c *Config) RunMockResults(results []RunResult) {
+	c.runMockLock()
+	c.runMock.results = results
+	c.runMock.mutex.Unlock()
+}
+
+// RunMockVerify returns an error if an expectation wasn't called the expected number of times, or if a command didn't match any expectations or outputs.
+func (c *Config) RunMockVerify() error {
+	c.runMockLock()
+	defer c.runMock.mutex.Unlock()
+
+	e := []string{}
+
+	for _, x := range c.runMock.expectations {
+		if x.Times == 0 && x.calls == 0 {
+			e = append(e, fmt.Sprintf("expectation %s was not called", x))
+		} else if x.Times > 0 && x.calls != x.Times {
+			e = append(e, fmt.Sprintf("expectation %s called %d times, want %d", x, x.calls, x.Times))
+		}
+	}
+
+	for _, u := range c.runMock.unexpected {
+		e = append(e, fmt.Sprintf("%s: %s", ErrRunMockUnexpected, u))
+	}
+
+	if len(e) > 0 {
+		return fmt.Errorf("%w:\n%s", ErrRunMockVerify, strings.Join(e, "\n"))
+	}
+
+	return nil
+}
+
+func (c *Config) runMockLock() {
+	if c.runMock.mutex == nil {
+		c.runMock.mutex = &sync.Mutex{}
+	}
+
+	c.runMock.mutex.Lock()
+}
+
+// runMockRun records a command and returns the result of the matching expectation, or the next RunMockResults and RunMockErrors.
+func (c *Config) runMockRun(opts RunOpts, cmd *exec.Cmd) (RunResult, error) {
+	c.runMockLock()
+	defer c.runMock.mutex.Unlock()
+
+	gid := uint32(0)
+	uid := uint32(0)
+
+	if cmd.SysProcAttr != nil && cmd.SysProcAttr.Credential != nil {
+		gid = cmd.SysProcAttr.Credential.Gid
+		uid = cmd.SysProcAttr.Credential.Uid
+	}
+
+	stdin := ""
+
+	if cmd.Stdin != nil {
+		b, _ := io.ReadAll(cmd.Stdin)
+		stdin = string(b)
+	}
+
+	c.runMock.inputs = append(c.runMock.inputs, RunMockInput{
+		Environment: opts.Environment,
+		Exec:        cmd.String(),
+		GID:         gid,
+		Stdin:       stdin,
+		Timeout:     opts.Timeout,
+		UID:         uid,
+		WorkDir:     opts.WorkDir,
+	})
+
+	// Prefer expectations that haven't been called Times, otherwise use the first match so it fails verification.
+	var match *RunMockExpectation
+
+	for _, x := range c.runMock.expectations {
+		if x.match(opts, cmd.String()) {
+			if x.Times == 0 || x.calls < x.Times {
+				match = x
+
+				break
+			}
+
+			if match == nil {
+				match = x
+			}
+		}
+	}
+
+	var e error
+
+	var r RunResult
+
+	switch {
+	case match != nil:
+		match.calls++
+		e = match.Err
+		r = match.Result
+	case len(c.runMock.errs) > 0 || len(c.runMock.results) > 0:
+		if len(c.runMock.errs) > 0 {
+			e = c.runMock.errs[0]
+			c.runMock.errs = c.runMock.errs[1:]
+		}
+
+		if len(c.runMock.results) > 0 {
+			r = c.runMock.results[0]
+			c.runMock.results = c.runMock.results[1:]
+		}
+	case len(c.runMock.expectations) > 0:
+		c.runMock.unexpected = append(c.runMock.unexpected, cmd.String())
+		e = fmt.Errorf("%w: %s", ErrRunMockUnexpected, cmd.String())
+	}
+
+	if e == nil && r.ExitCode != 0 {
+		e = fmt.Errorf("exit status %d", r.ExitCode)
+	}
+
+	return r, e
+}
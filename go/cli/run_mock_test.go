@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/logger"
+)
+
+func TestRunMockExpect(t *testing.T) {
+	ctx := logger.UseTestLogger(t)
+
+	c := Config{}
+	c.RunMock()
+
+	status := c.RunMockExpect(RunMockExpectation{
+		Args: []string{
+			"-a",
+		},
+		Command: "ls",
+		Result: RunResult{
+			Stdout: "clean",
+		},
+		Times: 2,
+	})
+	env := c.RunMockExpect(RunMockExpectation{
+		Command: "cat",
+		Environment: []string{
+			"A=b",
+		},
+		Result: RunResult{
+			ExitCode: 2,
+			Stderr:   "failed",
+		},
+	})
+	regex := c.RunMockExpect(RunMockExpectation{
+		Err:       errors.New("error"),
+		ExecRegex: `/rm -rf /tmp/\S+$`,
+	})
+	unused := c.RunMockExpect(RunMockExpectation{
+		Command: "unused",
+		User:    "root",
+	})
+
+	tests := map[string]struct {
+		opts       RunOpts
+		wantErr    error
+		wantResult RunResult
+	}{
+		"-a": {
+			opts: RunOpts{
+				Args: []string{
+					"-a",
+				},
+				Command: "ls",
+			},
+			wantResult: RunResult{
+				Output: "clean",
+				Stdout: "clean",
+			},
+		},
+		"status again": {
+			opts: RunOpts{
+				Args: []string{
+					"-a",
+				},
+				Command: "ls",
+			},
+			wantResult: RunResult{
+				Output: "clean",
+				Stdout: "clean",
+			},
+		},
+		"wrong args": {
+			opts: RunOpts{
+				Args: []string{
+					"-l",
+				},
+				Command: "ls",
+			},
+			wantErr: ErrRun,
+		},
+		"env": {
+			opts: RunOpts{
+				Command: "cat",
+				Environment: []string{
+					"C=d",
+					"A=b",
+				},
+			},
+			wantErr: ErrRun,
+			wantResult: RunResult{
+				ExitCode: 2,
+				Output:   "failed",
+				Stderr:   "failed",
+			},
+		},
+		"env missing": {
+			opts: RunOpts{
+				Command: "cat",
+			},
+			wantErr: ErrRun,
+		},
+		"regex": {
+			opts: RunOpts{
+				Args: []string{
+					"-rf",
+					"/tmp/a",
+				},
+				Command: "rm",
+			},
+			wantErr: ErrRun,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, err := c.RunWithResult(ctx, tc.opts)
+			assert.HasErr(t, err, tc.wantErr)
+			assert.Equal(t, r, tc.wantResult)
+		})
+	}
+
+	assert.Equal(t, status.Calls(), 2)
+	assert.Equal(t, env.Calls(), 1)
+	assert.Equal(t, regex.Calls(), 1)
+	assert.Equal(t, unused.Calls(), 0)
+	assert.Equal(t, len(c.RunMockInputs()), 6)
+
+	err := c.RunMockVerify()
+	assert.HasErr(t, err, ErrRunMockVerify)
+	assert.Contains(t, err.Error(), "expectation {command=unused} was not called\n")
+	assert.Contains(t, err.Error(), "unexpected command: /usr/bin/ls -l")
+	assert.Contains(t, err.Error(), "unexpected command: /usr/bin/cat")
+
+	// Calling more than Times fails verification.
+	c.RunMock()
+	c.RunMockExpect(RunMockExpectation{
+		Command: "ls",
+		Times:   1,
+	})
+	c.RunMockOutputs([]string{
+		"fallback",
+	})
+
+	out, err := c.Run(ctx, RunOpts{
+		Command: "ls",
+	})
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, out, "")
+
+	out, err = c.Run(ctx, RunOpts{
+		Command: "ls",
+	})
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, out, "")
+
+	out, err = c.Run(ctx, RunOpts{
+		Command: "other",
+	})
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, out, "fallback")
+
+	assert.HasErr(t, c.RunMockVerify(), ErrRunMockVerify)
+}
@@ -0,0 +1,339 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/candiddev/shared/go/errs"
+	"github.com/candiddev/shared/go/logger"
+	"github.com/candiddev/shared/go/types"
+)
+
+var (
+	ErrRunTaskCanceled  = errors.New("task canceled")
+	ErrRunTaskCycle     = errors.New("task dependency cycle")
+	ErrRunTaskDuplicate = errors.New("duplicate task name")
+	ErrRunTaskUnknown   = errors.New("unknown task dependency")
+	ErrRunTasks         = errors.New("error running tasks")
+)
+
+// RunTaskStatus is the status of a RunTask after RunTasks.
+type RunTaskStatus string
+
+// RunTaskStatus values.
+const (
+	RunTaskStatusCanceled  RunTaskStatus = "canceled"
+	RunTaskStatusFailed    RunTaskStatus = "failed"
+	RunTaskStatusSucceeded RunTaskStatus = "succeeded"
+)
+
+// RunTask is a named RunOpts within RunTasksOpts.
+type RunTask struct {
+	/* DependsOn are the names of tasks that must succeed before this task runs */
+	DependsOn []string
+
+	/* Name is a unique name of the task, used to prefix the task output */
+	Name string
+
+	/* Opts are the RunOpts of the task.  Opts.StreamWriter will receive the output without the prefix */
+	Opts RunOpts
+}
+
+// RunTasksOpts are options for running a graph of RunTasks.
+type RunTasksOpts struct {
+	/* Concurrency is the maximum number of tasks running at once.  Defaults to the number of CPUs */
+	Concurrency int
+
+	/* FailFast cancels all running and pending tasks after a task fails, instead of only the tasks that depend on it */
+	FailFast bool
+
+	/* StreamWriter receives the prefixed output of each task while it runs */
+	StreamWriter io.Writer
+
+	/* Tasks is the list of tasks to run */
+	Tasks []RunTask
+}
+
+// RunTaskResult is the result of a RunTask.
+type RunTaskResult struct {
+	Err    errs.Err
+	Name   string
+	Result RunResult
+	Status RunTaskStatus
+}
+
+// RunTasksResult is the result of RunTasks.
+type RunTasksResult struct {
+	/* Output is the combined output of all tasks in the order it was written, with each line prefixed by the task name */
+	Output CmdOutput
+
+	/* Tasks are the results of each task, in the same order as RunTasksOpts.Tasks */
+	Tasks []RunTaskResult
+}
+
+// Results returns a summary of each task, including the output of failed tasks.
+func (r RunTasksResult) Results() types.Results {
+	out := types.Results{}
+
+	for _, t := range r.Tasks {
+		s := string(t.Status)
+
+		switch t.Status {
+		case RunTaskStatusCanceled:
+			s += ": " + t.Err.Error()
+		case RunTaskStatusFailed:
+			s += fmt.Sprintf(" in %s: %s", t.Result.Duration.Round(time.Millisecond), t.Err)
+		case RunTaskStatusSucceeded:
+			s += fmt.Sprintf(" in %s", t.Result.Duration.Round(time.Millisecond))
+		}
+
+		out[t.Name] = []string{
+			s,
+		}
+
+		if t.Status == RunTaskStatusFailed && t.Result.Output.String() != "" {
+			out[t.Name] = append(out[t.Name], strings.Split(t.Result.Output.String(), "\n")...)
+		}
+	}
+
+	return out
+}
+
+// runTasksWriter prefixes each line written by a task.
+type runTasksWriter struct {
+	line   []byte
+	mutex  *sync.Mutex
+	output *bytes.Buffer
+	prefix string
+	stream io.Writer
+}
+
+func (r *runTasksWriter) Write(p []byte) (int, error) {
+	r.line = append(r.line, p...)
+
+	for {
+		i := bytes.IndexByte(r.line, '\n')
+		if i < 0 {
+			break
+		}
+
+		r.write(r.line[:i+1])
+		r.line = r.line[i+1:]
+	}
+
+	return len(p), nil
+}
+
+func (r *runTasksWriter) flush() {
+	if len(r.line) > 0 {
+		r.write(append(r.line, '\n'))
+		r.line = nil
+	}
+}
+
+func (r *runTasksWriter) write(line []byte) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	l := append([]byte(r.prefix), line...)
+
+	r.output.Write(l)
+
+	if r.stream != nil {
+		r.stream.Write(l) //nolint:errcheck
+	}
+}
+
+// RunTasks runs a graph of RunTasks using Run.  Tasks run once all of their dependencies succeed, with at most Concurrency tasks running at once.  Tasks that depend on a failed task are canceled.
+func (c *Config) RunTasks(ctx context.Context, opts RunTasksOpts) (RunTasksResult, errs.Err) { //nolint:gocognit
+	result := RunTasksResult{
+		Tasks: make([]RunTaskResult, len(opts.Tasks)),
+	}
+
+	if err := runTasksValidate(opts.Tasks); err != nil {
+		return result, logger.Error(ctx, err)
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := map[string]chan struct{}{}
+	index := map[string]int{}
+	width := 0
+
+	for i, t := range opts.Tasks {
+		done[t.Name] = make(chan struct{})
+		index[t.Name] = i
+
+		if len(t.Name) > width {
+			width = len(t.Name)
+		}
+	}
+
+	var output bytes.Buffer
+
+	m := &sync.Mutex{}
+	sem := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+
+	for i := range opts.Tasks {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			t := opts.Tasks[i]
+			r := &result.Tasks[i]
+			r.Name = t.Name
+
+			defer close(done[t.Name])
+
+			for _, d := range t.DependsOn {
+				<-done[d]
+
+				if s := result.Tasks[index[d]].Status; s != RunTaskStatusSucceeded {
+					r.Err = errs.ErrReceiver.Wrap(ErrRunTaskCanceled, fmt.Errorf("dependency %s %s", d, s))
+					r.Status = RunTaskStatusCanceled
+
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				r.Err = errs.ErrReceiver.Wrap(ErrRunTaskCanceled, ctx.Err())
+				r.Status = RunTaskStatusCanceled
+
+				return
+			case sem <- struct{}{}:
+			}
+
+			defer func() {
+				<-sem
+			}()
+
+			if ctx.Err() != nil {
+				r.Err = errs.ErrReceiver.Wrap(ErrRunTaskCanceled, ctx.Err())
+				r.Status = RunTaskStatusCanceled
+
+				return
+			}
+
+			w := &runTasksWriter{
+				mutex:  m,
+				output: &output,
+				prefix: fmt.Sprintf("%-*s | ", width, t.Name),
+				stream: opts.StreamWriter,
+			}
+
+			o := t.Opts
+			if o.StreamWriter != nil {
+				o.StreamWriter = io.MultiWriter(o.StreamWriter, w)
+			} else {
+				o.StreamWriter = w
+			}
+
+			r.Result, r.Err = c.RunWithResult(logger.SetAttribute(ctx, "task", t.Name), o)
+
+			w.flush()
+
+			switch {
+			case r.Err == nil:
+				r.Status = RunTaskStatusSucceeded
+			case ctx.Err() != nil:
+				r.Status = RunTaskStatusCanceled
+			default:
+				r.Status = RunTaskStatusFailed
+
+				if opts.FailFast {
+					cancel()
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	result.Output = CmdOutput(output.String())
+
+	failed := []string{}
+
+	for _, t := range result.Tasks {
+		if t.Status != RunTaskStatusSucceeded {
+			failed = append(failed, t.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return result, logger.Error(ctx, errs.ErrReceiver.Wrap(ErrRunTasks, fmt.Errorf("tasks did not succeed: %s", strings.Join(failed, ", "))))
+	}
+
+	return result, nil
+}
+
+// runTasksValidate checks that task names are unique, dependencies exist, and there are no cycles.
+func runTasksValidate(tasks []RunTask) errs.Err {
+	deps := map[string][]string{}
+
+	for _, t := range tasks {
+		if _, ok := deps[t.Name]; ok {
+			return errs.ErrSenderBadRequest.Wrap(ErrRunTaskDuplicate, errors.New(t.Name))
+		}
+
+		deps[t.Name] = t.DependsOn
+	}
+
+	for _, t := range tasks {
+		for _, d := range t.DependsOn {
+			if _, ok := deps[d]; !ok {
+				return errs.ErrSenderBadRequest.Wrap(ErrRunTaskUnknown, fmt.Errorf("%s depends on %s", t.Name, d))
+			}
+		}
+	}
+
+	// 1 is visiting, 2 is visited.
+	state := map[string]int{}
+
+	var visit func(name string, path []string) errs.Err
+
+	visit = func(name string, path []string) errs.Err {
+		switch state[name] {
+		case 1:
+			return errs.ErrSenderBadRequest.Wrap(ErrRunTaskCycle, errors.New(strings.Join(append(path, name), " -> ")))
+		case 2:
+			return nil
+		}
+
+		state[name] = 1
+
+		for _, d := range deps[name] {
+			if err := visit(d, append(path, name)); err != nil {
+				return err
+			}
+		}
+
+		state[name] = 2
+
+		return nil
+	}
+
+	for _, t := range tasks {
+		if err := visit(t.Name, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
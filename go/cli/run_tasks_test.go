@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/logger"
+)
+
+func TestRunTasks(t *testing.T) {
+	ctx := logger.UseTestLogger(t)
+
+	c := Config{}
+
+	var stream strings.Builder
+
+	tests := map[string]struct {
+		opts        RunTasksOpts
+		wantErr     error
+		wantOutput  []string
+		wantResults map[string]string
+	}{
+		"graph": {
+			opts: RunTasksOpts{
+				Concurrency: 2,
+				Tasks: []RunTask{
+					{
+						Name: "build",
+						Opts: RunOpts{
+							Args: []string{
+								"-c",
+								"echo built",
+							},
+							Command: "sh",
+						},
+					},
+					{
+						DependsOn: []string{
+							"build",
+						},
+						Name: "test",
+						Opts: RunOpts{
+							Args: []string{
+								"-c",
+								"echo fail; echo line >&2; exit 1",
+							},
+							Command:    "sh",
+							NoErrorLog: true,
+						},
+					},
+					{
+						DependsOn: []string{
+							"build",
+						},
+						Name: "lint",
+						Opts: RunOpts{
+							Args: []string{
+								"-c",
+								"printf linted",
+							},
+							Command: "sh",
+						},
+					},
+					{
+						DependsOn: []string{
+							"lint",
+							"test",
+						},
+						Name: "deploy",
+						Opts: RunOpts{
+							Command: "true",
+						},
+					},
+				},
+			},
+			wantErr: ErrRunTasks,
+			wantOutput: []string{
+				"build  | built\n",
+				"lint   | linted\n",
+				"test   | fail\n",
+				"test   | line\n",
+			},
+			wantResults: map[string]string{
+				"build":  "succeeded",
+				"deploy": "canceled: task canceled: dependency test failed",
+				"lint":   "succeeded",
+				"test":   "failed",
+			},
+		},
+		"fail_fast": {
+			opts: RunTasksOpts{
+				Concurrency: 2,
+				FailFast:    true,
+				Tasks: []RunTask{
+					{
+						Name: "a",
+						Opts: RunOpts{
+							Command:    "false",
+							NoErrorLog: true,
+						},
+					},
+					{
+						Name: "b",
+						Opts: RunOpts{
+							Args: []string{
+								"5",
+							},
+							Command:    "sleep",
+							NoErrorLog: true,
+						},
+					},
+				},
+			},
+			wantErr: ErrRunTasks,
+			wantResults: map[string]string{
+				"a": "failed",
+				"b": "canceled",
+			},
+		},
+		"cycle": {
+			opts: RunTasksOpts{
+				Tasks: []RunTask{
+					{
+						DependsOn: []string{
+							"b",
+						},
+						Name: "a",
+					},
+					{
+						DependsOn: []string{
+							"a",
+						},
+						Name: "b",
+					},
+				},
+			},
+			wantErr: ErrRunTaskCycle,
+		},
+		"duplicate": {
+			opts: RunTasksOpts{
+				Tasks: []RunTask{
+					{
+						Name: "a",
+					},
+					{
+						Name: "a",
+					},
+				},
+			},
+			wantErr: ErrRunTaskDuplicate,
+		},
+		"unknown": {
+			opts: RunTasksOpts{
+				Tasks: []RunTask{
+					{
+						DependsOn: []string{
+							"b",
+						},
+						Name: "a",
+					},
+				},
+			},
+			wantErr: ErrRunTaskUnknown,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			stream.Reset()
+			tc.opts.StreamWriter = &stream
+
+			r, err := c.RunTasks(ctx, tc.opts)
+			assert.HasErr(t, err, tc.wantErr)
+
+			for _, o := range tc.wantOutput {
+				assert.Contains(t, r.Output.String(), strings.TrimSpace(o))
+				assert.Contains(t, stream.String(), o)
+			}
+
+			res := r.Results()
+
+			for k, v := range tc.wantResults {
+				assert.Equal(t, len(res[k]) > 0, true)
+				assert.Equal(t, strings.HasPrefix(res[k][0], v), true)
+			}
+		})
+	}
+
+	// Concurrency
+	tasks := []RunTask{}
+
+	for _, name := range []string{"a", "b", "c"} {
+		tasks = append(tasks, RunTask{
+			Name: name,
+			Opts: RunOpts{
+				Args: []string{
+					"0.1",
+				},
+				Command: "sleep",
+			},
+		})
+	}
+
+	start := time.Now()
+	_, err := c.RunTasks(ctx, RunTasksOpts{
+		Concurrency: 1,
+		Tasks:       tasks,
+	})
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, time.Since(start) >= 300*time.Millisecond, true)
+
+	c.RunMock()
+
+	c.RunMockExpect(RunMockExpectation{
+		Result: RunResult{
+			Output: "ok",
+		},
+		Times: 3,
+	})
+
+	r, err := c.RunTasks(ctx, RunTasksOpts{
+		Tasks: tasks,
+	})
+	assert.HasErr(t, err, nil)
+	assert.HasErr(t, c.RunMockVerify(), nil)
+	assert.Equal(t, len(r.Output), len("a | ok\nb | ok\nc | ok\n"))
+	assert.Contains(t, string(r.Output), "b | ok\n")
+	assert.Equal(t, r.Results()["c"][0], "succeeded in 0s")
+	assert.Equal(t, r.Tasks[2].Name, "c")
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/candiddev/shared/go/assert"
 	"github.com/candiddev/shared/go/logger"
@@ -137,6 +138,7 @@ func TestRun(t *testing.T) {
 	})
 
 	c.RunMock()
+	c.SetContainerBackend(NewContainerBackend(ContainerRuntimeDocker))
 	c.Run(ctx, RunOpts{
 		Args: []string{
 			"world",
@@ -152,9 +154,7 @@ func TestRun(t *testing.T) {
 		WorkDir:          "/test2",
 	})
 
-	cri, _ := getContainerRuntime()
-
-	assert.Equal(t, regexp.MustCompile(fmt.Sprintf(`^/usr/bin/%s run -i --rm --name etcha_\S+ --privileged -v /a:/a -v /b:/b -w /test1 example hello world$`, cri)).MatchString(c.runMock.inputs[0].Exec), true)
+	assert.Equal(t, regexp.MustCompile(`^(\S+/)?docker run -i --rm --privileged -v /a:/a -v /b:/b -w /test1 example hello world$`).MatchString(c.runMock.inputs[0].Exec), true)
 	assert.Equal(t, c.runMock.inputs[0].WorkDir, "/test2")
 
 	c.runMockEnable = false
@@ -166,3 +166,116 @@ func TestRun(t *testing.T) {
 	assert.Equal(t, out, "hello")
 	assert.HasErr(t, err, nil)
 }
+
+func TestRunWithResult(t *testing.T) {
+	logger.UseTestLogger(t)
+
+	ctx := context.Background()
+	c := Config{}
+
+	var stream strings.Builder
+
+	r, err := c.RunWithResult(ctx, RunOpts{
+		Args: []string{
+			"-c",
+			"echo out; echo err >&2; exit 3",
+		},
+		Command:      "sh",
+		NoErrorLog:   true,
+		StreamWriter: &stream,
+	})
+	assert.HasErr(t, err, ErrRun)
+	assert.Equal(t, r.ExitCode, 3)
+	assert.Equal(t, r.Stderr, "err\n")
+	assert.Equal(t, r.Stdout, "out\n")
+	assert.Equal(t, len(r.Output), 8)
+	assert.Equal(t, len(stream.String()), 8)
+	assert.Equal(t, r.Duration > 0, true)
+
+	r, err = c.RunWithResult(ctx, RunOpts{
+		Command:     "cat",
+		StdinReader: strings.NewReader("hello"),
+	})
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, r.ExitCode, 0)
+	assert.Equal(t, r.Stdout, "hello")
+
+	// The forked sleep holds stdout open after sh is killed.
+	r, err = c.RunWithResult(ctx, RunOpts{
+		Args: []string{
+			"-c",
+			"sleep 4; echo hi",
+		},
+		Command:    "sh",
+		NoErrorLog: true,
+		Timeout:    500 * time.Millisecond,
+	})
+	assert.HasErr(t, err, ErrRun)
+	assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+	assert.Equal(t, r.Duration < 2*time.Second, true)
+	assert.Equal(t, r.Stdout, "")
+
+	logger.SetStd()
+
+	c.RunWithResult(logger.SetLevel(ctx, logger.LevelInfo), RunOpts{
+		Args: []string{
+			"-c",
+			"echo a; echo b",
+		},
+		Command:      "sh",
+		StreamLogger: true,
+	})
+
+	o := logger.ReadStd()
+	assert.Contains(t, o, "a\n")
+	assert.Contains(t, o, "b\n")
+
+	c.RunMock()
+	c.RunMockResults([]RunResult{
+		{
+			Duration: time.Second,
+			ExitCode: 2,
+			Stderr:   "err",
+			Stdout:   "out",
+		},
+		{
+			Output: "output",
+		},
+	})
+
+	stream.Reset()
+
+	r, err = c.RunWithResult(ctx, RunOpts{
+		Command:      "hello",
+		NoErrorLog:   true,
+		Stdin:        "world",
+		StreamWriter: &stream,
+		Timeout:      time.Minute,
+	})
+	assert.HasErr(t, err, ErrRun)
+	assert.Equal(t, r, RunResult{
+		Duration: time.Second,
+		ExitCode: 2,
+		Output:   "outerr",
+		Stderr:   "err",
+		Stdout:   "out",
+	})
+	assert.Equal(t, stream.String(), "outerr")
+
+	out, err := c.Run(ctx, RunOpts{
+		Command: "hello",
+	})
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, out, "output")
+
+	assert.Equal(t, c.RunMockInputs(), []RunMockInput{
+		{
+			Exec:    "hello",
+			Stdin:   "world",
+			Timeout: time.Minute,
+		},
+		{
+			Exec: "hello",
+		},
+	})
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrSandboxOptions = errors.New("invalid sandbox options")
+
+var ErrSandboxUnavailable = errors.New("sandbox requires Linux with unprivileged user namespaces enabled")
+
+// SandboxBindMountsSystem are read-only bind mounts for running most system binaries within a RunSandbox.
+var SandboxBindMountsSystem = []string{ //nolint:gochecknoglobals
+	"/bin",
+	"/etc",
+	"/lib",
+	"/lib32",
+	"/lib64",
+	"/sbin",
+	"/usr",
+}
+
+// RunSandbox runs a command within Linux user, mount, PID, and network namespaces without a container runtime.  The command runs as root within the user namespace, mapped to the current user.  The root filesystem is an empty, read-only tmpfs containing the bind mounts, /dev, /proc, and a private /tmp.
+type RunSandbox struct {
+	/* BindMounts are absolute paths mounted read-only at the same path within the sandbox.  Paths that don't exist are ignored and symlinks are recreated */
+	BindMounts []string
+
+	/* BindMountsWritable are absolute paths mounted read-write at the same path within the sandbox */
+	BindMountsWritable []string
+
+	/* LimitCPU is the maximum CPU time of the command, rounded up to the nearest second */
+	LimitCPU time.Duration
+
+	/* LimitMemory is the maximum virtual memory of the command in bytes */
+	LimitMemory uint64
+
+	/* LimitOpenFiles is the maximum number of open files of the command */
+	LimitOpenFiles uint64
+
+	/* Network shares the host network instead of creating a network namespace without any interfaces */
+	Network bool
+}
@@ -0,0 +1,383 @@
+//go:build linux
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/candiddev/shared/go/errs"
+)
+
+const (
+	sandboxEnv       = "CLI_SANDBOX"
+	sandboxErrorCode = 125
+	stRelatime       = 4096
+)
+
+// sandboxConfig is passed to the sandbox init process using sandboxEnv.
+type sandboxConfig struct {
+	BindMounts         []string
+	BindMountsWritable []string
+	LimitCPU           time.Duration
+	LimitMemory        uint64
+	LimitOpenFiles     uint64
+	Root               string
+	WorkDir            string
+}
+
+// The sandbox re-executes the current binary as PID 1 of the new namespaces to setup the mounts and limits before executing the command.
+func init() { //nolint:gochecknoinits
+	if s := os.Getenv(sandboxEnv); s != "" && os.Getpid() == 1 {
+		if err := sandboxInit(s); err != nil {
+			fmt.Fprintf(os.Stderr, "error setting up sandbox: %s\n", err)
+			os.Exit(sandboxErrorCode)
+		}
+	}
+}
+
+func (r *RunOpts) sandboxCmd(ctx context.Context) (*exec.Cmd, errs.Err) {
+	if r.ContainerExec != "" || r.ContainerImage != "" || r.Group != "" || r.User != "" {
+		return nil, errs.ErrReceiver.Wrap(ErrSandboxOptions, errors.New("sandbox can't be used with containers, Group, or User"))
+	}
+
+	for _, p := range append(append([]string{}, r.Sandbox.BindMounts...), r.Sandbox.BindMountsWritable...) {
+		if !filepath.IsAbs(p) {
+			return nil, errs.ErrReceiver.Wrap(ErrSandboxOptions, fmt.Errorf("bind mount %s must be an absolute path", p))
+		}
+	}
+
+	if err := sandboxAvailable(); err != nil {
+		return nil, err
+	}
+
+	// Lookup the command using the current PATH, like exec.Command.
+	command := r.Command
+	if p, err := exec.LookPath(command); err == nil {
+		command = p
+	}
+
+	root, e := os.MkdirTemp("", "sandbox")
+	if e != nil {
+		return nil, errs.ErrReceiver.Wrap(e)
+	}
+
+	r.sandboxRoot = root
+
+	c, e := json.Marshal(sandboxConfig{
+		BindMounts:         r.Sandbox.BindMounts,
+		BindMountsWritable: r.Sandbox.BindMountsWritable,
+		LimitCPU:           r.Sandbox.LimitCPU,
+		LimitMemory:        r.Sandbox.LimitMemory,
+		LimitOpenFiles:     r.Sandbox.LimitOpenFiles,
+		Root:               root,
+		WorkDir:            r.WorkDir,
+	})
+	if e != nil {
+		return nil, errs.ErrReceiver.Wrap(e)
+	}
+
+	flags := syscall.CLONE_NEWIPC | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID | syscall.CLONE_NEWUSER | syscall.CLONE_NEWUTS
+	if !r.Sandbox.Network {
+		flags |= syscall.CLONE_NEWNET
+	}
+
+	cmd := exec.CommandContext(ctx, "/proc/self/exe", append([]string{command}, r.Args...)...)
+	cmd.Env = []string{
+		sandboxEnv + "=" + string(c),
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: uintptr(flags),
+		GidMappings: []syscall.SysProcIDMap{
+			{
+				ContainerID: 0,
+				HostID:      os.Getgid(),
+				Size:        1,
+			},
+		},
+		GidMappingsEnableSetgroups: false,
+		Pdeathsig:                  syscall.SIGKILL,
+		UidMappings: []syscall.SysProcIDMap{
+			{
+				ContainerID: 0,
+				HostID:      os.Getuid(),
+				Size:        1,
+			},
+		},
+	}
+
+	return cmd, nil
+}
+
+// sandboxAvailable checks the sysctls that disable unprivileged user namespaces.
+func sandboxAvailable() errs.Err {
+	for _, s := range []struct {
+		disabled string
+		path     string
+		root     bool
+	}{
+		{
+			disabled: "0",
+			path:     "/proc/sys/user/max_user_namespaces",
+			root:     true,
+		},
+		{
+			disabled: "0",
+			path:     "/proc/sys/kernel/unprivileged_userns_clone",
+		},
+	} {
+		if !s.root && os.Geteuid() == 0 {
+			continue
+		}
+
+		if b, err := os.ReadFile(s.path); err == nil && strings.TrimSpace(string(b)) == s.disabled {
+			return errs.ErrReceiver.Wrap(ErrSandboxUnavailable, fmt.Errorf("%s is %s", s.path, s.disabled))
+		}
+	}
+
+	return nil
+}
+
+// sandboxError wraps errors from creating the namespaces with ErrSandboxUnavailable.
+func sandboxError(err error) error {
+	var p *os.PathError
+
+	if errors.As(err, &p) && p.Op == "fork/exec" && (errors.Is(p.Err, syscall.EINVAL) || errors.Is(p.Err, syscall.ENOSPC) || errors.Is(p.Err, syscall.EPERM) || errors.Is(p.Err, syscall.EUSERS)) {
+		return fmt.Errorf("%w: %w", ErrSandboxUnavailable, err)
+	}
+
+	return err
+}
+
+// sandboxBind bind mounts path into root.
+func sandboxBind(root, path string, writable bool) error {
+	path = filepath.Clean(path)
+	target := filepath.Join(root, path)
+
+	f, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil { //nolint:gosec
+		return err
+	}
+
+	switch {
+	case f.Mode()&os.ModeSymlink != 0:
+		l, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+
+		return os.Symlink(l, target)
+	case f.IsDir():
+		err = os.Mkdir(target, 0755) //nolint:gosec
+	default:
+		err = os.WriteFile(target, nil, 0644) //nolint:gosec
+	}
+
+	if err != nil && !os.IsExist(err) {
+		return err
+	}
+
+	if err := syscall.Mount(path, target, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+		return fmt.Errorf("error mounting %s: %w", path, err)
+	}
+
+	if writable {
+		return nil
+	}
+
+	// Remounting within a user namespace must keep the locked flags of the original mount.
+	var s syscall.Statfs_t
+
+	if err := syscall.Statfs(target, &s); err != nil {
+		return err
+	}
+
+	flags := uintptr(syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY) | uintptr(s.Flags)&(syscall.MS_NOATIME|syscall.MS_NODEV|syscall.MS_NODIRATIME|syscall.MS_NOEXEC|syscall.MS_NOSUID)
+	if s.Flags&stRelatime != 0 {
+		flags |= syscall.MS_RELATIME
+	}
+
+	if err := syscall.Mount("", target, "", flags, ""); err != nil {
+		return fmt.Errorf("error mounting %s read-only: %w", path, err)
+	}
+
+	return nil
+}
+
+// sandboxDev creates a minimal /dev within root.
+func sandboxDev(root string) error {
+	dev := filepath.Join(root, "dev")
+
+	if err := os.Mkdir(dev, 0755); err != nil { //nolint:gosec
+		return err
+	}
+
+	if err := syscall.Mount("tmpfs", dev, "tmpfs", syscall.MS_NOSUID|syscall.MS_NOEXEC, "mode=0755"); err != nil {
+		return fmt.Errorf("error mounting /dev: %w", err)
+	}
+
+	for _, d := range []string{
+		"full",
+		"null",
+		"random",
+		"tty",
+		"urandom",
+		"zero",
+	} {
+		if err := sandboxBind(root, "/dev/"+d, true); err != nil {
+			return err
+		}
+	}
+
+	for k, v := range map[string]string{
+		"fd":     "/proc/self/fd",
+		"stderr": "/proc/self/fd/2",
+		"stdin":  "/proc/self/fd/0",
+		"stdout": "/proc/self/fd/1",
+	} {
+		if err := os.Symlink(v, filepath.Join(dev, k)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sandboxInit runs within the namespaces to setup the root filesystem and limits, and then executes the command.
+func sandboxInit(s string) error {
+	var c sandboxConfig
+
+	if err := json.Unmarshal([]byte(s), &c); err != nil {
+		return err
+	}
+
+	os.Unsetenv(sandboxEnv)
+
+	if err := syscall.Mount("", "/", "", syscall.MS_PRIVATE|syscall.MS_REC, ""); err != nil {
+		return fmt.Errorf("error making mounts private: %w", err)
+	}
+
+	if err := syscall.Mount("tmpfs", c.Root, "tmpfs", syscall.MS_NODEV|syscall.MS_NOSUID, "mode=0755"); err != nil {
+		return fmt.Errorf("error mounting root: %w", err)
+	}
+
+	tmp := filepath.Join(c.Root, "tmp")
+
+	if err := os.Mkdir(tmp, 0755); err != nil { //nolint:gosec
+		return err
+	}
+
+	if err := syscall.Mount("tmpfs", tmp, "tmpfs", syscall.MS_NODEV|syscall.MS_NOSUID, "mode=1777"); err != nil {
+		return fmt.Errorf("error mounting /tmp: %w", err)
+	}
+
+	proc := filepath.Join(c.Root, "proc")
+
+	if err := os.Mkdir(proc, 0755); err != nil { //nolint:gosec
+		return err
+	}
+
+	if err := syscall.Mount("proc", proc, "proc", syscall.MS_NODEV|syscall.MS_NOEXEC|syscall.MS_NOSUID, ""); err != nil {
+		return fmt.Errorf("error mounting /proc: %w", err)
+	}
+
+	if err := sandboxDev(c.Root); err != nil {
+		return err
+	}
+
+	for _, p := range c.BindMounts {
+		if err := sandboxBind(c.Root, p, false); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range c.BindMountsWritable {
+		if err := sandboxBind(c.Root, p, true); err != nil {
+			return err
+		}
+	}
+
+	old := filepath.Join(c.Root, ".old")
+
+	if err := os.Mkdir(old, 0700); err != nil {
+		return err
+	}
+
+	if err := syscall.PivotRoot(c.Root, old); err != nil {
+		return fmt.Errorf("error changing root: %w", err)
+	}
+
+	if err := os.Chdir("/"); err != nil {
+		return err
+	}
+
+	if err := syscall.Unmount("/.old", syscall.MNT_DETACH); err != nil {
+		return fmt.Errorf("error unmounting host root: %w", err)
+	}
+
+	if err := os.Remove("/.old"); err != nil {
+		return err
+	}
+
+	if err := syscall.Mount("", "/", "", syscall.MS_BIND|syscall.MS_NODEV|syscall.MS_NOSUID|syscall.MS_RDONLY|syscall.MS_REMOUNT, ""); err != nil {
+		return fmt.Errorf("error mounting root read-only: %w", err)
+	}
+
+	if c.WorkDir != "" {
+		if err := os.Chdir(c.WorkDir); err != nil {
+			return fmt.Errorf("error changing to working directory: %w", err)
+		}
+	}
+
+	for _, l := range []struct {
+		resource int
+		value    uint64
+	}{
+		{
+			resource: syscall.RLIMIT_AS,
+			value:    c.LimitMemory,
+		},
+		{
+			resource: syscall.RLIMIT_CPU,
+			value:    uint64(math.Ceil(c.LimitCPU.Seconds())),
+		},
+		{
+			resource: syscall.RLIMIT_NOFILE,
+			value:    c.LimitOpenFiles,
+		},
+	} {
+		if l.value == 0 {
+			continue
+		}
+
+		if err := syscall.Setrlimit(l.resource, &syscall.Rlimit{
+			Cur: l.value,
+			Max: l.value,
+		}); err != nil {
+			return fmt.Errorf("error setting limit: %w", err)
+		}
+	}
+
+	if err := syscall.Exec(os.Args[1], os.Args[1:], os.Environ()); err != nil {
+		return fmt.Errorf("error running %s: %w", os.Args[1], err)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/logger"
+)
+
+func TestRunSandbox(t *testing.T) {
+	logger.UseTestLogger(t)
+
+	if err := sandboxAvailable(); err != nil {
+		t.Skip(err)
+	}
+
+	ctx := context.Background()
+	c := Config{}
+
+	d := t.TempDir()
+	os.WriteFile(filepath.Join(d, "hello"), []byte("world"), 0600)
+
+	tests := map[string]struct {
+		args       string
+		opts       RunOpts
+		wantErr    error
+		wantOutput string
+	}{
+		"root": {
+			args:       "ls /",
+			wantOutput: "bin\ndev\netc\nlib",
+		},
+		"tmp": {
+			args:       "ls -A /tmp && touch /tmp/a && ls /tmp",
+			wantOutput: "a",
+		},
+		"read-only": {
+			args:       "touch /usr/a",
+			wantErr:    ErrRun,
+			wantOutput: "Read-only file system",
+		},
+		"bind": {
+			args: "cat " + d + "/hello && touch " + d + "/a && ls " + d,
+			opts: RunOpts{
+				Sandbox: &RunSandbox{
+					BindMountsWritable: []string{
+						d,
+					},
+				},
+			},
+			wantOutput: "worlda\nhello",
+		},
+		"hidden": {
+			args:       "ls " + d,
+			wantErr:    ErrRun,
+			wantOutput: "No such file or directory",
+		},
+		"pid": {
+			args:       "echo $$",
+			wantOutput: "1",
+		},
+		"network": {
+			args:       "cat /proc/net/dev | tail -n +3 | cut -d: -f1",
+			wantOutput: "lo",
+		},
+		"limits": {
+			args: "ulimit -n && ulimit -t",
+			opts: RunOpts{
+				Sandbox: &RunSandbox{
+					LimitCPU:       1500 * time.Millisecond,
+					LimitOpenFiles: 64,
+				},
+			},
+			wantOutput: "64\n2",
+		},
+		"workdir": {
+			args: "pwd",
+			opts: RunOpts{
+				WorkDir: "/usr",
+			},
+			wantOutput: "/usr",
+		},
+		"bad_options": {
+			opts: RunOpts{
+				User: "1",
+			},
+			wantErr: ErrSandboxOptions,
+		},
+		"bad_bind": {
+			opts: RunOpts{
+				Sandbox: &RunSandbox{
+					BindMounts: []string{
+						"usr",
+					},
+				},
+			},
+			wantErr: ErrSandboxOptions,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := tc.opts
+			opts.Args = []string{
+				"-c",
+				tc.args,
+			}
+			opts.Command = "sh"
+			opts.NoErrorLog = true
+
+			if opts.Sandbox == nil {
+				opts.Sandbox = &RunSandbox{}
+			}
+
+			opts.Sandbox.BindMounts = append(opts.Sandbox.BindMounts, SandboxBindMountsSystem...)
+
+			out, err := c.Run(ctx, opts)
+			assert.HasErr(t, err, tc.wantErr)
+			assert.Contains(t, out.String(), tc.wantOutput)
+
+			if tc.args == "ls /" {
+				assert.Equal(t, strings.Contains(out.String(), "root"), false)
+			}
+		})
+	}
+
+	entries, _ := filepath.Glob(filepath.Join(os.TempDir(), "sandbox*"))
+	assert.Equal(t, len(entries), 0)
+}
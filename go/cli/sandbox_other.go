@@ -0,0 +1,19 @@
+//go:build !linux
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+
+	"github.com/candiddev/shared/go/errs"
+)
+
+func (*RunOpts) sandboxCmd(context.Context) (*exec.Cmd, errs.Err) {
+	return nil, errs.ErrReceiver.Wrap(ErrSandboxUnavailable, errors.New("namespaces are only supported on Linux"))
+}
+
+func sandboxError(err error) error {
+	return err
+}
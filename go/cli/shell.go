@@ -0,0 +1,329 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/candiddev/shared/go/config"
+	"github.com/candiddev/shared/go/errs"
+	"github.com/candiddev/shared/go/jsonnet"
+	"github.com/candiddev/shared/go/logger"
+	"golang.org/x/term"
+)
+
+var (
+	ErrShell      = errors.New("error running shell")
+	ErrShellQuote = errors.New("unterminated quote")
+)
+
+var shellBuiltins = []string{ //nolint:gochecknoglobals
+	"exit",
+	"help",
+	"history",
+	"reload",
+}
+
+const shellHelp = `Enter a command, like show-config or jq, or a Jsonnet expression to evaluate.  The config is available in Jsonnet as config, and jq reads the config when no files are provided.
+
+Shell Commands:
+  exit
+    	Exit the shell
+  help
+    	Print this help
+  history
+    	Print the command history
+  reload
+    	Parse the config again
+
+`
+
+// shell is a running shell session.
+type shell[T AppConfig[any]] struct {
+	app     App[T]
+	config  map[string]any
+	history []string
+	term    *term.Terminal
+}
+
+func (a App[T]) shellCommand() Command[T] {
+	return Command[T]{
+		Run: func(ctx context.Context, _ []string, _ T) errs.Err {
+			s := &shell[T]{
+				app: a,
+			}
+
+			return s.run(ctx)
+		},
+		Usage: "Start an interactive shell for running commands and evaluating Jsonnet with the current config",
+	}
+}
+
+func (s *shell[T]) run(ctx context.Context) errs.Err {
+	if err := s.mask(ctx); err != nil {
+		return err
+	}
+
+	if promptTTY() {
+		s.term = term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{os.Stdin, logger.Stderr}, strings.ToLower(s.app.Name)+"> ")
+		s.term.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+			if key != '\t' {
+				return "", 0, false
+			}
+
+			return s.complete(ctx, line, pos)
+		}
+	}
+
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return logger.Error(ctx, errs.ErrReceiver.Wrap(ErrShell, err))
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		s.history = append(s.history, line)
+
+		if line == "exit" || line == "quit" {
+			return nil
+		}
+
+		s.exec(ctx, line) //nolint:errcheck
+	}
+}
+
+// complete completes the word before pos in line using App completions.
+func (s *shell[T]) complete(ctx context.Context, line string, pos int) (string, int, bool) {
+	prefix := line[:pos]
+	words := strings.Fields(prefix)
+
+	if prefix == "" || unicode.IsSpace(rune(prefix[len(prefix)-1])) {
+		words = append(words, "")
+	}
+
+	c := s.app.complete(ctx, words)
+
+	current := words[len(words)-1]
+
+	if len(words) == 1 {
+		c = append(c, filterPrefix(shellBuiltins, current)...)
+	}
+
+	if len(c) == 0 {
+		return "", 0, false
+	}
+
+	common := c[0]
+
+	for _, v := range c[1:] {
+		for !strings.HasPrefix(v, common) {
+			common = common[:len(common)-1]
+		}
+	}
+
+	if len(c) == 1 {
+		common += " "
+	}
+
+	if common == current {
+		return "", 0, false
+	}
+
+	prefix = prefix[:len(prefix)-len(current)] + common
+
+	return prefix + line[pos:], len(prefix), true
+}
+
+// exec runs a line as a shell command, App command, plugin, or Jsonnet expression.
+func (s *shell[T]) exec(ctx context.Context, line string) errs.Err {
+	args, err := shellSplit(line)
+	if err != nil {
+		return logger.Error(ctx, errs.ErrSenderBadRequest.Wrap(ErrShell, err))
+	}
+
+	switch args[0] {
+	case "help":
+		logger.Raw(shellHelp)
+		s.app.usage(nil, Command[T]{
+			Commands: s.app.Commands,
+			Usage:    s.app.Description,
+		})
+
+		return nil
+	case "history":
+		for i := range s.history {
+			logger.Raw(fmt.Sprintf("%d  %s\n", i+1, s.history[i]))
+		}
+
+		return nil
+	case "reload":
+		c, err := reparseConfig(ctx, s.app.Config, s.app.NewConfig)
+		if err != nil {
+			return err
+		}
+
+		a := s.app
+		a.Config = c
+
+		m, err := config.Mask(ctx, a.Config, a.HideConfigFields)
+		if err != nil {
+			return logger.Error(ctx, err)
+		}
+
+		s.app = a
+		s.config = m
+
+		return nil
+	case "shell":
+		return logger.Error(ctx, errs.ErrSenderBadRequest.Wrap(ErrShell, errors.New("already in a shell")))
+	}
+
+	if k, _, ok := findCommand(s.app.Commands, args[0]); ok {
+		if k == "jq" {
+			b, err := json.Marshal(s.config)
+			if err != nil {
+				return logger.Error(ctx, errs.ErrReceiver.Wrap(ErrShell, err))
+			}
+
+			stdin := os.Stdin
+			p := promptStdin
+
+			SetStdin(string(b))
+
+			defer func() {
+				os.Stdin = stdin
+				promptStdin = p
+			}()
+		}
+
+		return s.app.runCommand(ctx, nil, Command[T]{
+			Commands: s.app.Commands,
+			Usage:    s.app.Description,
+		}, args)
+	}
+
+	if p, ok := s.app.plugins()[args[0]]; ok {
+		return s.app.runPlugin(ctx, p, args[1:])
+	}
+
+	r := jsonnet.NewRender(ctx, s.config)
+	r.Import(r.GetString("local config = std.native('getConfig')();\n" + line))
+
+	var out any
+
+	if err := r.Render(ctx, &out); err != nil {
+		return err
+	}
+
+	return Print(out)
+}
+
+// mask sets the config available to Jsonnet and jq, hiding HideConfigFields.
+func (s *shell[T]) mask(ctx context.Context) errs.Err {
+	m, err := config.Mask(ctx, s.app.Config, s.app.HideConfigFields)
+	if err != nil {
+		return logger.Error(ctx, err)
+	}
+
+	s.config = m
+
+	return nil
+}
+
+// readLine reads a line using the line editor if stdin is a terminal, otherwise it reads a line from stdin.
+func (s *shell[T]) readLine() (string, error) {
+	if s.term == nil {
+		l, err := promptReader().ReadString('\n')
+		if err != nil && (!errors.Is(err, io.EOF) || l == "") {
+			return "", err
+		}
+
+		return l, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+
+	if w, h, err := term.GetSize(fd); err == nil {
+		s.term.SetSize(w, h) //nolint:errcheck
+	}
+
+	// The terminal is only raw while reading, so commands can write output normally.
+	st, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", err
+	}
+
+	defer term.Restore(fd, st) //nolint:errcheck
+
+	return s.term.ReadLine()
+}
+
+// shellSplit splits a line into arguments like a shell, supporting quotes and backslash escapes.
+func shellSplit(line string) ([]string, error) {
+	args := []string{}
+
+	var b strings.Builder
+
+	var quote rune
+
+	arg := false
+	escape := false
+
+	for _, r := range line {
+		switch {
+		case escape:
+			b.WriteRune(r)
+
+			escape = false
+		case r == '\\' && quote != '\'':
+			arg = true
+			escape = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				b.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			arg = true
+			quote = r
+		case unicode.IsSpace(r):
+			if arg {
+				args = append(args, b.String())
+				b.Reset()
+
+				arg = false
+			}
+		default:
+			arg = true
+
+			b.WriteRune(r)
+		}
+	}
+
+	if quote != 0 || escape {
+		return nil, ErrShellQuote
+	}
+
+	if arg {
+		args = append(args, b.String())
+	}
+
+	return args, nil
+}
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/logger"
+)
+
+func TestShell(t *testing.T) {
+	BuildDate = "2024-01-01"
+	BuildVersion = "1.0"
+
+	a := App[*C]{
+		Commands: map[string]Command[*C]{},
+		Config:   &C{},
+		HideConfigFields: []string{
+			"Hide",
+		},
+		Name: "App",
+	}
+
+	os.Args = []string{"app", "-c", "./testdata/config.json", "shell"}
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+	SetStdin(`version
+config.Show.Message + "!"
+jq -r '.Show.Message'
+{ a: std.length(config.Show.Message) }
+jq ".Other"
+shell
+history
+exit
+version`)
+
+	logger.SetStd()
+
+	assert.HasErr(t, a.Run(), nil)
+	assert.Equal(t, logger.ReadStd(), `Build Version: 1.0
+Build Date: 2024-01-01
+"Hello World!"
+Hello World
+{
+  "a": 11
+}
+{}
+1  version
+2  config.Show.Message + "!"
+3  jq -r '.Show.Message'
+4  { a: std.length(config.Show.Message) }
+5  jq ".Other"
+6  shell
+7  history
+`)
+}
+
+func TestShellReload(t *testing.T) {
+	ctx := logger.UseTestLogger(t)
+
+	p := filepath.Join(t.TempDir(), "config.json")
+	assert.HasErr(t, os.WriteFile(p, []byte(`{"Other": {"Message": "a"}, "Show": {"Message": "b"}}`), 0600), nil)
+
+	c := &C{}
+
+	a := App[*C]{
+		Commands: map[string]Command[*C]{},
+		Config:   c,
+		NewConfig: func() *C {
+			return &C{}
+		},
+	}
+	a.setup(flag.NewFlagSet("app", flag.ContinueOnError), &ConfigArgs{})
+
+	c.CLI.ConfigPath = p
+	c.CLI.OutputFormat = OutputFormatJSONCompact
+	assert.HasErr(t, c.Parse(ctx, nil), nil)
+
+	s := &shell[*C]{
+		app: a,
+	}
+	assert.HasErr(t, s.mask(ctx), nil)
+
+	// Reloading requires NewConfig.
+	s.app.NewConfig = nil
+	assert.HasErr(t, s.exec(ctx, "reload"), ErrReloadNewConfig)
+	assert.Equal(t, s.app.Config == c, true)
+
+	s.app.NewConfig = a.NewConfig
+
+	// A failed reload keeps the current config.
+	assert.HasErr(t, os.WriteFile(p, []byte(`{"Show": {"Message": 1}}`), 0600), nil)
+	assert.Equal(t, s.exec(ctx, "reload") != nil, true)
+	assert.Equal(t, s.app.Config == c, true)
+	assert.Equal(t, c.Show.Message, "b")
+	assert.Equal[any](t, s.config["Show"], map[string]any{
+		"Message": "b",
+	})
+
+	// Removed keys aren't kept from the current config.
+	assert.HasErr(t, os.WriteFile(p, []byte(`{"Show": {"Message": "c"}}`), 0600), nil)
+	assert.HasErr(t, s.exec(ctx, "reload"), nil)
+	assert.Equal(t, c.Other.Message, "a")
+	assert.Equal(t, s.app.Config.Other.Message, "")
+	assert.Equal(t, s.app.Config.Show.Message, "c")
+	assert.Equal(t, s.app.Config.CLI.ConfigPath, p)
+	assert.Equal[any](t, s.config["Other"], map[string]any{})
+
+	logger.SetStd()
+	assert.HasErr(t, s.exec(ctx, "show-config"), nil)
+	assert.Contains(t, logger.ReadStd(), `"Other":{},"Show":{"Message":"c"}`)
+}
+
+func TestShellComplete(t *testing.T) {
+	ctx := logger.UseTestLogger(t)
+
+	a := App[*C]{
+		Commands: map[string]Command[*C]{
+			"hello": {
+				Usage: "Hello",
+			},
+			"help-me": {
+				Usage: "Help me",
+			},
+		},
+		Config: &C{},
+	}
+	a.setup(flag.NewFlagSet("app", flag.ContinueOnError), &ConfigArgs{})
+
+	s := &shell[*C]{
+		app: a,
+	}
+
+	tests := map[string]struct {
+		line     string
+		pos      int
+		wantLine string
+		wantOK   bool
+		wantPos  int
+	}{
+		"common": {
+			line:     "he",
+			pos:      2,
+			wantLine: "hel",
+			wantOK:   true,
+			wantPos:  3,
+		},
+		"single": {
+			line:     "hi jq",
+			pos:      2,
+			wantLine: "history  jq",
+			wantOK:   true,
+			wantPos:  8,
+		},
+		"ambiguous": {
+			line: "hel",
+			pos:  3,
+		},
+		"none": {
+			line: "x",
+			pos:  1,
+		},
+		"global": {
+			line: "-",
+			pos:  1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			l, p, ok := s.complete(ctx, tc.line, tc.pos)
+			assert.Equal(t, ok, tc.wantOK)
+
+			if tc.wantOK {
+				assert.Equal(t, l, tc.wantLine)
+				assert.Equal(t, p, tc.wantPos)
+			}
+		})
+	}
+}
+
+func TestShellSplit(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    []string
+		wantErr error
+	}{
+		"simple": {
+			input: "jq  -r .a",
+			want:  []string{"jq", "-r", ".a"},
+		},
+		"quotes": {
+			input: `jq '.a | "b"' "c d"e`,
+			want:  []string{"jq", `.a | "b"`, "c de"},
+		},
+		"escapes": {
+			input: `a\ b "c\"d" ''`,
+			want:  []string{"a b", `c"d`, ""},
+		},
+		"unterminated": {
+			input:   `a "b`,
+			wantErr: ErrShellQuote,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := shellSplit(tc.input)
+			assert.HasErr(t, err, tc.wantErr)
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
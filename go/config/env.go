@@ -19,7 +19,7 @@ func getEnv(ctx context.Context, config any, prefix string) errs.Err {
 		r := jsonnet.NewRender(ctx, config)
 		r.Import(r.GetString(c))
 
-		if err := r.Render(ctx, config); err != nil {
+		if err := render(ctx, r, config); err != nil {
 			return logger.Error(ctx, err)
 		}
 	}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -30,7 +31,7 @@ func GetFile(ctx context.Context, config any, path string) errs.Err {
 
 		r.Import(i)
 
-		if err := r.Render(ctx, config); err != nil {
+		if err := render(ctx, r, config); err != nil {
 			return logger.Error(ctx, err)
 		}
 	}
@@ -38,6 +39,25 @@ func GetFile(ctx context.Context, config any, path string) errs.Err {
 	return logger.Error(ctx, nil)
 }
 
+// render renders r onto config.  The rendered JSON is validated against the Schema of config first, so errors include the path of the invalid value.
+func render(ctx context.Context, r *jsonnet.Render, config any) errs.Err {
+	var raw json.RawMessage
+
+	if err := r.Render(ctx, &raw); err != nil {
+		return logger.Error(ctx, err)
+	}
+
+	if err := NewSchema(config, nil).Validate(raw); err != nil {
+		return logger.Error(ctx, err)
+	}
+
+	if err := json.Unmarshal(raw, config); err != nil {
+		return logger.Error(ctx, errs.ErrReceiver.Wrap(ErrRender, err))
+	}
+
+	return nil
+}
+
 // FindFilenameAscending looks for a filename in every parent directory.
 func FindFilenameAscending(ctx context.Context, filename string) (path string) {
 	wd, e := os.Getwd()
@@ -0,0 +1,345 @@
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/candiddev/shared/go/errs"
+)
+
+var ErrSchemaValidate = errors.New("config does not match schema")
+
+// SchemaDraft is the JSON Schema version of Schema.
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+//nolint:gochecknoglobals
+var (
+	schemaJSONUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	schemaTextUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	schemaTime            = reflect.TypeOf(time.Time{})
+)
+
+// Schema is a JSON Schema describing a config.
+type Schema struct {
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Schema               string             `json:"$schema,omitempty"`
+	Type                 SchemaType         `json:"type,omitempty"`
+}
+
+// SchemaType is a list of JSON Schema types.  An empty SchemaType allows any type.
+type SchemaType []string
+
+func (s SchemaType) MarshalJSON() ([]byte, error) {
+	if len(s) == 1 {
+		return json.Marshal(s[0])
+	}
+
+	return json.Marshal([]string(s))
+}
+
+func (s *SchemaType) UnmarshalJSON(data []byte) error {
+	var v string
+
+	if err := json.Unmarshal(data, &v); err == nil {
+		*s = SchemaType{v}
+
+		return nil
+	}
+
+	return json.Unmarshal(data, (*[]string)(s))
+}
+
+// NewSchema generates a Schema from a config struct.  Properties use the json tag names, descriptions use the description tag, and defaults use the non-zero values of c.  The defaults of hideFields, like HideConfigFields, are removed.
+func NewSchema(c any, hideFields []string) *Schema {
+	s := newSchema(reflect.ValueOf(c))
+	s.Schema = SchemaDraft
+
+	for _, f := range hideFields {
+		p := s
+
+		for _, k := range strings.Split(f, ".") {
+			if p = p.Properties[k]; p == nil {
+				break
+			}
+		}
+
+		if p != nil {
+			p.hideDefaults()
+		}
+	}
+
+	return s
+}
+
+func newSchema(v reflect.Value) *Schema {
+	s := &Schema{}
+	t := v.Type()
+
+	for t.Kind() == reflect.Pointer {
+		s.Type = append(s.Type, "null")
+
+		if v.IsValid() && !v.IsNil() {
+			v = v.Elem()
+		} else {
+			v = reflect.Value{}
+		}
+
+		t = t.Elem()
+	}
+
+	switch {
+	case t == schemaTime:
+		s.Format = "date-time"
+		s.Type = append(SchemaType{"string"}, s.Type...)
+		s.setDefault(v)
+
+		return s
+	case reflect.PointerTo(t).Implements(schemaJSONUnmarshaler):
+		// Custom JSON types can be any JSON value.
+		s.Type = nil
+		s.setDefault(v)
+
+		return s
+	case reflect.PointerTo(t).Implements(schemaTextUnmarshaler):
+		s.Type = append(SchemaType{"string"}, s.Type...)
+		s.setDefault(v)
+
+		return s
+	}
+
+	var kind string
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Bool:
+		kind = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		kind = "integer"
+	case reflect.Float32, reflect.Float64:
+		kind = "number"
+	case reflect.String:
+		kind = "string"
+	case reflect.Array, reflect.Slice:
+		if t.Kind() == reflect.Slice {
+			s.Type = append(s.Type, "null")
+		}
+
+		if t.Elem().Kind() == reflect.Uint8 {
+			kind = "string"
+
+			break
+		}
+
+		kind = "array"
+		s.Items = newSchema(reflect.Zero(t.Elem()))
+	case reflect.Map:
+		kind = "object"
+		s.Type = append(s.Type, "null")
+		s.AdditionalProperties = newSchema(reflect.Zero(t.Elem()))
+	case reflect.Struct:
+		kind = "object"
+		s.Properties = map[string]*Schema{}
+
+		schemaFields(s, t, v)
+
+		return s.prepend(kind)
+	default:
+		// Interfaces can be any JSON value.
+		s.Type = nil
+		s.setDefault(v)
+
+		return s
+	}
+
+	s.setDefault(v)
+
+	return s.prepend(kind)
+}
+
+// schemaFields adds the properties of struct t with value v to s.
+func schemaFields(s *Schema, t reflect.Type, v reflect.Value) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		n, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if n == "-" {
+			continue
+		}
+
+		fv := reflect.Zero(f.Type)
+		if v.IsValid() {
+			fv = v.Field(i)
+		}
+
+		if n == "" {
+			if f.Anonymous {
+				ft := f.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+					fv = fv.Elem()
+				}
+
+				if ft.Kind() == reflect.Struct {
+					schemaFields(s, ft, fv)
+
+					continue
+				}
+			}
+
+			if !f.IsExported() {
+				continue
+			}
+
+			n = f.Name
+		}
+
+		p := newSchema(fv)
+		p.Description = f.Tag.Get("description")
+		s.Properties[n] = p
+	}
+}
+
+func (s *Schema) hideDefaults() {
+	s.Default = nil
+
+	for _, p := range s.Properties {
+		p.hideDefaults()
+	}
+}
+
+func (s *Schema) prepend(kind string) *Schema {
+	s.Type = append(SchemaType{kind}, s.Type...)
+
+	return s
+}
+
+func (s *Schema) setDefault(v reflect.Value) {
+	if !v.IsValid() || v.IsZero() || !v.CanInterface() {
+		return
+	}
+
+	if k := v.Kind(); (k == reflect.Map || k == reflect.Slice) && v.Len() == 0 {
+		return
+	}
+
+	s.Default = v.Interface()
+}
+
+// Validate checks that the JSON data matches the Schema, and returns an error listing each invalid path.  Like json.Unmarshal, null is valid for any type.
+func (s *Schema) Validate(data []byte) errs.Err {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var v any
+
+	if err := d.Decode(&v); err != nil {
+		return errs.ErrReceiver.Wrap(ErrSchemaValidate, err)
+	}
+
+	e := s.validate("", v)
+	if len(e) == 0 {
+		return nil
+	}
+
+	return errs.ErrReceiver.Wrap(ErrSchemaValidate, errors.New(strings.Join(e, "\n")))
+}
+
+func (s *Schema) validate(path string, v any) []string {
+	// json.Unmarshal ignores null for every type, so it's always valid.
+	if len(s.Type) == 0 || v == nil {
+		return nil
+	}
+
+	t := schemaValueType(v)
+
+	match := false
+
+	for _, k := range s.Type {
+		if k == t || (k == "number" && t == "integer") {
+			match = true
+
+			break
+		}
+	}
+
+	if !match {
+		if path == "" {
+			path = "."
+		}
+
+		return []string{
+			fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(s.Type, " or "), t),
+		}
+	}
+
+	e := []string{}
+
+	switch t {
+	case "array":
+		if s.Items != nil {
+			for i, item := range v.([]any) { //nolint:forcetypeassert
+				e = append(e, s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item)...)
+			}
+		}
+	case "object":
+		m := v.(map[string]any) //nolint:forcetypeassert
+
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			p := s.Properties[k]
+			if p == nil {
+				p = s.AdditionalProperties
+			}
+
+			if p != nil {
+				e = append(e, p.validate(strings.TrimPrefix(path+"."+k, "."), m[k])...)
+			}
+		}
+	}
+
+	return e
+}
+
+// schemaValueType returns the JSON Schema type of a value decoded using json.Decoder.UseNumber.
+func schemaValueType(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := t.Int64(); err == nil {
+			return "integer"
+		}
+
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+
+	return ""
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/types"
+)
+
+type schemaConfig struct {
+	SchemaEmbedded
+
+	Any      any                      `json:"any"`
+	Bytes    []byte                   `json:"bytes"`
+	Date     types.CivilDate          `json:"date"`
+	Duration time.Duration            `json:"duration"`
+	Float    float64                  `json:"float"`
+	Ignored  string                   `json:"-"`
+	Int      int                      `json:"int" description:"An integer"`
+	List     []schemaNested           `json:"list"`
+	Map      map[string]*schemaNested `json:"map"`
+	NoTag    bool
+	Pointer  *schemaNested `json:"pointer,omitempty"`
+	Secret   schemaNested  `json:"secret"`
+	Time     time.Time     `json:"time"`
+	private  string        //nolint:unused
+}
+
+type SchemaEmbedded struct {
+	Embedded string `json:"embedded"`
+}
+
+type schemaNested struct {
+	Name string `json:"name" description:"Name of the thing"`
+}
+
+func TestNewSchema(t *testing.T) {
+	s := NewSchema(&schemaConfig{
+		SchemaEmbedded: SchemaEmbedded{
+			Embedded: "e",
+		},
+		Int: 1,
+		Map: map[string]*schemaNested{
+			"a": {
+				Name: "b",
+			},
+		},
+		Secret: schemaNested{
+			Name: "secret",
+		},
+	}, []string{
+		"secret",
+	})
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	assert.HasErr(t, err, nil)
+
+	assert.Equal(t, string(b), `{
+  "properties": {
+    "NoTag": {
+      "type": "boolean"
+    },
+    "any": {},
+    "bytes": {
+      "type": [
+        "string",
+        "null"
+      ]
+    },
+    "date": {},
+    "duration": {
+      "type": "integer"
+    },
+    "embedded": {
+      "default": "e",
+      "type": "string"
+    },
+    "float": {
+      "type": "number"
+    },
+    "int": {
+      "default": 1,
+      "description": "An integer",
+      "type": "integer"
+    },
+    "list": {
+      "items": {
+        "properties": {
+          "name": {
+            "description": "Name of the thing",
+            "type": "string"
+          }
+        },
+        "type": "object"
+      },
+      "type": [
+        "array",
+        "null"
+      ]
+    },
+    "map": {
+      "additionalProperties": {
+        "properties": {
+          "name": {
+            "description": "Name of the thing",
+            "type": "string"
+          }
+        },
+        "type": [
+          "object",
+          "null"
+        ]
+      },
+      "default": {
+        "a": {
+          "name": "b"
+        }
+      },
+      "type": [
+        "object",
+        "null"
+      ]
+    },
+    "pointer": {
+      "properties": {
+        "name": {
+          "description": "Name of the thing",
+          "type": "string"
+        }
+      },
+      "type": [
+        "object",
+        "null"
+      ]
+    },
+    "secret": {
+      "properties": {
+        "name": {
+          "description": "Name of the thing",
+          "type": "string"
+        }
+      },
+      "type": "object"
+    },
+    "time": {
+      "format": "date-time",
+      "type": "string"
+    }
+  },
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": [
+    "object",
+    "null"
+  ]
+}`)
+}
+
+func TestSchemaValidate(t *testing.T) {
+	s := NewSchema(&schemaConfig{}, nil)
+
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"valid": {
+			input: `{"any": [1], "date": "2024-01-01", "embedded": "a", "float": 1, "int": 1, "list": [{"name": "a"}], "map": {"a": null, "b": {"name": "b"}}, "pointer": null, "time": "2024-01-01T00:00:00Z", "unknown": 1}`,
+		},
+		"invalid": {
+			input: `{"embedded": 1, "float": "1", "int": 1.5, "list": [{"name": "a"}, {"name": true}], "map": {"a": {"name": 1}}, "secret": null}`,
+			want: `config does not match schema: embedded: expected string, got integer
+float: expected number, got string
+int: expected integer, got number
+list[1].name: expected string, got boolean
+map.a.name: expected string, got integer`,
+		},
+		"null": {
+			input: `{"embedded": null, "int": null, "list": [null, {"name": null}], "map": {"a": {"name": null}}, "secret": null, "time": null}`,
+		},
+		"root": {
+			input: `[]`,
+			want:  "config does not match schema: .: expected object or null, got array",
+		},
+		"bad_json": {
+			input: `{`,
+			want:  "config does not match schema: unexpected EOF",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := s.Validate([]byte(tc.input))
+			if tc.want == "" {
+				assert.HasErr(t, err, nil)
+
+				return
+			}
+
+			assert.HasErr(t, err, ErrSchemaValidate)
+			assert.Equal(t, err.Error(), tc.want)
+		})
+	}
+}
+
+func TestGetFileSchema(t *testing.T) {
+	ctx := context.Background()
+
+	p := filepath.Join(t.TempDir(), "config.jsonnet")
+	assert.HasErr(t, os.WriteFile(p, []byte(`{app: {port: "80"}, commands: [{exec: ["a"]}]}`), 0600), nil)
+
+	c := config{}
+
+	err := GetFile(ctx, &c, p)
+	assert.HasErr(t, err, ErrSchemaValidate)
+	assert.Contains(t, err.Error(), "app.port: expected integer, got string\ncommands[0].exec: expected string, got array")
+
+	assert.HasErr(t, os.WriteFile(p, []byte(`{app: {port: 80, arg1: null, nested: {a: null}}, commands: null}`), 0600), nil)
+
+	c = config{}
+
+	assert.HasErr(t, GetFile(ctx, &c, p), nil)
+	assert.Equal(t, c.App.Port, 80)
+}
@@ -20,6 +20,7 @@ const (
 	KDFECDHP256            KDF       = "ecdhp256"
 	ecp256publicRawLen               = 65
 	ecp256privateRawLen              = 32
+	ecp256signatureLen               = 32
 )
 
 // ECP256PrivateKey is a private key type.
@@ -173,9 +174,9 @@ func (e ECP256PrivateKey) Sign(message []byte, hash crypto.Hash) (signature []by
 		return nil, fmt.Errorf("%w: %w", ErrSign, err)
 	}
 
-	out := make([]byte, 2*32)
-	r.FillBytes(out[0:32])
-	s.FillBytes(out[32:])
+	out := make([]byte, 2*ecp256signatureLen)
+	r.FillBytes(out[0:ecp256signatureLen])
+	s.FillBytes(out[ecp256signatureLen:])
 
 	return out, nil
 }
@@ -292,8 +293,12 @@ func (e ECP256PublicKey) Verify(message []byte, hash crypto.Hash, signature []by
 		return fmt.Errorf("%w: %w", ErrCreatingHash, err)
 	}
 
-	r := big.NewInt(0).SetBytes(signature[:32])
-	s := big.NewInt(0).SetBytes(signature[32:])
+	if len(signature) != 2*ecp256signatureLen {
+		return ErrVerify
+	}
+
+	r := big.NewInt(0).SetBytes(signature[:ecp256signatureLen])
+	s := big.NewInt(0).SetBytes(signature[ecp256signatureLen:])
 
 	if !ecdsa.Verify(k, n.Sum(nil), r, s) {
 		return ErrVerify
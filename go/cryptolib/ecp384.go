@@ -0,0 +1,308 @@
+package cryptolib
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+const (
+	AlgorithmECP384        Algorithm = "ecp384"
+	AlgorithmECP384Private Algorithm = "ecp384private"
+	AlgorithmECP384Public  Algorithm = "ecp384public"
+	KDFECDHP384            KDF       = "ecdhp384"
+	ecp384publicRawLen               = 97
+	ecp384privateRawLen              = 48
+	ecp384signatureLen               = 48
+)
+
+// ECP384PrivateKey is a private key type.
+type ECP384PrivateKey string
+
+// ECP384PublicKey is a public key type.
+type ECP384PublicKey string
+
+var ecp384PrivateKeys = struct { //nolint: gochecknoglobals
+	keys  map[ECP384PrivateKey]*ecdsa.PrivateKey
+	mutex sync.Mutex
+}{
+	keys: map[ECP384PrivateKey]*ecdsa.PrivateKey{},
+}
+
+var ecp384PublicKeys = struct { //nolint: gochecknoglobals
+	keys  map[ECP384PublicKey]*ecdsa.PublicKey
+	mutex sync.Mutex
+}{
+	keys: map[ECP384PublicKey]*ecdsa.PublicKey{},
+}
+
+// NewECP384 generates a new ECP384 private/public keypair.
+func NewECP384() (privateKey ECP384PrivateKey, publicKey ECP384PublicKey, err error) {
+	private, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %w", ErrGeneratingPrivateKey, err)
+	}
+
+	x509Private, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %w", ErrMarshalingPrivateKey, err)
+	}
+
+	x509Public, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %w", ErrMarshalingPublicKey, err)
+	}
+
+	return ECP384PrivateKey(base64.StdEncoding.EncodeToString(x509Private)),
+		ECP384PublicKey(base64.StdEncoding.EncodeToString(x509Public)),
+		nil
+}
+
+func (ECP384PrivateKey) Algorithm() Algorithm {
+	return AlgorithmECP384Private
+}
+
+func (e ECP384PrivateKey) DecryptAsymmetric(input EncryptedValue) ([]byte, error) {
+	return KDFGet(e, input)
+}
+
+func (e ECP384PrivateKey) KDFGet(input, _ string) (key []byte, err error) {
+	pub := ECP384PublicKey(input)
+
+	pubE, err := pub.PublicKeyECDH()
+	if err != nil {
+		return nil, err
+	}
+
+	prvE, err := e.PrivateKeyECDH()
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := prvE.ECDH(pubE)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrGeneratingKDF, err)
+	}
+
+	return k, nil
+}
+
+func (ECP384PrivateKey) KDF() KDF {
+	return KDFECDHP384
+}
+
+func (e ECP384PrivateKey) PrivateKey() (*ecdsa.PrivateKey, error) {
+	ecp384PrivateKeys.mutex.Lock()
+
+	defer ecp384PrivateKeys.mutex.Unlock()
+
+	var ok bool
+
+	var p *ecdsa.PrivateKey
+
+	if p, ok = ecp384PrivateKeys.keys[e]; !ok {
+		bytesPrivate, err := base64.StdEncoding.DecodeString(string(e))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDecodingPrivateKey, err)
+		}
+
+		if len(bytesPrivate) == ecp384privateRawLen {
+			ep, err := ecdh.P384().NewPrivateKey(bytesPrivate)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrParsingPrivateKey, err)
+			}
+
+			bytesPrivate, err = x509.MarshalPKCS8PrivateKey(ep)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrParsingPrivateKey, err)
+			}
+		}
+
+		private, err := x509.ParsePKCS8PrivateKey(bytesPrivate)
+		if err != nil {
+			private, err = x509.ParseECPrivateKey(bytesPrivate)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrParsingPrivateKey, err)
+			}
+		}
+
+		if p, ok = private.(*ecdsa.PrivateKey); !ok {
+			return nil, ErrNoPrivateKey
+		}
+
+		ecp384PrivateKeys.keys[e] = p
+	}
+
+	return p, nil
+}
+
+func (e ECP384PrivateKey) PrivateKeyECDH() (*ecdh.PrivateKey, error) {
+	p, err := e.PrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParsingPrivateKey, err)
+	}
+
+	pe, err := p.ECDH()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParsingPrivateKey, err)
+	}
+
+	return pe, nil
+}
+
+func (e ECP384PrivateKey) Sign(message []byte, hash crypto.Hash) (signature []byte, err error) {
+	k, err := e.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	n := hash.New()
+
+	if _, err := n.Write(message); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCreatingHash, err)
+	}
+
+	r, s, err := ecdsa.Sign(rand.Reader, k, n.Sum(nil))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSign, err)
+	}
+
+	out := make([]byte, 2*ecp384signatureLen)
+	r.FillBytes(out[0:ecp384signatureLen])
+	s.FillBytes(out[ecp384signatureLen:])
+
+	return out, nil
+}
+
+func (ECP384PrivateKey) Provides(Encryption) bool {
+	return false
+}
+
+func (ECP384PublicKey) Algorithm() Algorithm {
+	return AlgorithmECP384Public
+}
+
+func (e ECP384PublicKey) EncryptAsymmetric(input []byte, keyID string, encryption Encryption) (EncryptedValue, error) {
+	return KDFSet(e, keyID, input, encryption)
+}
+
+func (ECP384PublicKey) KDF() KDF {
+	return KDFECDHP384
+}
+
+func (e ECP384PublicKey) KDFSet() (input string, key []byte, err error) {
+	pubE, err := e.PublicKeyECDH()
+	if err != nil {
+		return "", nil, err
+	}
+
+	prv, pub, err := NewECP384()
+	if err != nil {
+		return "", nil, err
+	}
+
+	prvE, err := prv.PrivateKeyECDH()
+	if err != nil {
+		return "", nil, err
+	}
+
+	key, err = prvE.ECDH(pubE)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrGeneratingKDF, err)
+	}
+
+	return string(pub), key, nil
+}
+
+func (ECP384PublicKey) Provides(Encryption) bool {
+	return false
+}
+
+func (e ECP384PublicKey) PublicKey() (*ecdsa.PublicKey, error) {
+	ecp384PublicKeys.mutex.Lock()
+
+	defer ecp384PublicKeys.mutex.Unlock()
+
+	var ok bool
+
+	var p *ecdsa.PublicKey
+
+	if p, ok = ecp384PublicKeys.keys[e]; !ok {
+		bytesPublic, err := base64.StdEncoding.DecodeString(string(e))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDecodingPublicKey, err)
+		}
+
+		if len(bytesPublic) == ecp384publicRawLen {
+			ep, err := ecdh.P384().NewPublicKey(bytesPublic)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrParsingPublicKey, err)
+			}
+
+			bytesPublic, err = x509.MarshalPKIXPublicKey(ep)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrParsingPublicKey, err)
+			}
+		}
+
+		public, err := x509.ParsePKIXPublicKey(bytesPublic)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrParsingPublicKey, err)
+		}
+
+		if p, ok = public.(*ecdsa.PublicKey); !ok {
+			return nil, ErrNoPublicKey
+		}
+
+		ecp384PublicKeys.keys[e] = p
+	}
+
+	return p, nil
+}
+
+func (e ECP384PublicKey) PublicKeyECDH() (*ecdh.PublicKey, error) {
+	p, err := e.PublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParsingPublicKey, err)
+	}
+
+	pe, err := p.ECDH()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParsingPublicKey, err)
+	}
+
+	return pe, nil
+}
+
+func (e ECP384PublicKey) Verify(message []byte, hash crypto.Hash, signature []byte) error {
+	k, err := e.PublicKey()
+	if err != nil {
+		return err
+	}
+
+	n := hash.New()
+
+	if _, err := n.Write(message); err != nil {
+		return fmt.Errorf("%w: %w", ErrCreatingHash, err)
+	}
+
+	if len(signature) != 2*ecp384signatureLen {
+		return ErrVerify
+	}
+
+	r := big.NewInt(0).SetBytes(signature[:ecp384signatureLen])
+	s := big.NewInt(0).SetBytes(signature[ecp384signatureLen:])
+
+	if !ecdsa.Verify(k, n.Sum(nil), r, s) {
+		return ErrVerify
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package cryptolib
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/candiddev/shared/go/assert"
+)
+
+func TestECP384(t *testing.T) {
+	prvStr, pubStr, err := NewECP384()
+
+	assert.Equal(t, err, nil)
+
+	badPrv1 := ECP384PrivateKey("asd")
+	_, err = badPrv1.PrivateKey()
+	assert.HasErr(t, err, ErrDecodingPrivateKey)
+
+	badPrv2 := ECP384PrivateKey(pubStr)
+	_, err = badPrv2.PrivateKey()
+	assert.HasErr(t, err, ErrParsingPrivateKey)
+
+	prvKey, err := prvStr.PrivateKey()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, ecp384PrivateKeys.keys[prvStr], prvKey)
+	assert.Equal(t, len(ecp384PrivateKeys.keys), 1)
+	ecp384PrivateKeys.keys = map[ECP384PrivateKey]*ecdsa.PrivateKey{}
+
+	badPub1 := ECP384PublicKey("asd")
+	_, err = badPub1.PublicKey()
+	assert.HasErr(t, err, ErrDecodingPublicKey)
+
+	badPub2 := ECP384PublicKey(prvStr)
+	_, err = badPub2.PublicKey()
+	assert.HasErr(t, err, ErrParsingPublicKey)
+
+	pubKey, err := pubStr.PublicKey()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(ecp384PublicKeys.keys), 1)
+	assert.Equal(t, ecp384PublicKeys.keys[pubStr], pubKey)
+	ecp384PublicKeys.keys = map[ECP384PublicKey]*ecdsa.PublicKey{}
+
+	// Sign/Verify
+	m := []byte("hello")
+	sig, err := prvStr.Sign(m, crypto.SHA384)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, len(sig), 96)
+	err = pubStr.Verify(m, crypto.SHA384, sig)
+	assert.HasErr(t, err, nil)
+	err = pubStr.Verify(m, crypto.SHA384, sig[:64])
+	assert.HasErr(t, err, ErrVerify)
+
+	// KDF
+	v := []byte("test")
+	v1, err := pubStr.EncryptAsymmetric(v, "123", EncryptionAES128GCM)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, v1.KDF, KDFECDHP384)
+	assert.Equal(t, v1.KDFInput != "", true)
+	assert.Equal(t, v1.KeyID, "123")
+
+	ev, err := ParseEncryptedValue(v1.String())
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, ev, v1)
+
+	out, err := prvStr.DecryptAsymmetric(v1)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, out, v)
+
+	prvStr, _, _ = NewECP384()
+
+	_, err = prvStr.DecryptAsymmetric(v1)
+	assert.HasErr(t, err, ErrDecryptingKey)
+}
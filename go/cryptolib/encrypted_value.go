@@ -23,6 +23,7 @@ var (
 		string(AlgorithmBest),
 		string(KDFECDHX25519),
 		string(KDFECDHP256),
+		string(KDFECDHP384),
 	}
 	EncryptionSymmetric = []string{ //nolint:gochecknoglobals
 		string(EncryptionBest),
@@ -58,6 +59,8 @@ func ParseEncryptedValue(s string) (EncryptedValue, error) {
 				v.KDF = KDFECDHX25519
 			case KDFECDHP256:
 				v.KDF = KDFECDHP256
+			case KDFECDHP384:
+				v.KDF = KDFECDHP384
 			}
 
 			if v.KDF == "" {
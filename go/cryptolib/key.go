@@ -34,6 +34,8 @@ func init() { //nolint:gochecknoinits
 	gob.Register(AES128Key(""))
 	gob.Register(ECP256PrivateKey(""))
 	gob.Register(ECP256PublicKey(""))
+	gob.Register(ECP384PrivateKey(""))
+	gob.Register(ECP384PublicKey(""))
 	gob.Register(Ed25519PrivateKey(""))
 	gob.Register(Ed25519PublicKey(""))
 	gob.Register(RSA2048PrivateKey(""))
@@ -153,6 +155,10 @@ func ParseKey[T KeyProvider](s string) (Key[T], error) {
 			kp = ECP256PrivateKey(r[1])
 		case AlgorithmECP256Public:
 			kp = ECP256PublicKey(r[1])
+		case AlgorithmECP384Private:
+			kp = ECP384PrivateKey(r[1])
+		case AlgorithmECP384Public:
+			kp = ECP384PublicKey(r[1])
 		case AlgorithmEd25519Private:
 			kp = Ed25519PrivateKey(r[1])
 		case AlgorithmEd25519Public:
@@ -274,6 +280,10 @@ func NewKeysAsymmetric[T Algorithm | Encryption](t T) (Key[KeyProviderPrivate],
 		fallthrough
 	case string(KDFECDHP256):
 		prv, pub, err = NewECP256()
+	case string(AlgorithmECP384):
+		fallthrough
+	case string(KDFECDHP384):
+		prv, pub, err = NewECP384()
 	case string(AlgorithmRSA2048):
 		fallthrough
 	case string(EncryptionRSA2048OAEPSHA256):
@@ -285,6 +295,8 @@ func NewKeysAsymmetric[T Algorithm | Encryption](t T) (Key[KeyProviderPrivate],
 			string(KDFECDHX25519),
 			string(AlgorithmECP256),
 			string(KDFECDHP256),
+			string(AlgorithmECP384),
+			string(KDFECDHP384),
 			string(AlgorithmRSA2048),
 			string(EncryptionRSA2048OAEPSHA256),
 		}, ", "))
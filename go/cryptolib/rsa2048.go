@@ -146,6 +146,29 @@ func (r RSA2048PrivateKey) Sign(message []byte, hash crypto.Hash) (signature []b
 	return sig, nil
 }
 
+// SignPSS signs a message using RSASSA-PSS with a salt length equal to the hash length.
+func (r RSA2048PrivateKey) SignPSS(message []byte, hash crypto.Hash) (signature []byte, err error) {
+	k, err := r.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	n := hash.New()
+
+	if _, err := n.Write(message); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCreatingHash, err)
+	}
+
+	sig, err := rsa.SignPSS(rand.Reader, k, hash, n.Sum(nil), &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSign, err)
+	}
+
+	return sig, nil
+}
+
 func (RSA2048PublicKey) Algorithm() Algorithm {
 	return AlgorithmRSA2048Public
 }
@@ -229,3 +252,25 @@ func (r RSA2048PublicKey) Verify(message []byte, hash crypto.Hash, signature []b
 
 	return nil
 }
+
+// VerifyPSS verifies a RSASSA-PSS signature with a salt length equal to the hash length.
+func (r RSA2048PublicKey) VerifyPSS(message []byte, hash crypto.Hash, signature []byte) error {
+	k, err := r.PublicKey()
+	if err != nil {
+		return err
+	}
+
+	n := hash.New()
+
+	if _, err := n.Write(message); err != nil {
+		return fmt.Errorf("%w: %w", ErrCreatingHash, err)
+	}
+
+	if err := rsa.VerifyPSS(k, hash, n.Sum(nil), signature, &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+	}); err != nil {
+		return fmt.Errorf("%w: %w", ErrVerify, err)
+	}
+
+	return nil
+}
@@ -51,6 +51,20 @@ func TestRSA2048(t *testing.T) {
 	err = pub.Verify(m, crypto.SHA256, sig)
 	assert.HasErr(t, err, nil)
 
+	sig, err = prv.SignPSS(m, crypto.SHA256)
+	assert.HasErr(t, err, nil)
+	err = pub.VerifyPSS(m, crypto.SHA256, sig)
+	assert.HasErr(t, err, nil)
+	err = pub.Verify(m, crypto.SHA256, sig)
+	assert.HasErr(t, err, ErrVerify)
+
+	sig, err = prv.Sign(m, crypto.SHA512)
+	assert.HasErr(t, err, nil)
+	err = pub.Verify(m, crypto.SHA512, sig)
+	assert.HasErr(t, err, nil)
+	err = pub.VerifyPSS(m, crypto.SHA512, sig)
+	assert.HasErr(t, err, ErrVerify)
+
 	// External
 	prv = RSA2048PrivateKey("MIIEowIBAAKCAQEApKc8lfc1IXpfAuMu5JhYA7SwKhKthluB+tI+aVFzQjTEwpA9fjUVsxf2XbGxtqppgQzw9MDSYjvFimz7Q4dTTTaAOsbqa6bduM6TJYnuysBGlp3L3SypxyDTEBkIa1mVFtLz5Roya2/kz4i8vA7FiqzP8LJqDPXd8I5ToZYMPTuBaIXORqZO/BMGp5XIRtl076ewCKhCeUK25JSV/aH2gcziKy/aVZ/Fe1C0oEdq3ajWZAp0XUkxBHM5ryheyd72HNkTrZSLVyKmWMUxxAVMoRGmvgJ+WiorUohBcF1ARITFbyejCVzs7eqYmmSdoOM5XRUVxmJkF4IKtC1sB1kiUQIDAQABAoIBAE5UqUQG6SXWG6E6BzMFLsoEidJaNGc43Ws/3iUodJbIl9qf2EFUa9BZ1ADa6lqmU67rQy6NFQlaui2Sjy6fEIgpJ3PO8fLo3Y5v6Bzxs8KNGofI5hWAi/yJpx9/aTv40C6diR4zCk2GW+pHeNJWjK/easZtenpT9ZPdgffbdFMkWpe09FKFPHd37sYwY/6JWdEG6uxZbomQ0ddm5WUoY6xVS57GkerBLNovcBznDm05bLzuB3gbYdKGBrATLBwgFySGkwsXodPT4AL16ffOC6do1GFEHV6wWPQpDU0WY9LFRRT2yN3QwFmIMmbZRLFajcyjkLOl/LAQT0BaPHOOzQkCgYEA0XRO79/d4ZQXkSRC6VOWnkkj+To0naUfZu8UK2WWCN4vppJyKNcA3WcHKj63N3C2uyGj8YqQ6DorZg5O5033l4l9PaJqHd2PiVhftL+4507gCAPSbhw84hdCYUhJz9RhLZrHQBpbBRI7riws8xYjjzfn8pJ8bPnB53YZHTUm54MCgYEAyT47NDGH0NWpEZXJ6UnTcj6GgZbcZDeLakw8V2FD3uLplAtIH6yb/JhqO+X6t+olo3WMU8YAm0+HUelR9/ll1wqIyUUpgB49JXOx29KGEt8bCq8ET3nNoLCn++y63mfSE4iKpCf+jeWhYntj9aqyAjSq1e2TCEKb/7NA2o5hUpsCgYBn9wVfh41I9QslngwgaL8wXjme8cdAIMAPhchLKidoy3B3i+ViZCYnv4YM8AhdWnM5O592u0LmIkl8ZMnBgi/NZg9mUoG9xUYD9Hu86hVLqxkEoXEH+rg1uTnXs9v/bvm1e0g/h1V6lOxOrdq55llMM4HMI+3i4a3fx/z7RHDFJQKBgQCKyEvz9qR/NJnf8rjIFY2on84K2Iss4dFXgTOr3vv7XelPm2glz9fTHxlELZn185f5XjtkGoyYjwP3TTymEmxVHIKwqu2v2Sq6BUuHGWw033+6onAKjylrw+hVKDDG6DpMFkHma151ZQMi841AAnO4abHWznwzmhwS/v+eucoMOQKBgA5QWrE93NDPVK39mAamhxlZJQd6OEM7R18OVZeovg4U2TP9GRQBFGs57VXUK7lDMbTCO7Zscw9IoDSydIFbHeIF48HQwfZ0qiGkGX/yzH7T/z7IYxfvk3F3j7qcJlKvthp/TihvH4q0Ao5uzr3TppyZ6gutgQKXZ9gQhBedpJOi")
 	pub = RSA2048PublicKey("MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEApKc8lfc1IXpfAuMu5JhYA7SwKhKthluB+tI+aVFzQjTEwpA9fjUVsxf2XbGxtqppgQzw9MDSYjvFimz7Q4dTTTaAOsbqa6bduM6TJYnuysBGlp3L3SypxyDTEBkIa1mVFtLz5Roya2/kz4i8vA7FiqzP8LJqDPXd8I5ToZYMPTuBaIXORqZO/BMGp5XIRtl076ewCKhCeUK25JSV/aH2gcziKy/aVZ/Fe1C0oEdq3ajWZAp0XUkxBHM5ryheyd72HNkTrZSLVyKmWMUxxAVMoRGmvgJ+WiorUohBcF1ARITFbyejCVzs7eqYmmSdoOM5XRUVxmJkF4IKtC1sB1kiUQIDAQAB")
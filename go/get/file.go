@@ -10,6 +10,12 @@ import (
 	"time"
 )
 
+// ProgressWriter is an io.Writer that reports progress, like cli.ProgressTask.Writer.  If dst is a ProgressWriter, File calls SetTotal with the size of src, when known, before writing to dst.
+type ProgressWriter interface {
+	io.Writer
+	SetTotal(total int64)
+}
+
 // File gets a file from src and writes it to dst.  If lastModified is supplied, it will be used to ensure the file isn't copied twice.  Returns a non-zero newLastModified if the file has changed.
 func File(ctx context.Context, src string, dst io.Writer, lastModified time.Time) (newLastModified time.Time, err error) {
 	switch {
@@ -20,6 +26,12 @@ func File(ctx context.Context, src string, dst io.Writer, lastModified time.Time
 	}
 }
 
+func setTotal(dst io.Writer, total int64) {
+	if p, ok := dst.(ProgressWriter); ok && total > 0 {
+		p.SetTotal(total)
+	}
+}
+
 // FileCache gets a file from src and writes it to dst, caching it to cachePath.
 func FileCache(ctx context.Context, src string, dst io.Writer, cachePath string) error {
 	cb := bytes.Buffer{}
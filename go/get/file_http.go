@@ -6,20 +6,52 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// HTTPCache is the caching state of a file retrieved via HTTP.
+type HTTPCache struct {
+	ETag         string
+	LastModified time.Time
+
+	// MaxAge is the Cache-Control max-age of the response, or zero if it wasn't provided.
+	MaxAge time.Duration
+
+	// Modified is whether the last request returned new content.
+	Modified bool
+
+	// NoCache is whether the response must be revalidated before each use.
+	NoCache bool
+}
+
 func fileHTTP(ctx context.Context, src string, dst io.Writer, lastModified time.Time) (newLastModified time.Time, err error) {
+	c, err := FileHTTP(ctx, src, dst, HTTPCache{
+		LastModified: lastModified,
+	})
+	if err != nil || !c.Modified {
+		return time.Time{}, err
+	}
+
+	return c.LastModified, nil
+}
+
+// FileHTTP gets a file from an HTTP src and writes it to dst.  The ETag and LastModified values of cache are sent as conditional request headers.  Returns the HTTPCache of the response, dst is only written to if it was modified.
+func FileHTTP(ctx context.Context, src string, dst io.Writer, cache HTTPCache) (HTTPCache, error) {
 	h := strings.Split(src, "#")
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h[0], nil)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("error creating request: %w", err)
+		return cache, fmt.Errorf("error creating request: %w", err)
+	}
+
+	if cache.ETag != "" {
+		req.Header.Add("If-None-Match", cache.ETag)
 	}
 
-	if !lastModified.IsZero() {
-		req.Header.Add("If-Modified-Since", lastModified.UTC().Format(http.TimeFormat))
+	if !cache.LastModified.IsZero() {
+		req.Header.Add("If-Modified-Since", cache.LastModified.UTC().Format(http.TimeFormat))
 	}
 
 	skipVerify := false
@@ -46,7 +78,7 @@ func fileHTTP(ctx context.Context, src string, dst io.Writer, lastModified time.
 
 	res, err := client.Do(req)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("error making request: %w", err)
+		return cache, fmt.Errorf("error making request: %w", err)
 	}
 
 	defer res.Body.Close()
@@ -54,24 +86,60 @@ func fileHTTP(ctx context.Context, src string, dst io.Writer, lastModified time.
 	switch res.StatusCode {
 	case http.StatusOK:
 		if dst != nil {
+			setTotal(dst, res.ContentLength)
+
 			if _, err := io.Copy(dst, res.Body); err != nil {
-				return time.Time{}, fmt.Errorf("error copying response: %w", err)
+				return cache, fmt.Errorf("error copying response: %w", err)
 			}
 		}
 
+		c := HTTPCache{
+			ETag:     res.Header.Get("ETag"),
+			Modified: true,
+		}
+
 		if lm := res.Header.Get("Last-Modified"); lm != "" {
 			t, err := time.Parse(http.TimeFormat, lm)
 			if err != nil {
-				return time.Time{}, fmt.Errorf("error parsing Last-Modified header: %w", err)
+				return cache, fmt.Errorf("error parsing Last-Modified header: %w", err)
 			}
 
-			return t, nil
+			c.LastModified = t
 		}
+
+		c.MaxAge, c.NoCache = parseCacheControl(res.Header.Get("Cache-Control"))
+
+		return c, nil
 	case http.StatusNotModified:
-		return time.Time{}, nil
+		cache.Modified = false
+
+		if cc := res.Header.Get("Cache-Control"); cc != "" {
+			cache.MaxAge, cache.NoCache = parseCacheControl(cc)
+		}
+
+		return cache, nil
 	default:
-		return time.Time{}, fmt.Errorf("bad response from server: %d", res.StatusCode)
+		return cache, fmt.Errorf("bad response from server: %d", res.StatusCode)
+	}
+}
+
+func parseCacheControl(header string) (maxAge time.Duration, noCache bool) {
+	for _, directive := range strings.Split(header, ",") {
+		d := strings.ToLower(strings.TrimSpace(directive))
+
+		switch {
+		case d == "no-cache" || d == "no-store":
+			noCache = true
+		case strings.HasPrefix(d, "max-age="):
+			if s, err := strconv.Atoi(strings.TrimPrefix(d, "max-age=")); err == nil && s > 0 {
+				maxAge = time.Duration(s) * time.Second
+			}
+		}
+	}
+
+	if noCache {
+		maxAge = 0
 	}
 
-	return time.Time{}, nil
+	return maxAge, noCache
 }
@@ -32,6 +32,8 @@ func fileLocal(_ context.Context, src string, dst io.Writer) (time.Time, error)
 	}
 
 	if dst != nil {
+		setTotal(dst, s.Size())
+
 		if _, err := io.Copy(dst, f); err != nil {
 			return time.Time{}, fmt.Errorf("error reading src: %w", err)
 		}
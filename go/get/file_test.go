@@ -144,6 +144,38 @@ func TestFile(t *testing.T) {
 	h.Close()
 }
 
+type progressWriter struct {
+	bytes.Buffer
+
+	total int64
+}
+
+func (p *progressWriter) SetTotal(total int64) {
+	p.total = total
+}
+
+func TestFileProgress(t *testing.T) {
+	ctx := context.Background()
+	h := NewHTTPMock([]string{"/good"}, []byte("Hello World"), time.Now().UTC())
+
+	os.WriteFile("./local", []byte("Hello"), 0600)
+
+	for src, want := range map[string]int64{
+		"file:/./local":   5,
+		h.URL() + "/good": 11,
+	} {
+		p := &progressWriter{}
+
+		_, err := File(ctx, src, p, time.Time{})
+		assert.HasErr(t, err, nil)
+		assert.Equal(t, p.total, want)
+		assert.Equal(t, int64(p.Len()), want)
+	}
+
+	os.Remove("./local")
+	h.Close()
+}
+
 func TestFileCache(t *testing.T) {
 	ctx := context.Background()
 	lm := time.Now().UTC()
@@ -217,3 +249,73 @@ func TestFileCache(t *testing.T) {
 	os.RemoveAll("./cache")
 	h.Close()
 }
+
+func TestFileHTTP(t *testing.T) {
+	ctx := context.Background()
+	h := NewHTTPMock([]string{"/good"}, []byte("Hello World"), time.Now().UTC())
+	h.SetCacheControl("public, max-age=60")
+	h.SetETag(`"1"`)
+
+	b := bytes.Buffer{}
+
+	c, err := FileHTTP(ctx, h.URL()+"/good", &b, HTTPCache{})
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, c, HTTPCache{
+		ETag:         `"1"`,
+		LastModified: h.LastModified(),
+		MaxAge:       time.Minute,
+		Modified:     true,
+	})
+	assert.Equal(t, b.String(), "Hello World")
+
+	b.Reset()
+	h.SetCacheControl("no-cache")
+
+	c, err = FileHTTP(ctx, h.URL()+"/good", &b, HTTPCache{
+		ETag: c.ETag,
+	})
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, c, HTTPCache{
+		ETag:    `"1"`,
+		NoCache: true,
+	})
+	assert.Equal(t, b.Len(), 0)
+
+	r := h.Requests()
+	assert.Equal(t, r[1].Headers.Get("If-None-Match"), `"1"`)
+	assert.Equal(t, r[1].Status, http.StatusNotModified)
+
+	h.SetETag(`"2"`)
+	h.SetBody([]byte("Hello Again"))
+
+	c, err = FileHTTP(ctx, h.URL()+"/good", &b, c)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, c.ETag, `"2"`)
+	assert.Equal(t, c.Modified, true)
+	assert.Equal(t, b.String(), "Hello Again")
+
+	h.Close()
+}
+
+func TestParseCacheControl(t *testing.T) {
+	tests := map[string]struct {
+		wantMaxAge  time.Duration
+		wantNoCache bool
+	}{
+		"":                           {},
+		"max-age=300":                {wantMaxAge: 5 * time.Minute},
+		"public, Max-Age=10":         {wantMaxAge: 10 * time.Second},
+		"max-age=bad":                {},
+		"no-store":                   {wantNoCache: true},
+		"max-age=60, no-cache":       {wantNoCache: true},
+		"must-revalidate, max-age=1": {wantMaxAge: time.Second},
+	}
+
+	for input, tc := range tests {
+		t.Run(input, func(t *testing.T) {
+			maxAge, noCache := parseCacheControl(input)
+			assert.Equal(t, maxAge, tc.wantMaxAge)
+			assert.Equal(t, noCache, tc.wantNoCache)
+		})
+	}
+}
@@ -13,6 +13,8 @@ type HTTPMock struct {
 	mux                  *sync.Mutex
 	server               *httptest.Server
 	responseBody         []byte
+	responseCacheControl string
+	responseETag         string
 	responseLastModified time.Time
 	responsePaths        []string
 }
@@ -68,6 +70,22 @@ func (h *HTTPMock) handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.responseCacheControl != "" {
+		w.Header().Add("cache-control", h.responseCacheControl)
+	}
+
+	if h.responseETag != "" {
+		w.Header().Add("etag", h.responseETag)
+
+		if r.Header.Get("If-None-Match") == h.responseETag {
+			w.WriteHeader(http.StatusNotModified)
+			req.Status = http.StatusNotModified
+			h.requests = append(h.requests, req)
+
+			return
+		}
+	}
+
 	if str := r.Header.Get("If-Modified-Since"); str == h.responseLastModified.Format(http.TimeFormat) {
 		w.WriteHeader(http.StatusNotModified)
 		req.Status = http.StatusNotModified
@@ -112,6 +130,30 @@ func (h *HTTPMock) Requests() []HTTPMockRequest {
 	return req
 }
 
+// SetBody sets the response body for future requests.
+func (h *HTTPMock) SetBody(body []byte) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	h.responseBody = body
+}
+
+// SetCacheControl sets the Cache-Control header for future requests.
+func (h *HTTPMock) SetCacheControl(cacheControl string) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	h.responseCacheControl = cacheControl
+}
+
+// SetETag sets the ETag header for future requests.  Requests with a matching If-None-Match header will receive a 304.
+func (h *HTTPMock) SetETag(etag string) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	h.responseETag = etag
+}
+
 // Close ends the mock.
 func (h *HTTPMock) Close() {
 	h.mux.Lock()
@@ -0,0 +1,77 @@
+package httpsig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ContentDigest returns a Content-Digest header value using SHA-256, per RFC 9530.
+func ContentDigest(body []byte) string {
+	h := sha256.Sum256(body)
+
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(h[:]) + ":"
+}
+
+// checkContentDigest checks body against a Content-Digest header.  Only sha-256 and sha-512 are supported, and every supported digest must match.
+func checkContentDigest(header string, body []byte) error {
+	d, _, err := parseDictionary(header)
+	if err != nil {
+		return err
+	}
+
+	checked := false
+
+	for k, v := range d {
+		var h []byte
+
+		switch strings.ToLower(k) {
+		case "sha-256":
+			s := sha256.Sum256(body)
+			h = s[:]
+		case "sha-512":
+			s := sha512.Sum512(body)
+			h = s[:]
+		default:
+			continue
+		}
+
+		b, err := parseByteSequence(v)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(b, h) {
+			return ErrDigest
+		}
+
+		checked = true
+	}
+
+	if !checked {
+		return fmt.Errorf("%w: no supported algorithm", ErrDigest)
+	}
+
+	return nil
+}
+
+// readBody reads a body and replaces it so it can be read again.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil {
+		return nil, nil
+	}
+
+	b, err := io.ReadAll(*body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error reading body: %w", ErrDigest, err)
+	}
+
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(b))
+
+	return b, nil
+}
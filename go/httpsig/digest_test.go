@@ -0,0 +1,24 @@
+package httpsig
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/candiddev/shared/go/assert"
+)
+
+func base64Std(b []byte) string {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func TestContentDigest(t *testing.T) {
+	// https://www.rfc-editor.org/rfc/rfc9530#appendix-B.1
+	b := []byte(`{"hello": "world"}`)
+
+	assert.Equal(t, ContentDigest(b), "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:")
+	assert.HasErr(t, checkContentDigest(ContentDigest(b), b), nil)
+	assert.HasErr(t, checkContentDigest("sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:", b), nil)
+	assert.HasErr(t, checkContentDigest(ContentDigest(b), []byte("hello")), ErrDigest)
+	assert.HasErr(t, checkContentDigest("md5=:AAAA:", b), ErrDigest)
+	assert.HasErr(t, checkContentDigest("sha-256=AAAA", b), ErrFormat)
+}
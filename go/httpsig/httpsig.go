@@ -0,0 +1,233 @@
+// Package httpsig contains functions for signing and verifying HTTP messages using RFC 9421.
+package httpsig
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/candiddev/shared/go/cryptolib"
+)
+
+var (
+	ErrAlgorithm = errors.New("signature algorithm is not supported")
+	ErrComponent = errors.New("error getting signature component")
+	ErrDigest    = errors.New("content digest does not match")
+	ErrExpired   = errors.New("signature has expired")
+	ErrFormat    = errors.New("signature has invalid format")
+	ErrKeyID     = errors.New("no key matches the signature keyid")
+	ErrMissing   = errors.New("message is not signed")
+	ErrVerify    = errors.New("error verifying signature")
+)
+
+// Algorithm is an HTTP signature algorithm.
+type Algorithm string
+
+// Algorithms from the HTTP Signature Algorithms registry.
+const (
+	AlgorithmECDSAP256SHA256 Algorithm = "ecdsa-p256-sha256"
+	AlgorithmECDSAP384SHA384 Algorithm = "ecdsa-p384-sha384"
+	AlgorithmEd25519         Algorithm = "ed25519"
+	AlgorithmRSAPSSSHA512    Algorithm = "rsa-pss-sha512"
+	AlgorithmRSAV15SHA256    Algorithm = "rsa-v1_5-sha256"
+)
+
+type signerPSS interface {
+	SignPSS(message []byte, hash crypto.Hash) (signature []byte, err error)
+}
+
+type verifierPSS interface {
+	VerifyPSS(message []byte, hash crypto.Hash, signature []byte) error
+}
+
+// getAlgorithm returns the default Algorithm for a key.
+func getAlgorithm(k cryptolib.Algorithm) (Algorithm, error) {
+	switch k { //nolint:exhaustive
+	case cryptolib.AlgorithmECP256Private, cryptolib.AlgorithmECP256Public:
+		return AlgorithmECDSAP256SHA256, nil
+	case cryptolib.AlgorithmECP384Private, cryptolib.AlgorithmECP384Public:
+		return AlgorithmECDSAP384SHA384, nil
+	case cryptolib.AlgorithmEd25519Private, cryptolib.AlgorithmEd25519Public:
+		return AlgorithmEd25519, nil
+	case cryptolib.AlgorithmRSA2048Private, cryptolib.AlgorithmRSA2048Public:
+		return AlgorithmRSAPSSSHA512, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrAlgorithm, k)
+}
+
+func (a Algorithm) getHash() crypto.Hash {
+	switch a {
+	case AlgorithmECDSAP256SHA256, AlgorithmRSAV15SHA256:
+		return crypto.SHA256
+	case AlgorithmECDSAP384SHA384:
+		return crypto.SHA384
+	case AlgorithmRSAPSSSHA512:
+		return crypto.SHA512
+	case AlgorithmEd25519:
+	}
+
+	return 0
+}
+
+// Supports returns whether the Algorithm can be used with a key.
+func (a Algorithm) Supports(k cryptolib.Algorithm) bool {
+	switch a {
+	case AlgorithmECDSAP256SHA256:
+		return k == cryptolib.AlgorithmECP256Private || k == cryptolib.AlgorithmECP256Public
+	case AlgorithmECDSAP384SHA384:
+		return k == cryptolib.AlgorithmECP384Private || k == cryptolib.AlgorithmECP384Public
+	case AlgorithmEd25519:
+		return k == cryptolib.AlgorithmEd25519Private || k == cryptolib.AlgorithmEd25519Public
+	case AlgorithmRSAPSSSHA512, AlgorithmRSAV15SHA256:
+		return k == cryptolib.AlgorithmRSA2048Private || k == cryptolib.AlgorithmRSA2048Public
+	}
+
+	return false
+}
+
+func (a Algorithm) sign(k cryptolib.KeyProviderPrivate, message []byte) ([]byte, error) {
+	if !a.Supports(k.Algorithm()) {
+		return nil, fmt.Errorf("%w: %s can't be used with %s", ErrAlgorithm, a, k.Algorithm())
+	}
+
+	if a == AlgorithmRSAPSSSHA512 {
+		s, ok := k.(signerPSS)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s can't be used with %s", ErrAlgorithm, a, k.Algorithm())
+		}
+
+		return s.SignPSS(message, a.getHash())
+	}
+
+	return k.Sign(message, a.getHash())
+}
+
+func (a Algorithm) verify(k cryptolib.KeyProviderPublic, message, signature []byte) error {
+	if !a.Supports(k.Algorithm()) {
+		return fmt.Errorf("%w: %s can't be used with %s", ErrAlgorithm, a, k.Algorithm())
+	}
+
+	if a == AlgorithmRSAPSSSHA512 {
+		v, ok := k.(verifierPSS)
+		if !ok {
+			return fmt.Errorf("%w: %s can't be used with %s", ErrAlgorithm, a, k.Algorithm())
+		}
+
+		return v.VerifyPSS(message, a.getHash(), signature)
+	}
+
+	return k.Verify(message, a.getHash(), signature)
+}
+
+// message is the parts of a request or response that can be covered by a signature.
+type message struct {
+	header http.Header
+	method string
+	status int
+	url    *url.URL
+}
+
+func requestMessage(r *http.Request) message {
+	u := *r.URL
+
+	if u.Host == "" {
+		u.Host = r.Host
+	}
+
+	if u.Scheme == "" {
+		u.Scheme = "http"
+
+		if r.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+
+	return message{
+		header: r.Header,
+		method: r.Method,
+		url:    &u,
+	}
+}
+
+func responseMessage(r *http.Response) message {
+	return message{
+		header: r.Header,
+		status: r.StatusCode,
+	}
+}
+
+// component returns the value of a component identifier.
+func (m message) component(name string) (string, error) {
+	if strings.HasPrefix(name, "@") {
+		if name == "@status" {
+			if m.status == 0 {
+				return "", fmt.Errorf("%w: %s is only valid for responses", ErrComponent, name)
+			}
+
+			return strconv.Itoa(m.status), nil
+		}
+
+		if m.url == nil {
+			return "", fmt.Errorf("%w: %s is only valid for requests", ErrComponent, name)
+		}
+
+		switch name {
+		case "@authority":
+			return strings.ToLower(m.url.Host), nil
+		case "@method":
+			return m.method, nil
+		case "@path":
+			if p := m.url.EscapedPath(); p != "" {
+				return p, nil
+			}
+
+			return "/", nil
+		case "@query":
+			return "?" + m.url.RawQuery, nil
+		case "@request-target":
+			return m.url.RequestURI(), nil
+		case "@scheme":
+			return strings.ToLower(m.url.Scheme), nil
+		case "@target-uri":
+			return m.url.String(), nil
+		}
+
+		return "", fmt.Errorf("%w: %s is not supported", ErrComponent, name)
+	}
+
+	v, ok := m.header[http.CanonicalHeaderKey(name)]
+	if !ok {
+		return "", fmt.Errorf("%w: header %s is missing", ErrComponent, name)
+	}
+
+	out := make([]string, len(v))
+
+	for i := range v {
+		out[i] = strings.TrimSpace(v[i])
+	}
+
+	return strings.Join(out, ", "), nil
+}
+
+// base returns the signature base of a message.
+func (m message) base(components []string, signatureParams string) ([]byte, error) {
+	b := strings.Builder{}
+
+	for i := range components {
+		v, err := m.component(components[i])
+		if err != nil {
+			return nil, err
+		}
+
+		b.WriteString(strconv.Quote(components[i]) + ": " + v + "\n")
+	}
+
+	b.WriteString(`"@signature-params": ` + signatureParams)
+
+	return []byte(b.String()), nil
+}
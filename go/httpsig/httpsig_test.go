@@ -0,0 +1,130 @@
+package httpsig
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/cryptolib"
+)
+
+// https://www.rfc-editor.org/rfc/rfc9421#appendix-B.1.4
+var (
+	rfcEd25519Private = cryptolib.Ed25519PrivateKey("MC4CAQAwBQYDK2VwBCIEIJ+DYvh6SEqVTm50DFtMDoQikTmiCqirVv9mWG9qfSnF")
+	rfcEd25519Public  = cryptolib.Ed25519PublicKey("MCowBQYDK2VwAyEAJrQLj5P/89iXES9+vFgrIy29clF9CC/oPPsw3c5D0bs=")
+)
+
+// https://www.rfc-editor.org/rfc/rfc9421#appendix-B.2
+func rfcRequest() *http.Request {
+	r, _ := http.NewRequest(http.MethodPost, "http://example.com/foo?param=Value&Pet=dog", strings.NewReader(`{"hello": "world"}`))
+	r.Header.Set("Content-Digest", "sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:")
+	r.Header.Set("Content-Length", "18")
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Date", "Tue, 20 Apr 2021 02:07:55 GMT")
+
+	return r
+}
+
+func TestRFC9421(t *testing.T) {
+	// https://www.rfc-editor.org/rfc/rfc9421#appendix-B.2.6
+	r := rfcRequest()
+	r.Header.Set("Signature-Input", `sig-b26=("date" "@method" "@path" "@authority" "content-type" "content-length");created=1618884473;keyid="test-key-ed25519"`)
+	r.Header.Set("Signature", `sig-b26=:wqcAqbmYJ2ji2glfAMaRy4gruYYnx2nEFN2HN6jrnDnQCK1u02Gb04v9EDgwUPiu4A0w6vuQv5lIp5WPpBKRCw==:`)
+
+	p, err := parseParams(`("date" "@method" "@path" "@authority" "content-type" "content-length");created=1618884473;keyid="test-key-ed25519"`)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, p.String(), p.raw)
+
+	b, err := requestMessage(r).base(p.components, p.raw)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, string(b), `"date": Tue, 20 Apr 2021 02:07:55 GMT
+"@method": POST
+"@path": /foo
+"@authority": example.com
+"content-type": application/json
+"content-length": 18
+"@signature-params": ("date" "@method" "@path" "@authority" "content-type" "content-length");created=1618884473;keyid="test-key-ed25519"`)
+
+	sig, err := AlgorithmEd25519.sign(rfcEd25519Private, b)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, "sig-b26=:"+base64Std(sig)+":", r.Header.Get("Signature"))
+
+	v := Verifier{
+		Keys: cryptolib.Keys[cryptolib.KeyProviderPublic]{
+			{
+				ID:  "test-key-ed25519",
+				Key: rfcEd25519Public,
+			},
+		},
+	}
+
+	k, err := v.VerifyRequest(r)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, k.ID, "test-key-ed25519")
+
+	r.Header.Set("Content-Type", "text/plain")
+
+	_, err = v.VerifyRequest(r)
+	assert.HasErr(t, err, ErrVerify)
+}
+
+func TestComponent(t *testing.T) {
+	r := rfcRequest()
+	r.Header.Add("X-Multi", " a ")
+	r.Header.Add("X-Multi", "b")
+	m := requestMessage(r)
+
+	tests := map[string]struct {
+		err  error
+		want string
+	}{
+		"@authority": {
+			want: "example.com",
+		},
+		"@method": {
+			want: "POST",
+		},
+		"@path": {
+			want: "/foo",
+		},
+		"@query": {
+			want: "?param=Value&Pet=dog",
+		},
+		"@request-target": {
+			want: "/foo?param=Value&Pet=dog",
+		},
+		"@scheme": {
+			want: "http",
+		},
+		"@status": {
+			err: ErrComponent,
+		},
+		"@target-uri": {
+			want: "http://example.com/foo?param=Value&Pet=dog",
+		},
+		"@unknown": {
+			err: ErrComponent,
+		},
+		"x-missing": {
+			err: ErrComponent,
+		},
+		"x-multi": {
+			want: "a, b",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := m.component(name)
+			assert.HasErr(t, err, tc.err)
+			assert.Equal(t, got, tc.want)
+		})
+	}
+
+	got, err := responseMessage(&http.Response{
+		StatusCode: 200,
+	}).component("@status")
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, got, "200")
+}
@@ -0,0 +1,27 @@
+package httpsig
+
+import (
+	"net/http"
+
+	"github.com/candiddev/shared/go/errs"
+	"github.com/candiddev/shared/go/logger"
+)
+
+// Handler wraps next, rejecting requests without a valid signature.  The keyid of the verifying key is added as a logger attribute.
+func (v *Verifier) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		k, err := v.VerifyRequest(r)
+		if err != nil {
+			e := logger.Error(ctx, errs.ErrSenderUnauthorized.Wrap(err))
+			http.Error(w, e.Message(), e.Status())
+
+			return
+		}
+
+		ctx = logger.SetAttribute(ctx, "keyid", k.ID)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
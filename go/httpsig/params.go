@@ -0,0 +1,186 @@
+package httpsig
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// params are the signature parameters of a Signature-Input member.
+type params struct {
+	alg        Algorithm
+	components []string
+	created    int64
+	expires    int64
+	keyID      string
+	nonce      string
+	tag        string
+
+	// raw is the serialized value, used as the @signature-params component.
+	raw string
+}
+
+func (p params) String() string {
+	s := make([]string, len(p.components))
+
+	for i := range p.components {
+		s[i] = strconv.Quote(p.components[i])
+	}
+
+	out := "(" + strings.Join(s, " ") + ")"
+
+	if p.created != 0 {
+		out += ";created=" + strconv.FormatInt(p.created, 10)
+	}
+
+	if p.expires != 0 {
+		out += ";expires=" + strconv.FormatInt(p.expires, 10)
+	}
+
+	for _, v := range [][2]string{
+		{"nonce", p.nonce},
+		{"alg", string(p.alg)},
+		{"keyid", p.keyID},
+		{"tag", p.tag},
+	} {
+		if v[1] != "" {
+			out += ";" + v[0] + "=" + strconv.Quote(v[1])
+		}
+	}
+
+	return out
+}
+
+// splitTopLevel splits s by sep, ignoring separators within quoted strings or parentheses.
+func splitTopLevel(s string, sep rune) []string {
+	out := []string{}
+	depth := 0
+	escape := false
+	quote := false
+	start := 0
+
+	for i, c := range s {
+		switch {
+		case escape:
+			escape = false
+		case quote && c == '\\':
+			escape = true
+		case c == '"':
+			quote = !quote
+		case quote:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			out = append(out, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(out, s[start:])
+}
+
+// parseDictionary parses a structured field dictionary into raw member values.
+func parseDictionary(header string) (map[string]string, []string, error) {
+	m := map[string]string{}
+	labels := []string{}
+
+	if strings.TrimSpace(header) == "" {
+		return m, labels, nil
+	}
+
+	for _, member := range splitTopLevel(header, ',') {
+		k, v, ok := strings.Cut(strings.TrimSpace(member), "=")
+		if !ok || k == "" {
+			return nil, nil, fmt.Errorf("%w: invalid dictionary member %s", ErrFormat, member)
+		}
+
+		if _, ok := m[k]; !ok {
+			labels = append(labels, k)
+		}
+
+		m[k] = strings.TrimSpace(v)
+	}
+
+	return m, labels, nil
+}
+
+func parseString(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("%w: %s is not a string", ErrFormat, s)
+	}
+
+	v, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrFormat, err)
+	}
+
+	return v, nil
+}
+
+// parseParams parses a Signature-Input member.
+func parseParams(raw string) (params, error) {
+	p := params{
+		raw: raw,
+	}
+
+	parts := splitTopLevel(raw, ';')
+	list := strings.TrimSpace(parts[0])
+
+	if len(list) < 2 || list[0] != '(' || list[len(list)-1] != ')' {
+		return p, fmt.Errorf("%w: components must be an inner list", ErrFormat)
+	}
+
+	for _, c := range strings.Fields(list[1 : len(list)-1]) {
+		s, err := parseString(c)
+		if err != nil {
+			return p, fmt.Errorf("%w: component parameters are not supported", err)
+		}
+
+		p.components = append(p.components, s)
+	}
+
+	for _, param := range parts[1:] {
+		k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+
+		var err error
+
+		switch k {
+		case "alg":
+			var a string
+			a, err = parseString(v)
+			p.alg = Algorithm(a)
+		case "created":
+			p.created, err = strconv.ParseInt(v, 10, 64)
+		case "expires":
+			p.expires, err = strconv.ParseInt(v, 10, 64)
+		case "keyid":
+			p.keyID, err = parseString(v)
+		case "nonce":
+			p.nonce, err = parseString(v)
+		case "tag":
+			p.tag, err = parseString(v)
+		}
+
+		if err != nil {
+			return p, fmt.Errorf("%w: parameter %s: %w", ErrFormat, k, err)
+		}
+	}
+
+	return p, nil
+}
+
+func parseByteSequence(s string) ([]byte, error) {
+	if len(s) < 2 || s[0] != ':' || s[len(s)-1] != ':' {
+		return nil, fmt.Errorf("%w: %s is not a byte sequence", ErrFormat, s)
+	}
+
+	b, err := base64.StdEncoding.DecodeString(s[1 : len(s)-1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFormat, err)
+	}
+
+	return b, nil
+}
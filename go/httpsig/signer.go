@@ -0,0 +1,124 @@
+package httpsig
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/candiddev/shared/go/cryptolib"
+)
+
+// Signer signs HTTP requests and responses.
+type Signer struct {
+	// Algorithm is the signature Algorithm.  Defaults to the Algorithm for the key, or rsa-pss-sha512 for RSA keys.
+	Algorithm Algorithm
+
+	// Components are the component identifiers covered by the signature.  Defaults to "@method" and "@target-uri" for requests, "@status" for responses, and "content-digest" if there is a body.
+	Components []string
+
+	// Expires is added to the created time to set the expires parameter.  If zero, signatures don't expire.
+	Expires time.Duration
+
+	// Key is used to sign messages.  The key ID is used as the keyid parameter.
+	Key cryptolib.Key[cryptolib.KeyProviderPrivate]
+
+	// Label is the name of the signature.  Defaults to "sig1".
+	Label string
+
+	// Tag is an optional application specific tag parameter.
+	Tag string
+}
+
+// SignRequest adds Signature-Input and Signature headers to a request.  If content-digest is covered and the Content-Digest header is missing, it is added using the body.
+func (s *Signer) SignRequest(r *http.Request) error {
+	c, err := s.prepare(r.Header, &r.Body, "@method", "@target-uri")
+	if err != nil {
+		return err
+	}
+
+	return s.sign(requestMessage(r), c)
+}
+
+// SignResponse adds Signature-Input and Signature headers to a response.  If content-digest is covered and the Content-Digest header is missing, it is added using the body.
+func (s *Signer) SignResponse(r *http.Response) error {
+	c, err := s.prepare(r.Header, &r.Body, "@status")
+	if err != nil {
+		return err
+	}
+
+	return s.sign(responseMessage(r), c)
+}
+
+// prepare returns the components to sign and adds a Content-Digest header if necessary.
+func (s *Signer) prepare(h http.Header, body *io.ReadCloser, defaults ...string) ([]string, error) {
+	b, err := readBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	c := s.Components
+	if c == nil {
+		c = defaults
+
+		if len(b) > 0 {
+			c = append(c, "content-digest")
+		}
+	}
+
+	for i := range c {
+		if c[i] == "content-digest" && h.Get("Content-Digest") == "" {
+			h.Set("Content-Digest", ContentDigest(b))
+		}
+	}
+
+	return c, nil
+}
+
+func (s *Signer) sign(m message, components []string) error {
+	a := s.Algorithm
+	if a == "" {
+		var err error
+
+		a, err = getAlgorithm(s.Key.Key.Algorithm())
+		if err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+
+	p := params{
+		alg:        a,
+		components: components,
+		created:    now.Unix(),
+		keyID:      s.Key.ID,
+		tag:        s.Tag,
+	}
+
+	if s.Expires != 0 {
+		p.expires = now.Add(s.Expires).Unix()
+	}
+
+	p.raw = p.String()
+
+	b, err := m.base(components, p.raw)
+	if err != nil {
+		return err
+	}
+
+	sig, err := a.sign(s.Key.Key, b)
+	if err != nil {
+		return err
+	}
+
+	l := s.Label
+	if l == "" {
+		l = "sig1"
+	}
+
+	m.header.Add("Signature-Input", l+"="+p.raw)
+	m.header.Add("Signature", l+"=:"+base64.StdEncoding.EncodeToString(sig)+":")
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package httpsig
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/cryptolib"
+)
+
+func TestSigner(t *testing.T) {
+	ed25519prv, ed25519pub, _ := cryptolib.NewEd25519()
+	ecp256prv, ecp256pub, _ := cryptolib.NewECP256()
+	ecp384prv, ecp384pub, _ := cryptolib.NewECP384()
+	rsa2048prv, rsa2048pub, _ := cryptolib.NewRSA2048()
+
+	tests := map[string]struct {
+		algorithm Algorithm
+		private   cryptolib.KeyProviderPrivate
+		public    cryptolib.KeyProviderPublic
+	}{
+		"ed25519": {
+			private: ed25519prv,
+			public:  ed25519pub,
+		},
+		"ecp256": {
+			private: ecp256prv,
+			public:  ecp256pub,
+		},
+		"ecp384": {
+			private: ecp384prv,
+			public:  ecp384pub,
+		},
+		"rsa-pss-sha512": {
+			private: rsa2048prv,
+			public:  rsa2048pub,
+		},
+		"rsa-v1_5-sha256": {
+			algorithm: AlgorithmRSAV15SHA256,
+			private:   rsa2048prv,
+			public:    rsa2048pub,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := Signer{
+				Algorithm: tc.algorithm,
+				Expires:   time.Minute,
+				Key: cryptolib.Key[cryptolib.KeyProviderPrivate]{
+					ID:  "1",
+					Key: tc.private,
+				},
+			}
+
+			v := Verifier{
+				Keys: cryptolib.Keys[cryptolib.KeyProviderPublic]{
+					{
+						ID:  "1",
+						Key: tc.public,
+					},
+				},
+				MaxAge:             time.Minute,
+				RequiredComponents: []string{"@method", "@target-uri", "content-digest"},
+			}
+
+			if tc.algorithm != "" {
+				v.Algorithms = []Algorithm{tc.algorithm}
+			}
+
+			r, _ := http.NewRequest(http.MethodPost, "https://example.com/hook", strings.NewReader("body"))
+			assert.HasErr(t, s.SignRequest(r), nil)
+			assert.Equal(t, r.Header.Get("Content-Digest"), ContentDigest([]byte("body")))
+
+			k, err := v.VerifyRequest(r)
+			assert.HasErr(t, err, nil)
+			assert.Equal(t, k.ID, "1")
+
+			b, _ := io.ReadAll(r.Body)
+			assert.Equal(t, string(b), "body")
+
+			// Body is checked against the digest.
+			r.Body = io.NopCloser(strings.NewReader("other"))
+			_, err = v.VerifyRequest(r)
+			assert.HasErr(t, err, ErrDigest)
+
+			res := &http.Response{
+				Body:       io.NopCloser(strings.NewReader("response")),
+				Header:     http.Header{},
+				StatusCode: http.StatusAccepted,
+			}
+
+			assert.HasErr(t, s.SignResponse(res), nil)
+
+			v.RequiredComponents = []string{"@status"}
+			_, err = v.VerifyResponse(res)
+			assert.HasErr(t, err, nil)
+
+			res.StatusCode = http.StatusOK
+			_, err = v.VerifyResponse(res)
+			assert.HasErr(t, err, ErrVerify)
+		})
+	}
+}
+
+func TestVerifier(t *testing.T) {
+	prv, pub, _ := cryptolib.NewEd25519()
+	_, other, _ := cryptolib.NewEd25519()
+
+	sign := func(s Signer) *http.Request {
+		r, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+		s.Key = cryptolib.Key[cryptolib.KeyProviderPrivate]{
+			ID:  "1",
+			Key: prv,
+		}
+		s.SignRequest(r)
+
+		return r
+	}
+
+	keys := cryptolib.Keys[cryptolib.KeyProviderPublic]{
+		{
+			ID:  "1",
+			Key: pub,
+		},
+	}
+
+	// proxied is the request seen by a server behind a proxy terminating TLS.
+	proxied := func(r *http.Request) *http.Request {
+		p := httptest.NewRequest(http.MethodGet, "/", nil)
+		p.Header = r.Header.Clone()
+
+		return p
+	}
+
+	tests := map[string]struct {
+		err      error
+		request  *http.Request
+		verifier Verifier
+	}{
+		"good": {
+			request: sign(Signer{}),
+			verifier: Verifier{
+				Keys: keys,
+			},
+		},
+		"label": {
+			request: sign(Signer{
+				Label: "other",
+			}),
+			verifier: Verifier{
+				Keys:  keys,
+				Label: "sig1",
+			},
+			err: ErrMissing,
+		},
+		"missing": {
+			request: func() *http.Request {
+				r, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+
+				return r
+			}(),
+			verifier: Verifier{
+				Keys: keys,
+			},
+			err: ErrMissing,
+		},
+		"expired": {
+			request: sign(Signer{
+				Expires: -1 * time.Minute,
+			}),
+			verifier: Verifier{
+				Keys: keys,
+			},
+			err: ErrExpired,
+		},
+		"keyid": {
+			request: sign(Signer{}),
+			verifier: Verifier{
+				Keys: cryptolib.Keys[cryptolib.KeyProviderPublic]{
+					{
+						ID:  "2",
+						Key: pub,
+					},
+				},
+			},
+			err: ErrKeyID,
+		},
+		"wrong key": {
+			request: sign(Signer{}),
+			verifier: Verifier{
+				Keys: cryptolib.Keys[cryptolib.KeyProviderPublic]{
+					{
+						ID:  "1",
+						Key: other,
+					},
+				},
+			},
+			err: ErrVerify,
+		},
+		"required": {
+			request: sign(Signer{}),
+			verifier: Verifier{
+				Keys:               keys,
+				RequiredComponents: []string{"content-digest"},
+			},
+			err: ErrVerify,
+		},
+		"proxy": {
+			request: proxied(sign(Signer{})),
+			verifier: Verifier{
+				Keys: keys,
+			},
+			err: ErrVerify,
+		},
+		"proxy base url": {
+			request: proxied(sign(Signer{})),
+			verifier: Verifier{
+				BaseURL: "https://example.com",
+				Keys:    keys,
+			},
+		},
+		"algorithm": {
+			request: sign(Signer{}),
+			verifier: Verifier{
+				Algorithms: []Algorithm{AlgorithmECDSAP256SHA256},
+				Keys:       keys,
+			},
+			err: ErrAlgorithm,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.verifier.VerifyRequest(tc.request)
+			assert.HasErr(t, err, tc.err)
+		})
+	}
+
+	s := Signer{
+		Algorithm: AlgorithmECDSAP256SHA256,
+		Key: cryptolib.Key[cryptolib.KeyProviderPrivate]{
+			Key: prv,
+		},
+	}
+	r, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	assert.HasErr(t, s.SignRequest(r), ErrAlgorithm)
+}
@@ -0,0 +1,31 @@
+package httpsig
+
+import (
+	"net/http"
+)
+
+// Transport is an http.RoundTripper that signs requests using a Signer.
+type Transport struct {
+	// Base is the RoundTripper used to send signed requests.  Defaults to http.DefaultTransport.
+	Base   http.RoundTripper
+	Signer *Signer
+}
+
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	b := t.Base
+	if b == nil {
+		b = http.DefaultTransport
+	}
+
+	req := r.Clone(r.Context())
+
+	if err := t.Signer.SignRequest(req); err != nil {
+		if r.Body != nil {
+			r.Body.Close()
+		}
+
+		return nil, err
+	}
+
+	return b.RoundTrip(req)
+}
@@ -0,0 +1,62 @@
+package httpsig
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/cryptolib"
+	"github.com/candiddev/shared/go/logger"
+)
+
+func TestTransportHandler(t *testing.T) {
+	logger.UseTestLogger(t)
+
+	prv, pub, _ := cryptolib.NewECP256()
+
+	v := Verifier{
+		Keys: cryptolib.Keys[cryptolib.KeyProviderPublic]{
+			{
+				ID:  "webhook",
+				Key: pub,
+			},
+		},
+		RequiredComponents: []string{"@method", "@target-uri", "content-digest"},
+	}
+
+	var gotBody, gotKeyID string
+
+	srv := httptest.NewServer(v.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		gotKeyID = logger.GetAttribute(r.Context(), "keyid")
+		w.WriteHeader(http.StatusNoContent)
+	})))
+	defer srv.Close()
+
+	c := http.Client{
+		Transport: &Transport{
+			Signer: &Signer{
+				Key: cryptolib.Key[cryptolib.KeyProviderPrivate]{
+					ID:  "webhook",
+					Key: prv,
+				},
+			},
+		},
+	}
+
+	res, err := c.Post(srv.URL+"/hook?a=b", "application/json", strings.NewReader(`{"event":"test"}`))
+	assert.HasErr(t, err, nil)
+	res.Body.Close()
+	assert.Equal(t, res.StatusCode, http.StatusNoContent)
+	assert.Equal(t, gotBody, `{"event":"test"}`)
+	assert.Equal(t, gotKeyID, "webhook")
+
+	res, err = http.Post(srv.URL+"/hook", "application/json", strings.NewReader(`{"event":"test"}`))
+	assert.HasErr(t, err, nil)
+	res.Body.Close()
+	assert.Equal(t, res.StatusCode, http.StatusUnauthorized)
+}
@@ -0,0 +1,202 @@
+package httpsig
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/candiddev/shared/go/cryptolib"
+)
+
+// Verifier verifies the signatures of HTTP requests and responses.
+type Verifier struct {
+	// Algorithms is the list of Algorithms the Verifier will accept.  If empty, each key only accepts its default Algorithm.
+	Algorithms []Algorithm
+
+	// BaseURL is the external scheme and authority of the server, like https://example.com, used for the request components instead of the ones seen by the server.  It should be set when a proxy terminates TLS or changes the Host, as the client signs the URL it requested.
+	BaseURL string
+
+	// Keys are selected using the keyid parameter.  If a signature has no keyid, every key is tried.
+	Keys cryptolib.Keys[cryptolib.KeyProviderPublic]
+
+	// Label is the name of the signature to verify.  If empty, any signature can be valid.
+	Label string
+
+	// MaxAge is the maximum age of the created parameter.  If zero, created is not required.
+	MaxAge time.Duration
+
+	// RequiredComponents must be covered by the signature, like "@method", "@target-uri", and "content-digest".
+	RequiredComponents []string
+}
+
+// VerifyRequest verifies the signature of a request and returns the key that verified it.  If content-digest is covered, the body is checked against it.
+func (v *Verifier) VerifyRequest(r *http.Request) (cryptolib.Key[cryptolib.KeyProviderPublic], error) {
+	m := requestMessage(r)
+
+	if v.BaseURL != "" {
+		u, err := url.Parse(v.BaseURL)
+		if err != nil {
+			return cryptolib.Key[cryptolib.KeyProviderPublic]{}, fmt.Errorf("%w: invalid BaseURL: %w", ErrComponent, err)
+		}
+
+		m.url.Host = u.Host
+		m.url.Scheme = u.Scheme
+	}
+
+	return v.verify(m, &r.Body)
+}
+
+// VerifyResponse verifies the signature of a response and returns the key that verified it.  If content-digest is covered, the body is checked against it.
+func (v *Verifier) VerifyResponse(r *http.Response) (cryptolib.Key[cryptolib.KeyProviderPublic], error) {
+	return v.verify(responseMessage(r), &r.Body)
+}
+
+func (v *Verifier) allows(a Algorithm, k cryptolib.KeyProviderPublic) bool {
+	if !a.Supports(k.Algorithm()) {
+		return false
+	}
+
+	if len(v.Algorithms) == 0 {
+		d, err := getAlgorithm(k.Algorithm())
+
+		return err == nil && d == a
+	}
+
+	return slices.Contains(v.Algorithms, a)
+}
+
+func (v *Verifier) verify(m message, body *io.ReadCloser) (cryptolib.Key[cryptolib.KeyProviderPublic], error) {
+	var k cryptolib.Key[cryptolib.KeyProviderPublic]
+
+	inputs, labels, err := parseDictionary(m.header.Get("Signature-Input"))
+	if err != nil {
+		return k, err
+	}
+
+	signatures, _, err := parseDictionary(m.header.Get("Signature"))
+	if err != nil {
+		return k, err
+	}
+
+	if v.Label != "" {
+		labels = []string{v.Label}
+	}
+
+	err = ErrMissing
+
+	for _, l := range labels {
+		i, ok := inputs[l]
+		if !ok {
+			continue
+		}
+
+		s, ok := signatures[l]
+		if !ok {
+			continue
+		}
+
+		k, err = v.verifySignature(m, body, i, s)
+		if err == nil {
+			return k, nil
+		}
+	}
+
+	return k, err
+}
+
+func (v *Verifier) verifySignature(m message, body *io.ReadCloser, input, signature string) (cryptolib.Key[cryptolib.KeyProviderPublic], error) {
+	var k cryptolib.Key[cryptolib.KeyProviderPublic]
+
+	p, err := parseParams(input)
+	if err != nil {
+		return k, err
+	}
+
+	sig, err := parseByteSequence(signature)
+	if err != nil {
+		return k, err
+	}
+
+	for i := range v.RequiredComponents {
+		if !slices.Contains(p.components, v.RequiredComponents[i]) {
+			return k, fmt.Errorf("%w: %s is not covered", ErrVerify, v.RequiredComponents[i])
+		}
+	}
+
+	now := time.Now()
+
+	if p.expires != 0 && now.After(time.Unix(p.expires, 0)) {
+		return k, ErrExpired
+	}
+
+	if v.MaxAge != 0 && (p.created == 0 || now.Sub(time.Unix(p.created, 0)) > v.MaxAge) {
+		return k, ErrExpired
+	}
+
+	if p.created != 0 && time.Unix(p.created, 0).After(now.Add(time.Minute)) {
+		return k, fmt.Errorf("%w: created is in the future", ErrVerify)
+	}
+
+	keys := cryptolib.Keys[cryptolib.KeyProviderPublic]{}
+
+	for i := range v.Keys {
+		if p.keyID == "" || v.Keys[i].ID == p.keyID {
+			keys = append(keys, v.Keys[i])
+		}
+	}
+
+	if len(keys) == 0 {
+		return k, fmt.Errorf("%w: %s", ErrKeyID, p.keyID)
+	}
+
+	b, err := m.base(p.components, p.raw)
+	if err != nil {
+		return k, err
+	}
+
+	err = ErrAlgorithm
+
+	for i := range keys {
+		a := p.alg
+		if a == "" {
+			a, err = getAlgorithm(keys[i].Key.Algorithm())
+			if err != nil {
+				continue
+			}
+		}
+
+		if !v.allows(a, keys[i].Key) {
+			err = fmt.Errorf("%w: %s is not allowed", ErrAlgorithm, a)
+
+			continue
+		}
+
+		if err = a.verify(keys[i].Key, b, sig); err == nil {
+			k = keys[i]
+
+			break
+		}
+
+		err = fmt.Errorf("%w: %w", ErrVerify, err)
+	}
+
+	if err != nil {
+		return k, err
+	}
+
+	if slices.Contains(p.components, "content-digest") {
+		b, err := readBody(body)
+		if err != nil {
+			return k, err
+		}
+
+		if err := checkContentDigest(m.header.Get("Content-Digest"), b); err != nil {
+			return k, err
+		}
+	}
+
+	return k, nil
+}
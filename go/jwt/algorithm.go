@@ -0,0 +1,129 @@
+package jwt
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/candiddev/shared/go/cryptolib"
+)
+
+// Algorithm is how the JWT will be signed.
+type Algorithm string
+
+// Algorithms supported for JWT signing.
+const (
+	AlgorithmES256 Algorithm = "ES256"
+	AlgorithmES384 Algorithm = "ES384"
+	AlgorithmEdDSA Algorithm = "EdDSA"
+	AlgorithmPS256 Algorithm = "PS256"
+	AlgorithmRS256 Algorithm = "RS256"
+	AlgorithmRS384 Algorithm = "RS384"
+	AlgorithmRS512 Algorithm = "RS512"
+
+	// Deprecated: use AlgorithmES256.
+	AlgorithmES2565 = AlgorithmES256
+)
+
+type signerPSS interface {
+	SignPSS(message []byte, hash crypto.Hash) (signature []byte, err error)
+}
+
+type verifierPSS interface {
+	VerifyPSS(message []byte, hash crypto.Hash, signature []byte) error
+}
+
+// getSigningMethod returns the default Algorithm for a key.
+func getSigningMethod(k cryptolib.Algorithm) (Algorithm, error) {
+	switch k { //nolint:exhaustive
+	case cryptolib.AlgorithmECP256Private:
+		fallthrough
+	case cryptolib.AlgorithmECP256Public:
+		return AlgorithmES256, nil
+	case cryptolib.AlgorithmECP384Private:
+		fallthrough
+	case cryptolib.AlgorithmECP384Public:
+		return AlgorithmES384, nil
+	case cryptolib.AlgorithmEd25519Private:
+		fallthrough
+	case cryptolib.AlgorithmEd25519Public:
+		return AlgorithmEdDSA, nil
+	case cryptolib.AlgorithmRSA2048Private:
+		fallthrough
+	case cryptolib.AlgorithmRSA2048Public:
+		return AlgorithmRS256, nil
+	}
+
+	return "", fmt.Errorf("%s: %w", k, ErrGetSigningMethod)
+}
+
+func (a Algorithm) getHash() crypto.Hash {
+	switch a {
+	case AlgorithmES256:
+		fallthrough
+	case AlgorithmPS256:
+		fallthrough
+	case AlgorithmRS256:
+		return crypto.SHA256
+	case AlgorithmES384:
+		fallthrough
+	case AlgorithmRS384:
+		return crypto.SHA384
+	case AlgorithmRS512:
+		return crypto.SHA512
+	case AlgorithmEdDSA:
+	}
+
+	return 0
+}
+
+// Supports returns whether the Algorithm can be used with a key.
+func (a Algorithm) Supports(k cryptolib.Algorithm) bool {
+	switch a {
+	case AlgorithmES256:
+		return k == cryptolib.AlgorithmECP256Private || k == cryptolib.AlgorithmECP256Public
+	case AlgorithmES384:
+		return k == cryptolib.AlgorithmECP384Private || k == cryptolib.AlgorithmECP384Public
+	case AlgorithmEdDSA:
+		return k == cryptolib.AlgorithmEd25519Private || k == cryptolib.AlgorithmEd25519Public
+	case AlgorithmPS256:
+		fallthrough
+	case AlgorithmRS256:
+		fallthrough
+	case AlgorithmRS384:
+		fallthrough
+	case AlgorithmRS512:
+		return k == cryptolib.AlgorithmRSA2048Private || k == cryptolib.AlgorithmRSA2048Public
+	}
+
+	return false
+}
+
+func (a Algorithm) sign(k cryptolib.KeyProviderPrivate, message []byte) ([]byte, error) {
+	if a == AlgorithmPS256 {
+		s, ok := k.(signerPSS)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s can't be used with %s", ErrGetSigningMethod, a, k.Algorithm())
+		}
+
+		return s.SignPSS(message, a.getHash())
+	}
+
+	return k.Sign(message, a.getHash())
+}
+
+func (a Algorithm) verify(k cryptolib.KeyProviderPublic, message, signature []byte) error {
+	if !a.Supports(k.Algorithm()) {
+		return ErrParseSigningMethod
+	}
+
+	if a == AlgorithmPS256 {
+		v, ok := k.(verifierPSS)
+		if !ok {
+			return ErrParseSigningMethod
+		}
+
+		return v.VerifyPSS(message, a.getHash(), signature)
+	}
+
+	return k.Verify(message, a.getHash(), signature)
+}
@@ -0,0 +1,42 @@
+package jwt
+
+import (
+	"testing"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/cryptolib"
+)
+
+func TestGetSigningMethod(t *testing.T) {
+	tests := map[cryptolib.Algorithm]Algorithm{
+		cryptolib.AlgorithmECP256Private:  AlgorithmES256,
+		cryptolib.AlgorithmECP256Public:   AlgorithmES256,
+		cryptolib.AlgorithmECP384Private:  AlgorithmES384,
+		cryptolib.AlgorithmECP384Public:   AlgorithmES384,
+		cryptolib.AlgorithmEd25519Private: AlgorithmEdDSA,
+		cryptolib.AlgorithmEd25519Public:  AlgorithmEdDSA,
+		cryptolib.AlgorithmRSA2048Private: AlgorithmRS256,
+		cryptolib.AlgorithmRSA2048Public:  AlgorithmRS256,
+	}
+
+	for input, want := range tests {
+		t.Run(string(input), func(t *testing.T) {
+			got, err := getSigningMethod(input)
+			assert.HasErr(t, err, nil)
+			assert.Equal(t, got, want)
+			assert.Equal(t, got.Supports(input), true)
+		})
+	}
+
+	_, err := getSigningMethod(cryptolib.AlgorithmAES128)
+	assert.HasErr(t, err, ErrGetSigningMethod)
+}
+
+func TestAlgorithmSupports(t *testing.T) {
+	assert.Equal(t, AlgorithmPS256.Supports(cryptolib.AlgorithmRSA2048Public), true)
+	assert.Equal(t, AlgorithmRS512.Supports(cryptolib.AlgorithmRSA2048Private), true)
+	assert.Equal(t, AlgorithmES256.Supports(cryptolib.AlgorithmRSA2048Public), false)
+	assert.Equal(t, AlgorithmES384.Supports(cryptolib.AlgorithmECP256Public), false)
+	assert.Equal(t, AlgorithmRS256.Supports(cryptolib.AlgorithmEd25519Public), false)
+	assert.Equal(t, Algorithm("none").Supports(cryptolib.AlgorithmNone), false)
+}
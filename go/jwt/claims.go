@@ -2,8 +2,11 @@ package jwt
 
 import (
 	"encoding/json"
+	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // RegisteredClaims are default fields in a JWT.
@@ -23,6 +26,85 @@ type CustomClaims interface {
 	Valid() error
 }
 
+// SetRegisteredClaims sets the RegisteredClaims of claims.  IssuedAt and NotBefore are set to now, and a zero expiresAt never expires.
+func SetRegisteredClaims(claims CustomClaims, expiresAt time.Time, audience []string, id, issuer, subject string) { //nolint:revive
+	r := claims.GetRegisteredClaims()
+
+	var expires int64
+
+	if !expiresAt.IsZero() {
+		expires = expiresAt.Unix()
+	}
+
+	r.Audience = audience
+	r.ExpiresAt = expires
+	r.ID = id
+	r.IssuedAt = time.Now().Unix()
+	r.Issuer = issuer
+	r.NotBefore = time.Now().Unix()
+	r.Subject = subject
+}
+
+// ValidateClaims validates the RegisteredClaims of claims against the regexes and the current time, and then calls claims.Valid.  Empty regexes are not checked.
+func ValidateClaims(claims CustomClaims, audRegex string, jidRegex string, subRegex string) error {
+	r := claims.GetRegisteredClaims()
+
+	if audRegex != "" {
+		audReg, err := regexp.Compile(audRegex)
+		if err != nil {
+			return fmt.Errorf("%w: error compiling audience regex: %w", ErrTokenParsePayloadValidation, err)
+		}
+
+		match := false
+
+		for i := range r.Audience {
+			if audReg.MatchString(r.Audience[i]) {
+				match = true
+
+				break
+			}
+		}
+
+		if !match {
+			return fmt.Errorf("%w: no aud matches", ErrTokenParsePayloadValidation)
+		}
+	}
+
+	if jidRegex != "" {
+		jidReg, err := regexp.Compile(jidRegex)
+		if err != nil {
+			return fmt.Errorf("%w: error compiling id regex: %w", ErrTokenParsePayloadValidation, err)
+		}
+
+		if !jidReg.MatchString(r.ID) {
+			return fmt.Errorf("%w: no jid matches", ErrTokenParsePayloadValidation)
+		}
+	}
+
+	now := time.Now()
+
+	if time.Unix(r.NotBefore, 0).After(now) {
+		return fmt.Errorf("%w: token is not valid yet", ErrTokenParsePayloadValidation)
+	}
+
+	if r.ExpiresAt != 0 && now.After(time.Unix(r.ExpiresAt, 0)) {
+		return fmt.Errorf("%w: token has expired", ErrTokenParsePayloadValidation)
+	}
+
+	if subRegex != "" {
+		subReg, err := regexp.Compile(subRegex)
+		if err != nil {
+			return fmt.Errorf("%w: error compiling subject regex: %w", ErrTokenParsePayloadValidation, err)
+		}
+
+		if !subReg.MatchString(r.Subject) {
+			return fmt.Errorf("%w: no sub matches", ErrTokenParsePayloadValidation)
+		}
+	}
+
+	return claims.Valid()
+}
+
 // Audience is a string or array of strings.
 type Audience []string
 
@@ -0,0 +1,599 @@
+package jwt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/candiddev/shared/go/cryptolib"
+	"golang.org/x/crypto/curve25519"
+)
+
+var (
+	ErrJWEAlgorithm = errors.New("unsupported JWE algorithm")
+	ErrJWEDecrypt   = errors.New("error decrypting JWE")
+	ErrJWEEncrypt   = errors.New("error encrypting JWE")
+	ErrJWEFormat    = errors.New("jwe has invalid format")
+	ErrJWEKey       = errors.New("key can't be used with JWE algorithm")
+	ErrJWENoKeys    = errors.New("no keys can decrypt JWE")
+	ErrJWENotNested = errors.New("jwe does not contain a JWT")
+)
+
+// Algorithms supported for JWE key management.
+const (
+	AlgorithmDir          Algorithm = "dir"
+	AlgorithmECDHES       Algorithm = "ECDH-ES"
+	AlgorithmECDHESA128KW Algorithm = "ECDH-ES+A128KW"
+	AlgorithmRSAOAEP256   Algorithm = "RSA-OAEP-256"
+)
+
+// Encryption is how the JWE content will be encrypted.
+type Encryption string
+
+// Encryptions supported for JWE content.
+const (
+	EncryptionA128GCM Encryption = "A128GCM"
+	EncryptionA256GCM Encryption = "A256GCM"
+)
+
+// jweTagSize is the size of the authentication tag for AES GCM.
+const jweTagSize = 16
+
+func (e Encryption) keySize() int {
+	switch e {
+	case EncryptionA128GCM:
+		return 16
+	case EncryptionA256GCM:
+		return 32
+	}
+
+	return 0
+}
+
+// JWE is a decrypted JWE.
+type JWE struct {
+	Header    JWEHeader
+	Plaintext []byte
+}
+
+// JWEHeader is a JWE header.
+type JWEHeader struct {
+	Algorithm          Algorithm  `json:"alg"`
+	AgreementPartyU    string     `json:"apu,omitempty"`
+	AgreementPartyV    string     `json:"apv,omitempty"`
+	ContentType        string     `json:"cty,omitempty"`
+	Encryption         Encryption `json:"enc"`
+	EphemeralPublicKey *JWK       `json:"epk,omitempty"`
+	KeyID              string     `json:"kid,omitempty"`
+	Type               string     `json:"typ,omitempty"`
+}
+
+func getEncryptionMethod(k cryptolib.KeyProvider) (Algorithm, Encryption) {
+	switch k.(type) {
+	case cryptolib.AES128Key:
+		return AlgorithmDir, EncryptionA128GCM
+	case cryptolib.KeyProviderSymmetric:
+		return AlgorithmDir, EncryptionA256GCM
+	case cryptolib.RSA2048PublicKey:
+		return AlgorithmRSAOAEP256, EncryptionA256GCM
+	case cryptolib.RSA2048PrivateKey:
+		return AlgorithmRSAOAEP256, EncryptionA256GCM
+	}
+
+	return AlgorithmECDHES, EncryptionA256GCM
+}
+
+// Encrypt encrypts plaintext into a compact serialized JWE.  Public keys can be used with ECDH-ES and ECDH-ES+A128KW (ECP256, ECP384 and Ed25519 as X25519) or RSA-OAEP-256 (RSA2048), and symmetric keys with dir.  If a or e are empty, they will be selected from the key.
+func Encrypt[T cryptolib.KeyProvider](plaintext []byte, contentType string, k cryptolib.Key[T], a Algorithm, e Encryption) (string, error) {
+	da, de := getEncryptionMethod(k.Key)
+
+	if a == "" {
+		a = da
+	}
+
+	if e == "" {
+		e = de
+	}
+
+	size := e.keySize()
+	if size == 0 {
+		return "", fmt.Errorf("%w: %s", ErrJWEAlgorithm, e)
+	}
+
+	h := JWEHeader{
+		Algorithm:   a,
+		ContentType: contentType,
+		Encryption:  e,
+		KeyID:       k.ID,
+	}
+
+	var cek []byte
+
+	var ek []byte
+
+	var err error
+
+	switch a {
+	case AlgorithmDir:
+		cek, err = jweSymmetricKey(k.Key, size)
+	case AlgorithmECDHES:
+		fallthrough
+	case AlgorithmECDHESA128KW:
+		var z []byte
+
+		z, h.EphemeralPublicKey, err = jweECDHSet(k.Key)
+		if err != nil {
+			break
+		}
+
+		if a == AlgorithmECDHES {
+			cek = jweConcatKDF(z, string(e), nil, nil, size)
+
+			break
+		}
+
+		cek, err = jweRandom(size)
+		if err != nil {
+			break
+		}
+
+		ek, err = jweKeyWrap(jweConcatKDF(z, string(a), nil, nil, 16), cek)
+	case AlgorithmRSAOAEP256:
+		r, ok := any(k.Key).(cryptolib.RSA2048PublicKey)
+		if !ok {
+			err = fmt.Errorf("%w: %s can't be used with %s", ErrJWEKey, a, k.Key.Algorithm())
+
+			break
+		}
+
+		cek, err = jweRandom(size)
+		if err != nil {
+			break
+		}
+
+		ek, err = r.EncryptOAEPSHA256(cek)
+	default:
+		err = fmt.Errorf("%w: %s", ErrJWEAlgorithm, a)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	hb, err := json.Marshal(h)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrJWEEncrypt, err)
+	}
+
+	h64 := base64.RawURLEncoding.EncodeToString(hb)
+
+	g, err := jweGCM(cek)
+	if err != nil {
+		return "", err
+	}
+
+	iv, err := jweRandom(g.NonceSize())
+	if err != nil {
+		return "", err
+	}
+
+	c := g.Seal(nil, iv, plaintext, []byte(h64))
+	t := len(c) - g.Overhead()
+
+	return strings.Join([]string{
+		h64,
+		base64.RawURLEncoding.EncodeToString(ek),
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(c[:t]),
+		base64.RawURLEncoding.EncodeToString(c[t:]),
+	}, "."), nil
+}
+
+// EncryptToken encrypts a signed Token into a nested JWE.
+func EncryptToken[T cryptolib.KeyProvider](t *Token, k cryptolib.Key[T], a Algorithm, e Encryption) (string, error) {
+	return Encrypt([]byte(t.String()), "JWT", k, a, e)
+}
+
+// Decrypt decrypts a compact serialized JWE.  The key is selected using the header kid.  Private keys are used for ECDH-ES, ECDH-ES+A128KW and RSA-OAEP-256, and symmetric keys for dir.
+func Decrypt[T cryptolib.KeyProvider](token string, keys cryptolib.Keys[T]) (*JWE, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return nil, ErrJWEFormat
+	}
+
+	b := make([][]byte, 5)
+
+	for i := range parts {
+		var err error
+
+		b[i], err = base64.RawURLEncoding.DecodeString(parts[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrJWEFormat, err)
+		}
+	}
+
+	j := &JWE{}
+
+	if err := json.Unmarshal(b[0], &j.Header); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJWEFormat, err)
+	}
+
+	size := j.Header.Encryption.keySize()
+	if size == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrJWEAlgorithm, j.Header.Encryption)
+	}
+
+	if len(b[4]) != jweTagSize {
+		return nil, fmt.Errorf("%w: invalid authentication tag", ErrJWEFormat)
+	}
+
+	err := ErrJWENoKeys
+
+	for i := range keys {
+		if j.Header.KeyID != "" && keys[i].ID != j.Header.KeyID {
+			continue
+		}
+
+		var cek []byte
+
+		cek, err = j.Header.getCEK(keys[i].Key, b[1], size)
+		if err != nil {
+			continue
+		}
+
+		var g cipher.AEAD
+
+		g, err = jweGCM(cek)
+		if err != nil {
+			continue
+		}
+
+		if len(b[2]) != g.NonceSize() {
+			return nil, fmt.Errorf("%w: invalid iv", ErrJWEFormat)
+		}
+
+		j.Plaintext, err = g.Open(nil, b[2], append(b[3], b[4]...), []byte(parts[0]))
+		if err == nil {
+			return j, nil
+		}
+
+		err = fmt.Errorf("%w: %w", ErrJWEDecrypt, err)
+	}
+
+	return nil, err
+}
+
+// ParseEncrypted decrypts a nested JWE and parses the JWT it contains using a Verifier.
+func ParseEncrypted[T cryptolib.KeyProvider](ctx context.Context, token string, keys cryptolib.Keys[T], v *Verifier) (*Token, cryptolib.Key[cryptolib.KeyProviderPublic], error) {
+	var p cryptolib.Key[cryptolib.KeyProviderPublic]
+
+	j, err := Decrypt(token, keys)
+	if err != nil {
+		return nil, p, err
+	}
+
+	if !strings.EqualFold(j.Header.ContentType, "JWT") {
+		return nil, p, ErrJWENotNested
+	}
+
+	return v.Parse(ctx, string(j.Plaintext))
+}
+
+func (h *JWEHeader) getCEK(k cryptolib.KeyProvider, ek []byte, size int) ([]byte, error) {
+	switch h.Algorithm {
+	case AlgorithmDir:
+		if len(ek) != 0 {
+			return nil, fmt.Errorf("%w: encrypted key must be empty", ErrJWEFormat)
+		}
+
+		return jweSymmetricKey(k, size)
+	case AlgorithmECDHES:
+		fallthrough
+	case AlgorithmECDHESA128KW:
+		z, err := jweECDHGet(k, h.EphemeralPublicKey)
+		if err != nil {
+			return nil, err
+		}
+
+		u, err := base64.RawURLEncoding.DecodeString(h.AgreementPartyU)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrJWEFormat, err)
+		}
+
+		v, err := base64.RawURLEncoding.DecodeString(h.AgreementPartyV)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrJWEFormat, err)
+		}
+
+		if h.Algorithm == AlgorithmECDHES {
+			if len(ek) != 0 {
+				return nil, fmt.Errorf("%w: encrypted key must be empty", ErrJWEFormat)
+			}
+
+			return jweConcatKDF(z, string(h.Encryption), u, v, size), nil
+		}
+
+		cek, err := jweKeyUnwrap(jweConcatKDF(z, string(h.Algorithm), u, v, 16), ek)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(cek) != size {
+			return nil, fmt.Errorf("%w: invalid key length", ErrJWEDecrypt)
+		}
+
+		return cek, nil
+	case AlgorithmRSAOAEP256:
+		r, ok := k.(cryptolib.RSA2048PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s can't be used with %s", ErrJWEKey, h.Algorithm, k.Algorithm())
+		}
+
+		cek, err := r.DecryptOAEPSHA256(ek)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrJWEDecrypt, err)
+		}
+
+		if len(cek) != size {
+			return nil, fmt.Errorf("%w: invalid key length", ErrJWEDecrypt)
+		}
+
+		return cek, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrJWEAlgorithm, h.Algorithm)
+}
+
+func jweSymmetricKey(k cryptolib.KeyProvider, size int) ([]byte, error) {
+	var s string
+
+	switch t := k.(type) {
+	case cryptolib.AES128Key:
+		s = string(t)
+	case cryptolib.ChaCha20Key:
+		s = string(t)
+	default:
+		return nil, fmt.Errorf("%w: %s can't be used with %s", ErrJWEKey, AlgorithmDir, k.Algorithm())
+	}
+
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", cryptolib.ErrDecodingKey, err)
+	}
+
+	if len(b) != size {
+		return nil, fmt.Errorf("%w: key length doesn't match encryption", ErrJWEKey)
+	}
+
+	return b, nil
+}
+
+// jweECDHSet generates an ephemeral key and returns the shared secret and the ephemeral public key.
+func jweECDHSet(k cryptolib.KeyProvider) ([]byte, *JWK, error) {
+	s, ok := k.(cryptolib.KeyProviderKDFSet)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s can't be used with %s", ErrJWEKey, AlgorithmECDHES, k.Algorithm())
+	}
+
+	input, z, err := s.KDFSet()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrJWEEncrypt, err)
+	}
+
+	var epk JWK
+
+	switch s.KDF() { //nolint:exhaustive
+	case cryptolib.KDFECDHP256:
+		epk, err = NewJWK(cryptolib.Key[cryptolib.KeyProviderPublic]{
+			Key: cryptolib.ECP256PublicKey(input),
+		})
+	case cryptolib.KDFECDHP384:
+		epk, err = NewJWK(cryptolib.Key[cryptolib.KeyProviderPublic]{
+			Key: cryptolib.ECP384PublicKey(input),
+		})
+	case cryptolib.KDFECDHX25519:
+		var x []byte
+
+		x, err = cryptolib.Ed25519PublicKey(input).PublicKeyECDH()
+		epk = JWK{
+			Curve:   "X25519",
+			KeyType: "OKP",
+			X:       base64.RawURLEncoding.EncodeToString(x),
+		}
+	default:
+		err = fmt.Errorf("%w: %s can't be used with %s", ErrJWEKey, AlgorithmECDHES, k.Algorithm())
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return z, &JWK{
+		Curve:   epk.Curve,
+		KeyType: epk.KeyType,
+		X:       epk.X,
+		Y:       epk.Y,
+	}, nil
+}
+
+// jweECDHGet returns the shared secret for an ephemeral public key.
+func jweECDHGet(k cryptolib.KeyProvider, epk *JWK) ([]byte, error) {
+	if epk == nil {
+		return nil, fmt.Errorf("%w: missing epk", ErrJWEFormat)
+	}
+
+	switch t := k.(type) {
+	case cryptolib.Ed25519PrivateKey:
+		if epk.KeyType != "OKP" || epk.Curve != "X25519" {
+			return nil, fmt.Errorf("%w: epk doesn't match key", ErrJWEKey)
+		}
+
+		x, err := jwkDecode(epk.X, curve25519.PointSize)
+		if err != nil {
+			return nil, err
+		}
+
+		prv, err := t.PrivateKeyECDH()
+		if err != nil {
+			return nil, err
+		}
+
+		z, err := curve25519.X25519(prv, x)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrJWEDecrypt, err)
+		}
+
+		return z, nil
+	case cryptolib.KeyProviderKDFGet:
+		if (t.KDF() == cryptolib.KDFECDHP256 && epk.Curve != "P-256") || (t.KDF() == cryptolib.KDFECDHP384 && epk.Curve != "P-384") {
+			return nil, fmt.Errorf("%w: epk doesn't match key", ErrJWEKey)
+		}
+
+		p, err := epk.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+
+		z, err := t.KDFGet(fmt.Sprint(unwrapPublicKey(p.Key)), "")
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrJWEDecrypt, err)
+		}
+
+		return z, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s can't be used with %s", ErrJWEKey, AlgorithmECDHES, k.Algorithm())
+}
+
+// jweConcatKDF is the Concat KDF from NIST SP 800-56A using SHA-256, as described in RFC 7518 section 4.6.2.
+func jweConcatKDF(z []byte, algorithmID string, partyU, partyV []byte, size int) []byte {
+	info := []byte{}
+
+	for _, v := range [][]byte{
+		[]byte(algorithmID),
+		partyU,
+		partyV,
+	} {
+		info = binary.BigEndian.AppendUint32(info, uint32(len(v)))
+		info = append(info, v...)
+	}
+
+	info = binary.BigEndian.AppendUint32(info, uint32(size*8))
+
+	out := []byte{}
+
+	for counter := uint32(1); len(out) < size; counter++ {
+		h := sha256.New()
+		h.Write(binary.BigEndian.AppendUint32(nil, counter))
+		h.Write(z)
+		h.Write(info)
+		out = h.Sum(out)
+	}
+
+	return out[:size]
+}
+
+func jweGCM(cek []byte) (cipher.AEAD, error) {
+	c, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", cryptolib.ErrCreatingCipher, err)
+	}
+
+	g, err := cipher.NewGCM(c)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", cryptolib.ErrGeneratingGCM, err)
+	}
+
+	return g, nil
+}
+
+func jweRandom(size int) ([]byte, error) {
+	b := make([]byte, size)
+
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return nil, fmt.Errorf("%w: %w", cryptolib.ErrGeneratingKey, err)
+	}
+
+	return b, nil
+}
+
+var jweKeyWrapIV = []byte{0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6} //nolint:gochecknoglobals
+
+// jweKeyWrap wraps a key using AES Key Wrap from RFC 3394.
+func jweKeyWrap(kek, key []byte) ([]byte, error) {
+	if len(key)%8 != 0 || len(key) < 16 {
+		return nil, fmt.Errorf("%w: invalid key length", ErrJWEEncrypt)
+	}
+
+	c, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", cryptolib.ErrCreatingCipher, err)
+	}
+
+	n := len(key) / 8
+	out := make([]byte, len(key)+8)
+	copy(out, jweKeyWrapIV)
+	copy(out[8:], key)
+
+	b := make([]byte, 16)
+
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			copy(b, out[:8])
+			copy(b[8:], out[i*8:])
+			c.Encrypt(b, b)
+
+			t := binary.BigEndian.Uint64(b[:8]) ^ uint64(n*j+i)
+			binary.BigEndian.PutUint64(out[:8], t)
+			copy(out[i*8:], b[8:])
+		}
+	}
+
+	return out, nil
+}
+
+// jweKeyUnwrap unwraps a key using AES Key Wrap from RFC 3394.
+func jweKeyUnwrap(kek, wrapped []byte) ([]byte, error) {
+	if len(wrapped)%8 != 0 || len(wrapped) < 24 {
+		return nil, fmt.Errorf("%w: invalid wrapped key length", ErrJWEDecrypt)
+	}
+
+	c, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", cryptolib.ErrCreatingCipher, err)
+	}
+
+	n := len(wrapped)/8 - 1
+	out := make([]byte, len(wrapped))
+	copy(out, wrapped)
+
+	b := make([]byte, 16)
+
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			t := binary.BigEndian.Uint64(out[:8]) ^ uint64(n*j+i)
+			binary.BigEndian.PutUint64(b[:8], t)
+			copy(b[8:], out[i*8:i*8+8])
+			c.Decrypt(b, b)
+
+			copy(out[:8], b[:8])
+			copy(out[i*8:], b[8:])
+		}
+	}
+
+	if subtle.ConstantTimeCompare(out[:8], jweKeyWrapIV) != 1 {
+		return nil, fmt.Errorf("%w: key unwrap integrity check failed", ErrJWEDecrypt)
+	}
+
+	return out[8:], nil
+}
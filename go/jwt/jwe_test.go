@@ -0,0 +1,236 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/cryptolib"
+)
+
+func TestJWE(t *testing.T) {
+	aes128, _ := cryptolib.NewKeySymmetric(cryptolib.AlgorithmAES128)
+	chacha20, _ := cryptolib.NewKeySymmetric(cryptolib.AlgorithmChaCha20)
+	ecp256prv, ecp256pub, _ := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmECP256)
+	ecp384prv, ecp384pub, _ := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmECP384)
+	ed25519prv, ed25519pub, _ := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmEd25519)
+	rsa2048prv, rsa2048pub, _ := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmRSA2048)
+
+	plaintext := []byte(`{"household":"secret"}`)
+
+	asymmetric := map[string]struct {
+		algorithm  Algorithm
+		encryption Encryption
+		private    cryptolib.Key[cryptolib.KeyProviderPrivate]
+		public     cryptolib.Key[cryptolib.KeyProviderPublic]
+		wantErr    error
+	}{
+		"ecdh-es ecp256": {
+			algorithm:  AlgorithmECDHES,
+			encryption: EncryptionA128GCM,
+			private:    ecp256prv,
+			public:     ecp256pub,
+		},
+		"ecdh-es ecp384": {
+			private: ecp384prv,
+			public:  ecp384pub,
+		},
+		"ecdh-es x25519": {
+			private: ed25519prv,
+			public:  ed25519pub,
+		},
+		"ecdh-es+a128kw ecp256": {
+			algorithm: AlgorithmECDHESA128KW,
+			private:   ecp256prv,
+			public:    ecp256pub,
+		},
+		"ecdh-es+a128kw x25519": {
+			algorithm:  AlgorithmECDHESA128KW,
+			encryption: EncryptionA128GCM,
+			private:    ed25519prv,
+			public:     ed25519pub,
+		},
+		"rsa-oaep-256": {
+			private: rsa2048prv,
+			public:  rsa2048pub,
+		},
+		"rsa-oaep-256 ecp256": {
+			algorithm: AlgorithmRSAOAEP256,
+			public:    ecp256pub,
+			wantErr:   ErrJWEKey,
+		},
+		"dir ed25519": {
+			algorithm: AlgorithmDir,
+			public:    ed25519pub,
+			wantErr:   ErrJWEKey,
+		},
+		"unknown encryption": {
+			encryption: "A192CBC",
+			public:     ed25519pub,
+			wantErr:    ErrJWEAlgorithm,
+		},
+	}
+
+	for name, tc := range asymmetric {
+		t.Run(name, func(t *testing.T) {
+			s, err := Encrypt(plaintext, "", tc.public, tc.algorithm, tc.encryption)
+			assert.HasErr(t, err, tc.wantErr)
+
+			if tc.wantErr != nil {
+				return
+			}
+
+			assert.Equal(t, len(strings.Split(s, ".")), 5)
+
+			j, err := Decrypt(s, cryptolib.Keys[cryptolib.KeyProviderPrivate]{
+				ecp256prv,
+				ecp384prv,
+				ed25519prv,
+				rsa2048prv,
+			})
+			assert.HasErr(t, err, nil)
+			assert.Equal(t, j.Plaintext, plaintext)
+			assert.Equal(t, j.Header.KeyID, tc.public.ID)
+
+			// Wrong key
+			wrong, _, err := cryptolib.NewKeysAsymmetric(cryptolib.Algorithm(strings.TrimSuffix(string(tc.private.Key.Algorithm()), "private")))
+			assert.HasErr(t, err, nil)
+			wrong.ID = tc.private.ID
+			_, err = Decrypt(s, cryptolib.Keys[cryptolib.KeyProviderPrivate]{
+				wrong,
+			})
+			assert.Equal(t, err != nil, true)
+
+			// Tampered header
+			parts := strings.Split(s, ".")
+			parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"dir","enc":"A256GCM"}`))
+			_, err = Decrypt(strings.Join(parts, "."), cryptolib.Keys[cryptolib.KeyProviderPrivate]{
+				tc.private,
+			})
+			assert.Equal(t, err != nil, true)
+		})
+	}
+
+	symmetric := map[string]struct {
+		encryption Encryption
+		key        cryptolib.Key[cryptolib.KeyProviderSymmetric]
+		wantErr    error
+		wantEnc    Encryption
+	}{
+		"aes128": {
+			key:     aes128,
+			wantEnc: EncryptionA128GCM,
+		},
+		"chacha20": {
+			key:     chacha20,
+			wantEnc: EncryptionA256GCM,
+		},
+		"wrong size": {
+			encryption: EncryptionA256GCM,
+			key:        aes128,
+			wantErr:    ErrJWEKey,
+		},
+	}
+
+	for name, tc := range symmetric {
+		t.Run(name, func(t *testing.T) {
+			s, err := Encrypt(plaintext, "", tc.key, "", tc.encryption)
+			assert.HasErr(t, err, tc.wantErr)
+
+			if tc.wantErr != nil {
+				return
+			}
+
+			j, err := Decrypt(s, cryptolib.Keys[cryptolib.KeyProviderSymmetric]{
+				aes128,
+				chacha20,
+			})
+			assert.HasErr(t, err, nil)
+			assert.Equal(t, j.Plaintext, plaintext)
+			assert.Equal(t, j.Header.Algorithm, AlgorithmDir)
+			assert.Equal(t, j.Header.Encryption, tc.wantEnc)
+
+			// Truncated tag
+			parts := strings.Split(s, ".")
+			tag, _ := base64.RawURLEncoding.DecodeString(parts[4])
+			parts[4] = base64.RawURLEncoding.EncodeToString(tag[:12])
+			_, err = Decrypt(strings.Join(parts, "."), cryptolib.Keys[cryptolib.KeyProviderSymmetric]{
+				aes128,
+				chacha20,
+			})
+			assert.HasErr(t, err, ErrJWEFormat)
+		})
+	}
+
+	_, err := Decrypt("a.b.c", cryptolib.Keys[cryptolib.KeyProviderSymmetric]{})
+	assert.HasErr(t, err, ErrJWEFormat)
+}
+
+func TestParseEncrypted(t *testing.T) {
+	ctx := context.Background()
+	sprv, spub, _ := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmEd25519)
+	eprv, epub, _ := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmECP256)
+
+	j := jwtCustom{
+		Name: "household",
+	}
+
+	tok, _ := New(&j, time.Now().Add(time.Minute), nil, "", "", "")
+	assert.HasErr(t, tok.Sign(sprv), nil)
+
+	s, err := EncryptToken(tok, epub, AlgorithmECDHESA128KW, EncryptionA256GCM)
+	assert.HasErr(t, err, nil)
+
+	keys := cryptolib.Keys[cryptolib.KeyProviderPrivate]{
+		eprv,
+	}
+	v := &Verifier{
+		Keys: cryptolib.Keys[cryptolib.KeyProviderPublic]{
+			spub,
+		},
+	}
+
+	got, k, err := ParseEncrypted(ctx, s, keys, v)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, k, spub)
+
+	out := jwtCustom{}
+	assert.HasErr(t, got.ParsePayload(&out, "", "", ""), nil)
+	assert.Equal(t, out.Name, "household")
+
+	s, _ = Encrypt([]byte("hello"), "", epub, "", "")
+
+	_, _, err = ParseEncrypted(ctx, s, keys, v)
+	assert.HasErr(t, err, ErrJWENotNested)
+}
+
+func TestJWEConcatKDF(t *testing.T) {
+	// RFC 7518 Appendix C
+	z := []byte{158, 86, 217, 29, 129, 113, 53, 211, 114, 131, 66, 131, 191, 132, 38, 156, 251, 49, 110, 163, 218, 128, 106, 72, 246, 218, 167, 121, 140, 254, 144, 196}
+
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(jweConcatKDF(z, "A128GCM", []byte("Alice"), []byte("Bob"), 16)), "VqqN6vgjbSBcIijNcacQGg")
+}
+
+func TestJWEKeyWrap(t *testing.T) {
+	// RFC 3394 4.1
+	kek, _ := hex.DecodeString("000102030405060708090A0B0C0D0E0F")
+	key, _ := hex.DecodeString("00112233445566778899AABBCCDDEEFF")
+
+	w, err := jweKeyWrap(kek, key)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, strings.ToUpper(hex.EncodeToString(w)), "1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5")
+
+	u, err := jweKeyUnwrap(kek, w)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, u, key)
+
+	rand.Read(kek)
+
+	_, err = jweKeyUnwrap(kek, w)
+	assert.HasErr(t, err, ErrJWEDecrypt)
+}
@@ -0,0 +1,288 @@
+package jwt
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/candiddev/shared/go/cryptolib"
+)
+
+var (
+	ErrJWKInvalid     = errors.New("invalid JWK")
+	ErrJWKUnsupported = errors.New("unsupported JWK")
+)
+
+// JWK is a JSON Web Key.
+type JWK struct {
+	Algorithm Algorithm `json:"alg,omitempty"`
+	Curve     string    `json:"crv,omitempty"`
+	E         string    `json:"e,omitempty"`
+	KeyID     string    `json:"kid,omitempty"`
+	KeyType   string    `json:"kty"`
+	N         string    `json:"n,omitempty"`
+	Use       string    `json:"use,omitempty"`
+	X         string    `json:"x,omitempty"`
+	Y         string    `json:"y,omitempty"`
+}
+
+// JWKPublicKey is a public key from a JWK with an alg.  Verifiers only accept JWTs signed using Alg for it.
+type JWKPublicKey struct {
+	cryptolib.KeyProviderPublic
+
+	Alg Algorithm
+}
+
+// unwrapPublicKey returns the public key of a JWKPublicKey, or k.
+func unwrapPublicKey(k cryptolib.KeyProviderPublic) cryptolib.KeyProviderPublic {
+	if j, ok := k.(JWKPublicKey); ok {
+		return j.KeyProviderPublic
+	}
+
+	return k
+}
+
+// JWKSet is a JSON Web Key Set document.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK converts a public key to a JWK.  The alg is the default Algorithm of the key, or the Alg of a JWKPublicKey.
+func NewJWK(k cryptolib.Key[cryptolib.KeyProviderPublic]) (JWK, error) {
+	j := JWK{
+		KeyID: k.ID,
+		Use:   "sig",
+	}
+
+	a, err := getSigningMethod(k.Key.Algorithm())
+	if err != nil {
+		return j, fmt.Errorf("%w: %w", ErrJWKUnsupported, err)
+	}
+
+	j.Algorithm = a
+
+	if p, ok := k.Key.(JWKPublicKey); ok && p.Alg != "" {
+		j.Algorithm = p.Alg
+	}
+
+	switch t := unwrapPublicKey(k.Key).(type) {
+	case cryptolib.ECP256PublicKey:
+		p, err := t.PublicKey()
+		if err != nil {
+			return j, err
+		}
+
+		j.Curve = "P-256"
+		j.X, j.Y, err = jwkECPoint(p, 32)
+		j.KeyType = "EC"
+
+		return j, err
+	case cryptolib.ECP384PublicKey:
+		p, err := t.PublicKey()
+		if err != nil {
+			return j, err
+		}
+
+		j.Curve = "P-384"
+		j.X, j.Y, err = jwkECPoint(p, 48)
+		j.KeyType = "EC"
+
+		return j, err
+	case cryptolib.Ed25519PublicKey:
+		p, err := t.PublicKey()
+		if err != nil {
+			return j, err
+		}
+
+		j.Curve = "Ed25519"
+		j.KeyType = "OKP"
+		j.X = base64.RawURLEncoding.EncodeToString(p)
+
+		return j, nil
+	case cryptolib.RSA2048PublicKey:
+		p, err := t.PublicKey()
+		if err != nil {
+			return j, err
+		}
+
+		j.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.E)).Bytes())
+		j.KeyType = "RSA"
+		j.N = base64.RawURLEncoding.EncodeToString(p.N.Bytes())
+
+		return j, nil
+	}
+
+	return j, fmt.Errorf("%w: %s", ErrJWKUnsupported, k.Key.Algorithm())
+}
+
+func jwkECPoint(p *ecdsa.PublicKey, size int) (x, y string, err error) {
+	e, err := p.ECDH()
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %w", ErrJWKInvalid, err)
+	}
+
+	b := e.Bytes()
+
+	return base64.RawURLEncoding.EncodeToString(b[1 : size+1]), base64.RawURLEncoding.EncodeToString(b[size+1:]), nil
+}
+
+// PublicKey converts a JWK to a public key.  If the JWK has an alg, the key is a JWKPublicKey.  RSA keys must be 2048 bits.
+func (j JWK) PublicKey() (cryptolib.Key[cryptolib.KeyProviderPublic], error) {
+	k := cryptolib.Key[cryptolib.KeyProviderPublic]{
+		ID: j.KeyID,
+	}
+
+	switch j.KeyType {
+	case "EC":
+		var c ecdh.Curve
+
+		var size int
+
+		switch j.Curve {
+		case "P-256":
+			c = ecdh.P256()
+			size = 32
+		case "P-384":
+			c = ecdh.P384()
+			size = 48
+		default:
+			return k, fmt.Errorf("%w: curve %s", ErrJWKUnsupported, j.Curve)
+		}
+
+		x, err := jwkDecode(j.X, size)
+		if err != nil {
+			return k, err
+		}
+
+		y, err := jwkDecode(j.Y, size)
+		if err != nil {
+			return k, err
+		}
+
+		e, err := c.NewPublicKey(append(append([]byte{4}, x...), y...))
+		if err != nil {
+			return k, fmt.Errorf("%w: %w", ErrJWKInvalid, err)
+		}
+
+		b, err := x509.MarshalPKIXPublicKey(e)
+		if err != nil {
+			return k, fmt.Errorf("%w: %w", ErrJWKInvalid, err)
+		}
+
+		if size == 32 {
+			k.Key = cryptolib.ECP256PublicKey(base64.StdEncoding.EncodeToString(b))
+		} else {
+			k.Key = cryptolib.ECP384PublicKey(base64.StdEncoding.EncodeToString(b))
+		}
+	case "OKP":
+		if j.Curve != "Ed25519" {
+			return k, fmt.Errorf("%w: curve %s", ErrJWKUnsupported, j.Curve)
+		}
+
+		x, err := jwkDecode(j.X, ed25519.PublicKeySize)
+		if err != nil {
+			return k, err
+		}
+
+		b, err := x509.MarshalPKIXPublicKey(ed25519.PublicKey(x))
+		if err != nil {
+			return k, fmt.Errorf("%w: %w", ErrJWKInvalid, err)
+		}
+
+		k.Key = cryptolib.Ed25519PublicKey(base64.StdEncoding.EncodeToString(b))
+	case "RSA":
+		n, err := jwkDecode(j.N, 0)
+		if err != nil {
+			return k, err
+		}
+
+		e, err := jwkDecode(j.E, 0)
+		if err != nil {
+			return k, err
+		}
+
+		p := &rsa.PublicKey{
+			E: int(big.NewInt(0).SetBytes(e).Int64()),
+			N: big.NewInt(0).SetBytes(n),
+		}
+
+		if p.N.BitLen() != 2048 {
+			return k, fmt.Errorf("%w: RSA key size %d", ErrJWKUnsupported, p.N.BitLen())
+		}
+
+		b, err := x509.MarshalPKIXPublicKey(p)
+		if err != nil {
+			return k, fmt.Errorf("%w: %w", ErrJWKInvalid, err)
+		}
+
+		k.Key = cryptolib.RSA2048PublicKey(base64.StdEncoding.EncodeToString(b))
+	default:
+		return k, fmt.Errorf("%w: key type %s", ErrJWKUnsupported, j.KeyType)
+	}
+
+	if j.Algorithm != "" {
+		k.Key = JWKPublicKey{
+			Alg:               j.Algorithm,
+			KeyProviderPublic: k.Key,
+		}
+	}
+
+	return k, nil
+}
+
+func jwkDecode(s string, size int) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJWKInvalid, err)
+	}
+
+	if len(b) == 0 || (size > 0 && len(b) != size) {
+		return nil, fmt.Errorf("%w: value has invalid length", ErrJWKInvalid)
+	}
+
+	return b, nil
+}
+
+// NewJWKSet converts public keys to a JWKSet.
+func NewJWKSet(keys cryptolib.Keys[cryptolib.KeyProviderPublic]) (JWKSet, error) {
+	s := JWKSet{
+		Keys: []JWK{},
+	}
+
+	for i := range keys {
+		j, err := NewJWK(keys[i])
+		if err != nil {
+			return s, err
+		}
+
+		s.Keys = append(s.Keys, j)
+	}
+
+	return s, nil
+}
+
+// PublicKeys returns the signing keys in a JWKSet.  Keys that are not used for signatures or are not supported are skipped.
+func (j JWKSet) PublicKeys() cryptolib.Keys[cryptolib.KeyProviderPublic] {
+	keys := cryptolib.Keys[cryptolib.KeyProviderPublic]{}
+
+	for i := range j.Keys {
+		if j.Keys[i].Use != "" && j.Keys[i].Use != "sig" {
+			continue
+		}
+
+		k, err := j.Keys[i].PublicKey()
+		if err != nil {
+			continue
+		}
+
+		keys = append(keys, k)
+	}
+
+	return keys
+}
@@ -0,0 +1,97 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/cryptolib"
+)
+
+func TestJWK(t *testing.T) {
+	for _, a := range []cryptolib.Algorithm{
+		cryptolib.AlgorithmECP256,
+		cryptolib.AlgorithmECP384,
+		cryptolib.AlgorithmEd25519,
+		cryptolib.AlgorithmRSA2048,
+	} {
+		t.Run(string(a), func(t *testing.T) {
+			prv, pub, _ := cryptolib.NewKeysAsymmetric(a)
+
+			j, err := NewJWK(pub)
+			assert.HasErr(t, err, nil)
+			assert.Equal(t, j.KeyID, pub.ID)
+			assert.Equal(t, j.Use, "sig")
+
+			k, err := j.PublicKey()
+			assert.HasErr(t, err, nil)
+			assert.Equal(t, k.ID, pub.ID)
+			assert.Equal(t, k.Key.Algorithm(), pub.Key.Algorithm())
+			assert.Equal[cryptolib.KeyProviderPublic](t, k.Key, JWKPublicKey{
+				Alg:               j.Algorithm,
+				KeyProviderPublic: pub.Key,
+			})
+
+			tok, _ := New(&jwtCustom{}, time.Time{}, nil, "", "", "")
+			assert.HasErr(t, tok.Sign(prv), nil)
+
+			_, _, err = Parse(context.Background(), tok.String(), StaticKeys{
+				k,
+			})
+			assert.HasErr(t, err, nil)
+		})
+	}
+
+	_, err := JWK{
+		KeyType: "oct",
+	}.PublicKey()
+	assert.HasErr(t, err, ErrJWKUnsupported)
+
+	_, err = JWK{
+		Curve:   "P-256",
+		KeyType: "EC",
+		X:       "AAAA",
+		Y:       "AAAA",
+	}.PublicKey()
+	assert.HasErr(t, err, ErrJWKInvalid)
+
+	r, _ := rsa.GenerateKey(rand.Reader, 1024)
+
+	_, err = JWK{
+		E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(r.E)).Bytes()),
+		KeyType: "RSA",
+		N:       base64.RawURLEncoding.EncodeToString(r.N.Bytes()),
+	}.PublicKey()
+	assert.HasErr(t, err, ErrJWKUnsupported)
+}
+
+func TestJWKSetPublicKeys(t *testing.T) {
+	_, pub1, _ := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmEd25519)
+	_, pub2, _ := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmECP256)
+
+	s, err := NewJWKSet(cryptolib.Keys[cryptolib.KeyProviderPublic]{
+		pub1,
+		pub2,
+	})
+	assert.HasErr(t, err, nil)
+
+	s.Keys[1].Use = "enc"
+	s.Keys = append(s.Keys, JWK{
+		KeyType: "oct",
+	})
+
+	assert.Equal(t, s.PublicKeys(), cryptolib.Keys[cryptolib.KeyProviderPublic]{
+		{
+			ID: pub1.ID,
+			Key: JWKPublicKey{
+				Alg:               AlgorithmEdDSA,
+				KeyProviderPublic: pub1.Key,
+			},
+		},
+	})
+}
@@ -0,0 +1,177 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/candiddev/shared/go/cryptolib"
+	"github.com/candiddev/shared/go/get"
+)
+
+var (
+	ErrJWKSFetch = errors.New("error fetching JWKS")
+	ErrJWKSParse = errors.New("error parsing JWKS")
+)
+
+// JWKS is a KeySource backed by a remote JSON Web Key Set.  Keys are cached according to the Cache-Control and ETag headers of the response, and refreshed when a JWT has an unknown kid.
+type JWKS struct {
+	// DefaultMaxAge is how long keys are cached if the response has no Cache-Control max-age.  Defaults to 1 hour.
+	DefaultMaxAge time.Duration
+
+	// MinRefresh is the minimum time between requests to URL.  Defaults to 1 minute.
+	MinRefresh time.Duration
+
+	// URL is where the JWKS is retrieved from.  It is passed to get.FileHTTP, so headers can be added using a fragment.
+	URL string
+
+	cache      get.HTTPCache
+	err        error
+	expires    time.Time
+	keys       cryptolib.Keys[cryptolib.KeyProviderPublic]
+	mutex      sync.RWMutex
+	refreshed  time.Time
+	refreshing chan struct{}
+}
+
+func (j *JWKS) defaultMaxAge() time.Duration {
+	if j.DefaultMaxAge == 0 {
+		return time.Hour
+	}
+
+	return j.DefaultMaxAge
+}
+
+func (j *JWKS) minRefresh() time.Duration {
+	if j.MinRefresh == 0 {
+		return time.Minute
+	}
+
+	return j.MinRefresh
+}
+
+// PublicKeys returns the cached keys, refreshing them if they have expired or keyID is unknown.  Stale keys are returned if a refresh fails, or while another request is refreshing them.
+func (j *JWKS) PublicKeys(ctx context.Context, keyID string) (cryptolib.Keys[cryptolib.KeyProviderPublic], error) {
+	now := time.Now()
+
+	j.mutex.RLock()
+	keys := j.keys
+	stale := keys == nil || now.After(j.expires) || (keyID != "" && !j.hasKeyID(keyID) && now.Sub(j.refreshed) >= j.minRefresh())
+	j.mutex.RUnlock()
+
+	if !stale {
+		return keys, nil
+	}
+
+	return j.refresh(ctx, now)
+}
+
+func (j *JWKS) hasKeyID(keyID string) bool {
+	for i := range j.keys {
+		if j.keys[i].ID == keyID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// refresh fetches the keys unless they were fetched within MinRefresh.  Only one request fetches the keys at a time, the others return the stale keys, or wait for the fetch if there are none.
+func (j *JWKS) refresh(ctx context.Context, now time.Time) (cryptolib.Keys[cryptolib.KeyProviderPublic], error) {
+	j.mutex.Lock()
+
+	if j.refreshing != nil {
+		done := j.refreshing
+		keys := j.keys
+		j.mutex.Unlock()
+
+		if keys != nil {
+			return keys, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", ErrJWKSFetch, ctx.Err())
+		case <-done:
+		}
+
+		j.mutex.Lock()
+	}
+
+	if !j.refreshed.IsZero() && now.Sub(j.refreshed) < j.minRefresh() {
+		defer j.mutex.Unlock()
+
+		if j.keys == nil {
+			return nil, j.err
+		}
+
+		return j.keys, nil
+	}
+
+	j.refreshed = now
+
+	// Retry failed requests after MinRefresh.
+	j.expires = now.Add(j.minRefresh())
+
+	cache := j.cache
+	done := make(chan struct{})
+	j.refreshing = done
+	j.mutex.Unlock()
+
+	c, keys, err := j.fetch(ctx, cache)
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	close(done)
+	j.refreshing = nil
+	j.err = err
+
+	if err == nil {
+		if c.Modified {
+			j.keys = keys
+		}
+
+		j.cache = c
+
+		switch {
+		case c.NoCache:
+		case c.MaxAge > 0:
+			j.expires = now.Add(c.MaxAge)
+		default:
+			j.expires = now.Add(j.defaultMaxAge())
+		}
+	}
+
+	if j.keys == nil {
+		return nil, j.err
+	}
+
+	return j.keys, nil
+}
+
+// fetch gets the keys from URL without holding the mutex.  keys is only set if the JWKS was modified.
+func (j *JWKS) fetch(ctx context.Context, cache get.HTTPCache) (c get.HTTPCache, keys cryptolib.Keys[cryptolib.KeyProviderPublic], err error) {
+	b := bytes.Buffer{}
+
+	c, err = get.FileHTTP(ctx, j.URL, &b, cache)
+	if err != nil {
+		return c, nil, fmt.Errorf("%w: %w", ErrJWKSFetch, err)
+	}
+
+	if c.Modified {
+		s := JWKSet{}
+
+		if err := json.Unmarshal(b.Bytes(), &s); err != nil {
+			return c, nil, fmt.Errorf("%w: %w", ErrJWKSParse, err)
+		}
+
+		keys = s.PublicKeys()
+	}
+
+	return c, keys, nil
+}
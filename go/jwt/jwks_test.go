@@ -0,0 +1,155 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/cryptolib"
+	"github.com/candiddev/shared/go/get"
+)
+
+func TestJWKS(t *testing.T) {
+	ctx := context.Background()
+
+	prv1, pub1, _ := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmEd25519)
+	prv2, pub2, _ := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmRSA2048)
+
+	s, _ := NewJWKSet(cryptolib.Keys[cryptolib.KeyProviderPublic]{
+		pub1,
+	})
+	b, _ := json.Marshal(s)
+
+	h := get.NewHTTPMock([]string{"/jwks.json"}, b, time.Time{})
+	h.SetCacheControl("max-age=60")
+	h.SetETag(`"1"`)
+
+	j := &JWKS{
+		URL: h.URL() + "/jwks.json",
+	}
+	v := Verifier{
+		KeySource: j,
+	}
+
+	tok1, _ := New(&jwtCustom{}, time.Time{}, nil, "", "", "")
+	tok1.Sign(prv1)
+
+	tok2, _ := New(&jwtCustom{}, time.Time{}, nil, "", "", "")
+	tok2.Sign(prv2)
+
+	// Initial fetch
+	_, k, err := v.Parse(ctx, tok1.String())
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, k, pub1)
+	assert.Equal(t, len(h.Requests()), 1)
+
+	// Cached
+	_, _, err = v.Parse(ctx, tok1.String())
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, len(h.Requests()), 0)
+
+	// Unknown kid is rate limited
+	_, _, err = v.Parse(ctx, tok2.String())
+	assert.HasErr(t, err, ErrParseKeyID)
+	assert.Equal(t, len(h.Requests()), 0)
+
+	// Unknown kid refreshes after MinRefresh
+	s, _ = NewJWKSet(cryptolib.Keys[cryptolib.KeyProviderPublic]{
+		pub1,
+		pub2,
+	})
+	b, _ = json.Marshal(s)
+	h.SetBody(b)
+	h.SetETag(`"2"`)
+
+	j.refreshed = j.refreshed.Add(-2 * time.Minute)
+
+	_, k, err = v.Parse(ctx, tok2.String())
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, k, pub2)
+
+	r := h.Requests()
+	assert.Equal(t, len(r), 1)
+	assert.Equal(t, r[0].Headers.Get("If-None-Match"), `"1"`)
+	assert.Equal(t, r[0].Status, http.StatusOK)
+
+	// Expired keys are revalidated using the ETag
+	j.expires = time.Now().Add(-1 * time.Second)
+	j.refreshed = j.refreshed.Add(-2 * time.Minute)
+
+	_, _, err = v.Parse(ctx, tok1.String())
+	assert.HasErr(t, err, nil)
+
+	r = h.Requests()
+	assert.Equal(t, len(r), 1)
+	assert.Equal(t, r[0].Status, http.StatusNotModified)
+	assert.Equal(t, j.expires.After(time.Now().Add(59*time.Second)), true)
+
+	// Stale keys are used while another request refreshes them
+	j.expires = time.Now().Add(-1 * time.Second)
+	j.refreshed = j.refreshed.Add(-2 * time.Minute)
+	j.refreshing = make(chan struct{})
+
+	_, k, err = v.Parse(ctx, tok1.String())
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, k, pub1)
+	assert.Equal(t, len(h.Requests()), 0)
+
+	j.refreshing = nil
+
+	// Stale keys are used if the server is unavailable
+	h.Close()
+
+	j.expires = time.Now().Add(-1 * time.Second)
+	j.refreshed = j.refreshed.Add(-2 * time.Minute)
+
+	_, _, err = v.Parse(ctx, tok1.String())
+	assert.HasErr(t, err, nil)
+	assert.HasErr(t, j.err, ErrJWKSFetch)
+
+	// Errors are returned without keys
+	j = &JWKS{
+		URL: h.URL() + "/jwks.json",
+	}
+	v.KeySource = j
+
+	_, _, err = v.Parse(ctx, tok1.String())
+	assert.HasErr(t, err, ErrJWKSFetch)
+
+	// Requests without keys wait for another request to refresh them
+	j = &JWKS{
+		refreshing: make(chan struct{}),
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+
+		j.mutex.Lock()
+		j.keys = cryptolib.Keys[cryptolib.KeyProviderPublic]{
+			pub1,
+		}
+		j.refreshed = time.Now()
+		close(j.refreshing)
+		j.refreshing = nil
+		j.mutex.Unlock()
+	}()
+
+	keys, err := j.PublicKeys(ctx, "")
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, keys, cryptolib.Keys[cryptolib.KeyProviderPublic]{
+		pub1,
+	})
+
+	j = &JWKS{
+		refreshing: make(chan struct{}),
+	}
+
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	_, err = j.PublicKeys(cctx, "")
+	assert.HasErr(t, err, ErrJWKSFetch)
+}
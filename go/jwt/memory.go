@@ -0,0 +1,106 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryRefreshFamily struct {
+	expires time.Time
+	revoked bool
+	tokenID string
+}
+
+// MemoryStore is an in-memory RevocationStore and RefreshStore.  It is only suitable for a single instance.
+type MemoryStore struct {
+	families map[string]memoryRefreshFamily
+	mutex    sync.Mutex
+	revoked  map[string]time.Time
+}
+
+// NewMemoryStore creates a new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		families: map[string]memoryRefreshFamily{},
+		revoked:  map[string]time.Time{},
+	}
+}
+
+func (m *MemoryStore) CreateRefreshFamily(_ context.Context, familyID, tokenID string, expiresAt time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.families[familyID] = memoryRefreshFamily{
+		expires: expiresAt,
+		tokenID: tokenID,
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) IsJWTRevoked(_ context.Context, id string) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	e, ok := m.revoked[id]
+
+	return ok && (e.IsZero() || e.After(time.Now())), nil
+}
+
+// Purge removes expired revocations and refresh token families.
+func (m *MemoryStore) Purge() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+
+	for k, v := range m.families {
+		if !v.expires.IsZero() && v.expires.Before(now) {
+			delete(m.families, k)
+		}
+	}
+
+	for k, v := range m.revoked {
+		if !v.IsZero() && v.Before(now) {
+			delete(m.revoked, k)
+		}
+	}
+}
+
+func (m *MemoryStore) RevokeJWT(_ context.Context, id string, expiresAt time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.revoked[id] = expiresAt
+
+	return nil
+}
+
+func (m *MemoryStore) RevokeRefreshFamily(_ context.Context, familyID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if f, ok := m.families[familyID]; ok {
+		f.revoked = true
+		m.families[familyID] = f
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) RotateRefreshFamily(_ context.Context, familyID, tokenID, nextTokenID string, expiresAt time.Time) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	f, ok := m.families[familyID]
+	if !ok || f.revoked || f.tokenID != tokenID || (!f.expires.IsZero() && f.expires.Before(time.Now())) {
+		return false, nil
+	}
+
+	f.expires = expiresAt
+	f.tokenID = nextTokenID
+	m.families[familyID] = f
+
+	return true, nil
+}
@@ -0,0 +1,51 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStore()
+
+	assert.HasErr(t, m.RevokeJWT(ctx, "expired", time.Now().Add(-1*time.Minute)), nil)
+	assert.HasErr(t, m.RevokeJWT(ctx, "forever", time.Time{}), nil)
+	assert.HasErr(t, m.RevokeJWT(ctx, "valid", time.Now().Add(time.Minute)), nil)
+
+	for id, want := range map[string]bool{
+		"expired": false,
+		"forever": true,
+		"missing": false,
+		"valid":   true,
+	} {
+		got, err := m.IsJWTRevoked(ctx, id)
+		assert.HasErr(t, err, nil)
+		assert.Equal(t, got, want)
+	}
+
+	assert.HasErr(t, m.CreateRefreshFamily(ctx, "expired", "1", time.Now().Add(-1*time.Minute)), nil)
+	assert.HasErr(t, m.CreateRefreshFamily(ctx, "family", "1", time.Now().Add(time.Minute)), nil)
+
+	ok, err := m.RotateRefreshFamily(ctx, "expired", "1", "2", time.Now().Add(time.Minute))
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, ok, false)
+
+	ok, _ = m.RotateRefreshFamily(ctx, "family", "1", "2", time.Now().Add(time.Minute))
+	assert.Equal(t, ok, true)
+
+	ok, _ = m.RotateRefreshFamily(ctx, "family", "1", "3", time.Now().Add(time.Minute))
+	assert.Equal(t, ok, false)
+
+	assert.HasErr(t, m.RevokeRefreshFamily(ctx, "family"), nil)
+
+	ok, _ = m.RotateRefreshFamily(ctx, "family", "2", "3", time.Now().Add(time.Minute))
+	assert.Equal(t, ok, false)
+
+	m.Purge()
+	assert.Equal(t, len(m.families), 1)
+	assert.Equal(t, len(m.revoked), 2)
+}
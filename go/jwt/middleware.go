@@ -0,0 +1,179 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/candiddev/shared/go/errs"
+	"github.com/candiddev/shared/go/logger"
+)
+
+var (
+	ErrMiddlewareConfig        = errors.New("middleware requires a Parser and NewClaims")
+	ErrMiddlewareInvalidHeader = errors.New("invalid token: authorization header must be a bearer token")
+	ErrMiddlewareNoToken       = errors.New("no bearer token provided")
+)
+
+type ctxKey string
+
+const ctxKeyClaims ctxKey = "claims"
+
+// Middleware is an http.Handler middleware that authenticates requests using a bearer JWT.
+type Middleware[T CustomClaims] struct {
+	// AudienceRegex, IDRegex, and SubjectRegex are passed to ClaimsParser.ParseClaims.
+	AudienceRegex string
+
+	// Cookie is the name of a cookie to read the token from if the Authorization header is missing.
+	Cookie string
+
+	// ErrorHandler writes the response for requests that fail authentication, or errs.ErrReceiver if the Middleware is misconfigured.  Defaults to writing the error message and status.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err errs.Err)
+
+	IDRegex string
+
+	// NewClaims returns the CustomClaims to parse the payload into, like func() *MyClaims { return &MyClaims{} }.
+	NewClaims func() T
+
+	// Optional allows requests without a token to continue without claims.  Requests with an invalid token are still rejected.
+	Optional bool
+
+	// Parser verifies tokens, like a Verifier.
+	Parser ClaimsParser
+
+	// QueryParameter is the name of a query parameter to read the token from if the Authorization header and Cookie are missing.
+	QueryParameter string
+
+	// Realm is added to the WWW-Authenticate header.
+	Realm string
+
+	// RevocationStore is used to reject revoked tokens if set.
+	RevocationStore RevocationStore
+
+	SubjectRegex string
+}
+
+// ClaimsFromContext returns the claims added to the context by a Middleware.
+func ClaimsFromContext[T CustomClaims](ctx context.Context) (T, bool) {
+	c, ok := ctx.Value(ctxKeyClaims).(T)
+
+	return c, ok
+}
+
+// Handler wraps next, validating the bearer token of each request and adding the claims to the request context.  Requests fail with errs.ErrReceiver if Parser or NewClaims are nil.
+func (m *Middleware[T]) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if m.Parser == nil || m.NewClaims == nil {
+			m.write(w, r, logger.Error(ctx, errs.ErrReceiver.Wrap(ErrMiddlewareConfig)))
+
+			return
+		}
+
+		token, err := m.token(r)
+		if err != nil {
+			m.error(w, r, "The token is invalid", logger.Error(ctx, errs.ErrSenderUnauthorized.Wrap(err)))
+
+			return
+		}
+
+		if token == "" {
+			if m.Optional {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			m.error(w, r, "", logger.Error(ctx, errs.ErrSenderUnauthorized.Wrap(ErrMiddlewareNoToken)))
+
+			return
+		}
+
+		c, err := m.parse(ctx, token)
+		if err != nil {
+			m.error(w, r, "The token is invalid", logger.Error(ctx, errs.ErrSenderUnauthorized.Wrap(err)))
+
+			return
+		}
+
+		ctx = context.WithValue(ctx, ctxKeyClaims, c)
+		ctx = logger.SetAttribute(ctx, "subject", c.GetRegisteredClaims().Subject)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// error writes the WWW-Authenticate header from RFC 6750.  The description is not the underlying error, to avoid leaking validation details.
+func (m *Middleware[T]) error(w http.ResponseWriter, r *http.Request, description string, err errs.Err) {
+	h := "Bearer"
+	p := []string{}
+
+	if m.Realm != "" {
+		p = append(p, fmt.Sprintf("realm=%q", m.Realm))
+	}
+
+	if description != "" {
+		p = append(p, `error="invalid_token"`, fmt.Sprintf("error_description=%q", description))
+	}
+
+	if len(p) > 0 {
+		h += " " + strings.Join(p, ", ")
+	}
+
+	w.Header().Set("WWW-Authenticate", h)
+
+	m.write(w, r, err)
+}
+
+// write writes the response for err using the ErrorHandler.
+func (m *Middleware[T]) write(w http.ResponseWriter, r *http.Request, err errs.Err) {
+	if m.ErrorHandler != nil {
+		m.ErrorHandler(w, r, err)
+
+		return
+	}
+
+	http.Error(w, err.Message(), err.Status())
+}
+
+func (m *Middleware[T]) parse(ctx context.Context, token string) (T, error) {
+	c := m.NewClaims()
+
+	if err := m.Parser.ParseClaims(ctx, token, c, m.AudienceRegex, m.IDRegex, m.SubjectRegex); err != nil {
+		return c, err
+	}
+
+	if m.RevocationStore != nil {
+		if err := CheckRevoked(ctx, m.RevocationStore, c); err != nil {
+			return c, err
+		}
+	}
+
+	return c, nil
+}
+
+// token returns the token from the request, or an error if the Authorization header is not a bearer token.
+func (m *Middleware[T]) token(r *http.Request) (string, error) {
+	if a := r.Header.Get("Authorization"); a != "" {
+		if s, t, ok := strings.Cut(a, " "); ok && strings.EqualFold(s, "Bearer") && strings.TrimSpace(t) != "" {
+			return strings.TrimSpace(t), nil
+		}
+
+		return "", ErrMiddlewareInvalidHeader
+	}
+
+	if m.Cookie != "" {
+		if c, err := r.Cookie(m.Cookie); err == nil && c.Value != "" {
+			return c.Value, nil
+		}
+	}
+
+	if m.QueryParameter != "" {
+		return r.URL.Query().Get(m.QueryParameter), nil
+	}
+
+	return "", nil
+}
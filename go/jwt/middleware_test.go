@@ -0,0 +1,202 @@
+package jwt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/cryptolib"
+	"github.com/candiddev/shared/go/logger"
+)
+
+func TestMiddleware(t *testing.T) {
+	logger.UseTestLogger(t)
+
+	prv, pub, _ := cryptolib.NewEd25519()
+	k := cryptolib.Key[cryptolib.KeyProviderPrivate]{
+		Key: prv,
+	}
+
+	newToken := func(e time.Time, id, subject string) string {
+		tok, _ := New(&jwtCustom{
+			Name: "name",
+		}, e, []string{"audience"}, id, "issuer", subject)
+		tok.Sign(k)
+
+		return tok.String()
+	}
+
+	good := newToken(time.Now().Add(time.Minute), "1", "subject")
+	expired := newToken(time.Now().Add(-1*time.Minute), "2", "subject")
+	revoked := newToken(time.Now().Add(time.Minute), "3", "subject")
+	wrongSubject := newToken(time.Now().Add(time.Minute), "4", "other")
+
+	s := NewMemoryStore()
+	s.RevokeJWT(context.Background(), "3", time.Time{})
+
+	m := Middleware[*jwtCustom]{
+		Cookie: "token",
+		NewClaims: func() *jwtCustom {
+			return &jwtCustom{}
+		},
+		QueryParameter:  "token",
+		Realm:           "example",
+		RevocationStore: s,
+		SubjectRegex:    "^subject$",
+		Parser: &Verifier{
+			Keys: cryptolib.Keys[cryptolib.KeyProviderPublic]{
+				{
+					Key: pub,
+				},
+			},
+		},
+	}
+
+	var gotName, gotSubject string
+
+	h := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, ok := ClaimsFromContext[*jwtCustom](r.Context())
+		if ok {
+			gotName = c.Name
+		}
+
+		gotSubject = logger.GetAttribute(r.Context(), "subject")
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := map[string]struct {
+		cookie      string
+		header      string
+		optional    bool
+		query       string
+		wantAuth    string
+		wantName    string
+		wantStatus  int
+		wantSubject string
+	}{
+		"header": {
+			header:      "Bearer " + good,
+			wantName:    "name",
+			wantStatus:  http.StatusOK,
+			wantSubject: "subject",
+		},
+		"header lowercase": {
+			header:      "bearer " + good,
+			wantName:    "name",
+			wantStatus:  http.StatusOK,
+			wantSubject: "subject",
+		},
+		"cookie": {
+			cookie:      good,
+			wantName:    "name",
+			wantStatus:  http.StatusOK,
+			wantSubject: "subject",
+		},
+		"query": {
+			query:       good,
+			wantName:    "name",
+			wantStatus:  http.StatusOK,
+			wantSubject: "subject",
+		},
+		"missing": {
+			wantAuth:   `Bearer realm="example"`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		"missing optional": {
+			optional:   true,
+			wantStatus: http.StatusOK,
+		},
+		"basic": {
+			header:     "Basic " + good,
+			wantAuth:   `Bearer realm="example", error="invalid_token", error_description="The token is invalid"`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		"basic optional": {
+			header:     "Basic " + good,
+			optional:   true,
+			wantAuth:   `Bearer realm="example", error="invalid_token", error_description="The token is invalid"`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		"bearer without token": {
+			header:     "Bearer",
+			wantAuth:   `Bearer realm="example", error="invalid_token", error_description="The token is invalid"`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		"bearer empty token": {
+			header:     "Bearer  ",
+			wantAuth:   `Bearer realm="example", error="invalid_token", error_description="The token is invalid"`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		"expired": {
+			header:     "Bearer " + expired,
+			wantAuth:   `Bearer realm="example", error="invalid_token", error_description="The token is invalid"`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		"invalid optional": {
+			header:     "Bearer " + good + "a",
+			optional:   true,
+			wantAuth:   `Bearer realm="example", error="invalid_token", error_description="The token is invalid"`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		"revoked": {
+			header:     "Bearer " + revoked,
+			wantAuth:   `Bearer realm="example", error="invalid_token", error_description="The token is invalid"`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		"wrong subject": {
+			header:     "Bearer " + wrongSubject,
+			wantAuth:   `Bearer realm="example", error="invalid_token", error_description="The token is invalid"`,
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotName = ""
+			gotSubject = ""
+			m.Optional = tc.optional
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			if tc.cookie != "" {
+				r.AddCookie(&http.Cookie{
+					Name:  "token",
+					Value: tc.cookie,
+				})
+			}
+
+			if tc.header != "" {
+				r.Header.Set("Authorization", tc.header)
+			}
+
+			if tc.query != "" {
+				r.URL.RawQuery = "token=" + tc.query
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, w.Code, tc.wantStatus)
+			assert.Equal(t, w.Header().Get("WWW-Authenticate"), tc.wantAuth)
+			assert.Equal(t, gotName, tc.wantName)
+			assert.Equal(t, gotSubject, tc.wantSubject)
+		})
+	}
+
+	// Missing Parser is a server error.
+	m.Optional = false
+	m.Parser = nil
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+good)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	assert.Equal(t, w.Code, http.StatusInternalServerError)
+	assert.Equal(t, w.Header().Get("WWW-Authenticate"), "")
+}
@@ -0,0 +1,92 @@
+package jwt
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshStore stores refresh token families.  Each family tracks the ID of the only refresh token that can be used to get the next one.
+type RefreshStore interface {
+	// CreateRefreshFamily creates a new refresh token family with the current token ID.
+	CreateRefreshFamily(ctx context.Context, familyID, tokenID string, expiresAt time.Time) error
+
+	// RevokeRefreshFamily revokes a refresh token family, so none of its tokens can be rotated.
+	RevokeRefreshFamily(ctx context.Context, familyID string) error
+
+	// RotateRefreshFamily atomically replaces the current token ID of a family with nextTokenID.  Returns false if the family is revoked, expired, unknown, or tokenID isn't the current token.
+	RotateRefreshFamily(ctx context.Context, familyID, tokenID, nextTokenID string, expiresAt time.Time) (bool, error)
+}
+
+// RefreshClaims are the claims of a refresh token.
+type RefreshClaims struct {
+	FamilyID string `json:"fam"`
+	RegisteredClaims
+}
+
+func (r *RefreshClaims) GetRegisteredClaims() *RegisteredClaims {
+	return &r.RegisteredClaims
+}
+
+func (r *RefreshClaims) Valid() error {
+	if r.FamilyID == "" || r.ID == "" {
+		return ErrRefreshTokenInvalid
+	}
+
+	return nil
+}
+
+// NewRefresh creates a refresh Token for a new family.  The Token still needs to be signed.
+func NewRefresh(ctx context.Context, s RefreshStore, expiresAt time.Time, audience []string, issuer, subject string) (*Token, error) {
+	c := RefreshClaims{
+		FamilyID: uuid.NewString(),
+	}
+
+	t, err := New(&c, expiresAt, audience, uuid.NewString(), issuer, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.CreateRefreshFamily(ctx, c.FamilyID, c.ID, expiresAt); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// RotateRefresh uses validated refresh claims to create the next refresh Token in the family.  If claims are not for the current token, they have been reused, and the entire family is revoked.  The Token still needs to be signed.
+func RotateRefresh(ctx context.Context, s RefreshStore, claims *RefreshClaims, expiresAt time.Time) (*Token, error) {
+	if err := claims.Valid(); err != nil {
+		return nil, err
+	}
+
+	c := RefreshClaims{
+		FamilyID: claims.FamilyID,
+	}
+
+	t, err := New(&c, expiresAt, claims.Audience, uuid.NewString(), claims.Issuer, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.RotateRefreshFamily(ctx, claims.FamilyID, claims.ID, c.ID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		if err := s.RevokeRefreshFamily(ctx, claims.FamilyID); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	return t, nil
+}
+
+// RevokeRefresh revokes the family of refresh claims, like during a logout.
+func RevokeRefresh(ctx context.Context, s RefreshStore, claims *RefreshClaims) error {
+	return s.RevokeRefreshFamily(ctx, claims.FamilyID)
+}
@@ -0,0 +1,74 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/cryptolib"
+)
+
+func TestRefresh(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStore()
+	e := time.Now().Add(time.Hour)
+
+	prv, pub, _ := cryptolib.NewEd25519()
+	v := Verifier{
+		Keys: cryptolib.Keys[cryptolib.KeyProviderPublic]{
+			{
+				Key: pub,
+			},
+		},
+	}
+
+	parse := func(tok *Token) *RefreshClaims {
+		t.Helper()
+
+		assert.HasErr(t, tok.Sign(cryptolib.Key[cryptolib.KeyProviderPrivate]{
+			Key: prv,
+		}), nil)
+
+		p, _, err := v.Parse(ctx, tok.String())
+		assert.HasErr(t, err, nil)
+
+		c := RefreshClaims{}
+		assert.HasErr(t, p.ParsePayload(&c, "", "", ""), nil)
+
+		return &c
+	}
+
+	tok, err := NewRefresh(ctx, m, e, []string{"audience"}, "issuer", "subject")
+	assert.HasErr(t, err, nil)
+
+	c1 := parse(tok)
+	assert.Equal(t, c1.FamilyID != "", true)
+	assert.Equal(t, c1.Subject, "subject")
+
+	tok, err = RotateRefresh(ctx, m, c1, e)
+	assert.HasErr(t, err, nil)
+
+	c2 := parse(tok)
+	assert.Equal(t, c2.FamilyID, c1.FamilyID)
+	assert.Equal(t, c2.ID != c1.ID, true)
+	assert.Equal(t, c2.Audience, c1.Audience)
+
+	// Reusing c1 revokes the family, including c2.
+	_, err = RotateRefresh(ctx, m, c1, e)
+	assert.HasErr(t, err, ErrRefreshTokenInvalid)
+
+	_, err = RotateRefresh(ctx, m, c2, e)
+	assert.HasErr(t, err, ErrRefreshTokenInvalid)
+
+	tok, _ = NewRefresh(ctx, m, e, nil, "", "")
+	c3 := parse(tok)
+
+	assert.HasErr(t, RevokeRefresh(ctx, m, c3), nil)
+
+	_, err = RotateRefresh(ctx, m, c3, e)
+	assert.HasErr(t, err, ErrRefreshTokenInvalid)
+
+	_, err = RotateRefresh(ctx, m, &RefreshClaims{}, e)
+	assert.HasErr(t, err, ErrRefreshTokenInvalid)
+}
@@ -0,0 +1,57 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token has been reused or revoked")
+	ErrTokenRevoked        = errors.New("token has been revoked")
+)
+
+// RevocationStore stores revoked JWT IDs until they expire.
+type RevocationStore interface {
+	// IsJWTRevoked returns whether a JWT ID has been revoked.
+	IsJWTRevoked(ctx context.Context, id string) (bool, error)
+
+	// RevokeJWT revokes a JWT ID until expiresAt.  A zero expiresAt never expires.
+	RevokeJWT(ctx context.Context, id string, expiresAt time.Time) error
+}
+
+// CheckRevoked returns ErrTokenRevoked if the ID of claims has been revoked.
+func CheckRevoked(ctx context.Context, s RevocationStore, claims CustomClaims) error {
+	r := claims.GetRegisteredClaims()
+	if r.ID == "" {
+		return nil
+	}
+
+	revoked, err := s.IsJWTRevoked(ctx, r.ID)
+	if err != nil {
+		return err
+	}
+
+	if revoked {
+		return fmt.Errorf("%w: %s", ErrTokenRevoked, r.ID)
+	}
+
+	return nil
+}
+
+// Revoke revokes the ID of claims until the claims expire.
+func Revoke(ctx context.Context, s RevocationStore, claims CustomClaims) error {
+	r := claims.GetRegisteredClaims()
+	if r.ID == "" {
+		return fmt.Errorf("%w: token has no jti", ErrTokenParsePayloadValidation)
+	}
+
+	var e time.Time
+
+	if r.ExpiresAt != 0 {
+		e = time.Unix(r.ExpiresAt, 0)
+	}
+
+	return s.RevokeJWT(ctx, r.ID, e)
+}
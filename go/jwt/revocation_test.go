@@ -0,0 +1,23 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+)
+
+func TestRevocation(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStore()
+
+	c := jwtCustom{}
+	New(&c, time.Now().Add(time.Minute), nil, "id", "", "")
+
+	assert.HasErr(t, CheckRevoked(ctx, m, &c), nil)
+	assert.HasErr(t, Revoke(ctx, m, &c), nil)
+	assert.HasErr(t, CheckRevoked(ctx, m, &c), ErrTokenRevoked)
+	assert.HasErr(t, Revoke(ctx, m, &jwtCustom{}), ErrTokenParsePayloadValidation)
+	assert.HasErr(t, CheckRevoked(ctx, m, &jwtCustom{}), nil)
+}
@@ -1,13 +1,11 @@
 package jwt
 
 import (
-	"crypto"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"regexp"
-	"strings"
 	"time"
 
 	"github.com/candiddev/shared/go/cryptolib"
@@ -17,6 +15,7 @@ var (
 	ErrGetSigningMethod            = errors.New("unknown crypto signer")
 	ErrNewMarshalJSON              = errors.New("error converting claims to JSON")
 	ErrParseFormat                 = errors.New("jwt has invalid format")
+	ErrParseKeyID                  = errors.New("no public key matches the JWT key ID")
 	ErrParseNoPublicKeys           = errors.New("can't verify JWT without public keys")
 	ErrParseSigningMethod          = errors.New("signing method doesn't match verifier")
 	ErrTokenParsePayloadValidation = errors.New("error validating token claims")
@@ -31,16 +30,6 @@ type Token struct {
 	SignatureBase64 string
 }
 
-// Algorithm is how the JWT will be signed.
-type Algorithm string
-
-// Algorithms supported for JWT signing.
-const (
-	AlgorithmES2565 = "ES256"
-	AlgorithmEdDSA  = "EdDSA"
-	AlgorithmRS256  = "RS256"
-)
-
 // TokenHeader is a JWT header.
 type TokenHeader struct {
 	Algorithm Algorithm `json:"alg"`
@@ -48,43 +37,11 @@ type TokenHeader struct {
 	Type      string    `json:"typ"`
 }
 
-func getSigningMethod(k cryptolib.Algorithm) (Algorithm, error) {
-	switch k { //nolint:exhaustive
-	case cryptolib.AlgorithmECP256Private:
-		fallthrough
-	case cryptolib.AlgorithmECP256Public:
-		return AlgorithmES2565, nil
-	case cryptolib.AlgorithmEd25519Private:
-		fallthrough
-	case cryptolib.AlgorithmEd25519Public:
-		return AlgorithmEdDSA, nil
-	case cryptolib.AlgorithmRSA2048Private:
-		fallthrough
-	case cryptolib.AlgorithmRSA2048Public:
-		return AlgorithmES2565, nil
-	}
-
-	return "", fmt.Errorf("%s: %w", k, ErrGetSigningMethod)
-}
-
 // New creates a new Token from CustomClaims.
 func New(claims CustomClaims, expiresAt time.Time, audience []string, id, issuer, subject string) (*Token, error) { //nolint:revive
 	t := Token{}
-	r := claims.GetRegisteredClaims()
-
-	var expires int64
 
-	if !expiresAt.IsZero() {
-		expires = expiresAt.Unix()
-	}
-
-	r.Audience = audience
-	r.ExpiresAt = expires
-	r.ID = id
-	r.IssuedAt = time.Now().Unix()
-	r.Issuer = issuer
-	r.NotBefore = time.Now().Unix()
-	r.Subject = subject
+	SetRegisteredClaims(claims, expiresAt, audience, id, issuer, subject)
 
 	p, err := json.Marshal(claims)
 	if err != nil {
@@ -96,73 +53,13 @@ func New(claims CustomClaims, expiresAt time.Time, audience []string, id, issuer
 	return &t, nil
 }
 
-// Parse takes a token and parses it into a Token struct for future use and the public key that verified it.  Returns an error if the signature does not match or the token format is invalid.
-func Parse(token string, keys cryptolib.Keys[cryptolib.KeyProviderPublic]) (*Token, cryptolib.Key[cryptolib.KeyProviderPublic], error) {
-	var p cryptolib.Key[cryptolib.KeyProviderPublic]
-
-	parts := strings.Split(token, ".")
-	if len(parts) != 3 {
-		return nil, p, ErrParseFormat
-	}
-
-	h, err := base64.RawURLEncoding.DecodeString(parts[0])
-	if err != nil {
-		return nil, p, fmt.Errorf("%w: %w", ErrParseFormat, err)
+// Parse takes a token and parses it into a Token struct for future use and the public key from keys that verified it.  Use StaticKeys for a fixed list of keys.  Only the default Algorithm of each key (or the alg of a JWK) is accepted, use a Verifier to allow others.  Returns an error if the signature does not match or the token format is invalid.
+func Parse(ctx context.Context, token string, keys KeySource) (*Token, cryptolib.Key[cryptolib.KeyProviderPublic], error) {
+	v := Verifier{
+		KeySource: keys,
 	}
 
-	header := TokenHeader{}
-
-	if err := json.Unmarshal(h, &header); err != nil {
-		return nil, p, fmt.Errorf("%w: %w", ErrParseFormat, err)
-	}
-
-	t := &Token{
-		Header:          header,
-		HeaderBase64:    parts[0],
-		PayloadBase64:   parts[1],
-		SignatureBase64: parts[2],
-	}
-
-	if len(keys) == 0 {
-		return t, p, ErrParseNoPublicKeys
-	}
-
-	sig, err := base64.RawURLEncoding.DecodeString(t.SignatureBase64)
-	if err != nil {
-		return nil, p, fmt.Errorf("%w: %w", ErrParseFormat, err)
-	}
-
-	for i := range keys {
-		err = keys[i].Key.Verify([]byte(strings.Join(parts[0:2], ".")), header.getHash(), sig)
-		if err == nil {
-			p = keys[i]
-
-			break
-		}
-	}
-
-	if err != nil {
-		return t, p, err
-	}
-
-	m, err := getSigningMethod(p.Key.Algorithm())
-	if err != nil {
-		return t, p, err
-	}
-
-	if m != header.Algorithm {
-		return t, p, ErrParseSigningMethod
-	}
-
-	return t, p, nil
-}
-
-func (h *TokenHeader) getHash() crypto.Hash {
-	if h.Algorithm == AlgorithmES2565 || h.Algorithm == AlgorithmRS256 {
-		return crypto.SHA256
-	}
-
-	return 0
+	return v.Parse(ctx, token)
 }
 
 // GetSignMessage is the message contents that need to be signed.
@@ -195,77 +92,31 @@ func (t *Token) ParsePayload(claims CustomClaims, audRegex string, jidRegex stri
 		return fmt.Errorf("%w: %w", ErrUnmarshalingJWT, err)
 	}
 
-	// Check claims
-	r := claims.GetRegisteredClaims()
-
-	if audRegex != "" {
-		audReg, err := regexp.Compile(audRegex)
-		if err != nil {
-			return fmt.Errorf("%w: error compiling audience regex: %w", ErrTokenParsePayloadValidation, err)
-		}
-
-		match := false
-
-		for i := range r.Audience {
-			if audReg.MatchString(r.Audience[i]) {
-				match = true
-
-				break
-			}
-		}
-
-		if !match {
-			return fmt.Errorf("%w: no aud matches", ErrTokenParsePayloadValidation)
-		}
-	}
-
-	if jidRegex != "" {
-		jidReg, err := regexp.Compile(jidRegex)
-		if err != nil {
-			return fmt.Errorf("%w: error compiling id regex: %w", ErrTokenParsePayloadValidation, err)
-		}
-
-		if !jidReg.MatchString(r.ID) {
-			return fmt.Errorf("%w: no jid matches", ErrTokenParsePayloadValidation)
-		}
-	}
-
-	now := time.Now()
-
-	if time.Unix(r.NotBefore, 0).After(now) {
-		return fmt.Errorf("%w: token is not valid yet", ErrTokenParsePayloadValidation)
-	}
-
-	if r.ExpiresAt != 0 && now.After(time.Unix(r.ExpiresAt, 0)) {
-		return fmt.Errorf("%w: token has expired", ErrTokenParsePayloadValidation)
-	}
-
-	if subRegex != "" {
-		subReg, err := regexp.Compile(jidRegex)
-		if err != nil {
-			return fmt.Errorf("%w: error compiling id regex: %w", ErrTokenParsePayloadValidation, err)
-		}
-
-		if !subReg.MatchString(r.ID) {
-			return fmt.Errorf("%w: no jid matches", ErrTokenParsePayloadValidation)
-		}
-	}
-
-	return claims.Valid()
+	return ValidateClaims(claims, audRegex, jidRegex, subRegex)
 }
 
+// Sign signs the Token using the default Algorithm for the key.
 func (t *Token) Sign(k cryptolib.Key[cryptolib.KeyProviderPrivate]) error {
 	a, err := getSigningMethod(k.Key.Algorithm())
 	if err != nil {
 		return err
 	}
 
+	return t.SignAlgorithm(k, a)
+}
+
+// SignAlgorithm signs the Token using a specific Algorithm.  Returns an error if the Algorithm can't be used with the key.
+func (t *Token) SignAlgorithm(k cryptolib.Key[cryptolib.KeyProviderPrivate], a Algorithm) error {
+	if !a.Supports(k.Key.Algorithm()) {
+		return fmt.Errorf("%w: %s can't be used with %s", ErrGetSigningMethod, a, k.Key.Algorithm())
+	}
+
 	m, err := t.GetSignMessage(a, k.ID)
 	if err != nil {
 		return err
 	}
 
-	sig, err := k.Key.Sign([]byte(m), t.Header.getHash())
+	sig, err := a.sign(k.Key, []byte(m))
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"testing"
@@ -14,6 +15,7 @@ import (
 func TestToken(t *testing.T) {
 	ed25519prv, ed25519pub, _ := cryptolib.NewEd25519()
 	ecp256prv, ecp256pub, _ := cryptolib.NewECP256()
+	ecp384prv, ecp384pub, _ := cryptolib.NewECP384()
 	rsa2048prv, rsa2048pub, _ := cryptolib.NewRSA2048()
 
 	tests := map[string]struct {
@@ -28,6 +30,10 @@ func TestToken(t *testing.T) {
 			private: ecp256prv,
 			public:  ecp256pub,
 		},
+		"ecp384": {
+			private: ecp384prv,
+			public:  ecp384pub,
+		},
 		"rsa2048": {
 			private: rsa2048prv,
 			public:  rsa2048pub,
@@ -37,13 +43,14 @@ func TestToken(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			e := time.Now().Add(10 * time.Second)
+			id := types.RandString(10)
 
 			prv := cryptolib.Key[cryptolib.KeyProviderPrivate]{
-				ID:  types.RandString(10),
+				ID:  id,
 				Key: tc.private,
 			}
 			pub := cryptolib.Key[cryptolib.KeyProviderPublic]{
-				ID:  types.RandString(10),
+				ID:  id,
 				Key: tc.public,
 			}
 			j1 := jwtCustom{
@@ -73,7 +80,7 @@ func TestToken(t *testing.T) {
 			assert.HasErr(t, got1.Sign(prv), nil)
 			assert.Equal(t, got1.SignatureBase64 != "", true)
 
-			gotT1, p, err := Parse(got1.String(), cryptolib.Keys[cryptolib.KeyProviderPublic]{
+			gotT1, p, err := Parse(context.Background(), got1.String(), StaticKeys{
 				pub,
 			})
 			assert.HasErr(t, err, nil)
@@ -95,7 +102,7 @@ func TestToken(t *testing.T) {
 			assert.HasErr(t, err, nil)
 			assert.HasErr(t, got2.Sign(prv), nil)
 
-			gotT2, _, err := Parse(got2.String(), cryptolib.Keys[cryptolib.KeyProviderPublic]{
+			gotT2, _, err := Parse(context.Background(), got2.String(), StaticKeys{
 				pub,
 			})
 			assert.HasErr(t, err, nil)
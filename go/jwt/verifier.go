@@ -0,0 +1,159 @@
+package jwt
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/candiddev/shared/go/cryptolib"
+)
+
+// ClaimsParser verifies a token and parses its claims.  It allows a token format to be chosen by configuration, like a JWT Verifier or a PASETO verifier.
+type ClaimsParser interface {
+	// ParseClaims verifies token, parses it into claims, and validates them using ValidateClaims.
+	ParseClaims(ctx context.Context, token string, claims CustomClaims, audRegex, jidRegex, subRegex string) error
+}
+
+// KeySource provides public keys for verifying JWTs, like a JWKS.
+type KeySource interface {
+	// PublicKeys returns the current public keys.  keyID is the kid of the JWT being verified, and may be used to refresh the keys if it is unknown.
+	PublicKeys(ctx context.Context, keyID string) (cryptolib.Keys[cryptolib.KeyProviderPublic], error)
+}
+
+// StaticKeys is a KeySource for a fixed list of public keys.
+type StaticKeys cryptolib.Keys[cryptolib.KeyProviderPublic]
+
+// PublicKeys returns the public keys.
+func (s StaticKeys) PublicKeys(_ context.Context, _ string) (cryptolib.Keys[cryptolib.KeyProviderPublic], error) {
+	return cryptolib.Keys[cryptolib.KeyProviderPublic](s), nil
+}
+
+// Verifier verifies JWTs using a set of public keys and an allowlist of Algorithms.
+type Verifier struct {
+	// Algorithms is the list of Algorithms the Verifier will accept.  If empty, each key only accepts its default Algorithm.
+	Algorithms []Algorithm
+
+	// KeySource is used instead of Keys if set.
+	KeySource KeySource
+	Keys      cryptolib.Keys[cryptolib.KeyProviderPublic]
+}
+
+// allows returns whether the Verifier will accept the Algorithm for a key.  A JWKPublicKey only accepts its Alg, which replaces the default Algorithm.
+func (v *Verifier) allows(a Algorithm, k cryptolib.KeyProviderPublic) bool {
+	if !a.Supports(k.Algorithm()) {
+		return false
+	}
+
+	j, ok := k.(JWKPublicKey)
+	if ok && j.Alg != a {
+		return false
+	}
+
+	if len(v.Algorithms) == 0 {
+		if ok {
+			return true
+		}
+
+		d, err := getSigningMethod(k.Algorithm())
+
+		return err == nil && d == a
+	}
+
+	for i := range v.Algorithms {
+		if v.Algorithms[i] == a {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parse takes a token and parses it into a Token struct for future use and the public key that verified it, without a JWKPublicKey wrapper.  The key is selected using the header kid, and the header alg must be allowed by the Verifier and match the key type.  Returns an error if the signature does not match or the token format is invalid.
+func (v *Verifier) Parse(ctx context.Context, token string) (*Token, cryptolib.Key[cryptolib.KeyProviderPublic], error) {
+	var p cryptolib.Key[cryptolib.KeyProviderPublic]
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, p, ErrParseFormat
+	}
+
+	h, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, p, fmt.Errorf("%w: %w", ErrParseFormat, err)
+	}
+
+	header := TokenHeader{}
+
+	if err := json.Unmarshal(h, &header); err != nil {
+		return nil, p, fmt.Errorf("%w: %w", ErrParseFormat, err)
+	}
+
+	t := &Token{
+		Header:          header,
+		HeaderBase64:    parts[0],
+		PayloadBase64:   parts[1],
+		SignatureBase64: parts[2],
+	}
+
+	k := v.Keys
+
+	if v.KeySource != nil {
+		k, err = v.KeySource.PublicKeys(ctx, header.KeyID)
+		if err != nil {
+			return t, p, err
+		}
+	}
+
+	if len(k) == 0 {
+		return t, p, ErrParseNoPublicKeys
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(t.SignatureBase64)
+	if err != nil {
+		return nil, p, fmt.Errorf("%w: %w", ErrParseFormat, err)
+	}
+
+	keys := cryptolib.Keys[cryptolib.KeyProviderPublic]{}
+
+	for i := range k {
+		if header.KeyID == "" || k[i].ID == header.KeyID {
+			keys = append(keys, k[i])
+		}
+	}
+
+	if len(keys) == 0 {
+		return t, p, fmt.Errorf("%w: %s", ErrParseKeyID, header.KeyID)
+	}
+
+	err = ErrParseSigningMethod
+
+	for i := range keys {
+		if !v.allows(header.Algorithm, keys[i].Key) {
+			continue
+		}
+
+		key := cryptolib.Key[cryptolib.KeyProviderPublic]{
+			ID:  keys[i].ID,
+			Key: unwrapPublicKey(keys[i].Key),
+		}
+
+		err = header.Algorithm.verify(key.Key, []byte(strings.Join(parts[0:2], ".")), sig)
+		if err == nil {
+			return t, key, nil
+		}
+	}
+
+	return t, p, err
+}
+
+// ParseClaims verifies a JWT and parses the payload into claims.
+func (v *Verifier) ParseClaims(ctx context.Context, token string, claims CustomClaims, audRegex, jidRegex, subRegex string) error {
+	t, _, err := v.Parse(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return t.ParsePayload(claims, audRegex, jidRegex, subRegex)
+}
@@ -0,0 +1,180 @@
+package jwt
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/cryptolib"
+)
+
+func TestVerifierParse(t *testing.T) {
+	ctx := context.Background()
+	ecp256prv, ecp256pub, _ := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmECP256)
+	ed25519prv, ed25519pub, _ := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmEd25519)
+	rsa2048prv, rsa2048pub, _ := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmRSA2048)
+
+	keys := cryptolib.Keys[cryptolib.KeyProviderPublic]{
+		ecp256pub,
+		ed25519pub,
+		rsa2048pub,
+	}
+
+	rsa2048jwk := cryptolib.Key[cryptolib.KeyProviderPublic]{
+		ID: rsa2048pub.ID,
+		Key: JWKPublicKey{
+			Alg:               AlgorithmPS256,
+			KeyProviderPublic: rsa2048pub.Key,
+		},
+	}
+
+	tests := map[string]struct {
+		algorithm  Algorithm
+		algorithms []Algorithm
+		keys       cryptolib.Keys[cryptolib.KeyProviderPublic]
+		private    cryptolib.Key[cryptolib.KeyProviderPrivate]
+		token      func(t *Token) string
+		wantErr    error
+		wantKey    cryptolib.Key[cryptolib.KeyProviderPublic]
+	}{
+		"default": {
+			keys:    keys,
+			private: ed25519prv,
+			wantKey: ed25519pub,
+		},
+		"ps256 not allowed": {
+			algorithm: AlgorithmPS256,
+			keys:      keys,
+			private:   rsa2048prv,
+			wantErr:   ErrParseSigningMethod,
+		},
+		"ps256 allowed": {
+			algorithm: AlgorithmPS256,
+			algorithms: []Algorithm{
+				AlgorithmPS256,
+			},
+			keys:    keys,
+			private: rsa2048prv,
+			wantKey: rsa2048pub,
+		},
+		"rs512 allowed": {
+			algorithm: AlgorithmRS512,
+			algorithms: []Algorithm{
+				AlgorithmRS256,
+				AlgorithmRS512,
+			},
+			keys:    keys,
+			private: rsa2048prv,
+			wantKey: rsa2048pub,
+		},
+		"allowlist excludes default": {
+			algorithms: []Algorithm{
+				AlgorithmEdDSA,
+			},
+			keys:    keys,
+			private: ecp256prv,
+			wantErr: ErrParseSigningMethod,
+		},
+		"jwk alg": {
+			algorithm: AlgorithmPS256,
+			keys: cryptolib.Keys[cryptolib.KeyProviderPublic]{
+				rsa2048jwk,
+			},
+			private: rsa2048prv,
+			wantKey: rsa2048pub,
+		},
+		"jwk alg mismatch": {
+			algorithms: []Algorithm{
+				AlgorithmPS256,
+				AlgorithmRS256,
+			},
+			keys: cryptolib.Keys[cryptolib.KeyProviderPublic]{
+				rsa2048jwk,
+			},
+			private: rsa2048prv,
+			wantErr: ErrParseSigningMethod,
+		},
+		"unknown kid": {
+			keys: cryptolib.Keys[cryptolib.KeyProviderPublic]{
+				ecp256pub,
+				rsa2048pub,
+			},
+			private: ed25519prv,
+			wantErr: ErrParseKeyID,
+		},
+		"no keys": {
+			private: ed25519prv,
+			wantErr: ErrParseNoPublicKeys,
+		},
+		"alg confusion": {
+			algorithms: []Algorithm{
+				AlgorithmES256,
+				AlgorithmRS256,
+			},
+			keys:    keys,
+			private: rsa2048prv,
+			token: func(t *Token) string {
+				t.GetSignMessage(AlgorithmES256, rsa2048prv.ID)
+
+				return t.String()
+			},
+			wantErr: ErrParseSigningMethod,
+		},
+		"alg none": {
+			keys:    keys,
+			private: ed25519prv,
+			token: func(t *Token) string {
+				t.GetSignMessage("none", ed25519prv.ID)
+				t.SignatureBase64 = ""
+
+				return t.String()
+			},
+			wantErr: ErrParseSigningMethod,
+		},
+		"bad signature": {
+			keys:    keys,
+			private: ecp256prv,
+			token: func(t *Token) string {
+				t.SignatureBase64 = base64.RawURLEncoding.EncodeToString([]byte("bad"))
+
+				return t.String()
+			},
+			wantErr: cryptolib.ErrVerify,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tok, _ := New(&jwtCustom{}, time.Now().Add(10*time.Second), nil, "", "", "")
+
+			if tc.algorithm == "" {
+				assert.HasErr(t, tok.Sign(tc.private), nil)
+			} else {
+				assert.HasErr(t, tok.SignAlgorithm(tc.private, tc.algorithm), nil)
+			}
+
+			s := tok.String()
+			if tc.token != nil {
+				s = tc.token(tok)
+			}
+
+			v := Verifier{
+				Algorithms: tc.algorithms,
+				Keys:       tc.keys,
+			}
+
+			_, k, err := v.Parse(ctx, s)
+			assert.HasErr(t, err, tc.wantErr)
+			assert.Equal(t, k, tc.wantKey)
+		})
+	}
+}
+
+func TestTokenSignAlgorithm(t *testing.T) {
+	prv, _, _ := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmEd25519)
+	tok, _ := New(&jwtCustom{}, time.Time{}, nil, "", "", "")
+
+	assert.HasErr(t, tok.SignAlgorithm(prv, AlgorithmRS256), ErrGetSigningMethod)
+}
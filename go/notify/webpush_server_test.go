@@ -194,7 +194,7 @@ func TestWebPushSend(t *testing.T) {
 				srvB, err := base64.RawURLEncoding.DecodeString(tc.srvPub)
 				assert.HasErr(t, err, nil)
 
-				token, _, err := jwt.Parse(strings.Split(gotHeader.Get("Authorization"), " ")[1], cryptolib.Keys[cryptolib.KeyProviderPublic]{
+				token, _, err := jwt.Parse(context.Background(), strings.Split(gotHeader.Get("Authorization"), " ")[1], jwt.StaticKeys{
 					{
 						Key: cryptolib.ECP256PublicKey(base64.StdEncoding.EncodeToString(srvB)),
 					},
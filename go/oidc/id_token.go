@@ -0,0 +1,132 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/candiddev/shared/go/jwt"
+)
+
+// IDTokenClaims are the claims of an ID token.
+type IDTokenClaims struct {
+	AccessTokenHash   string `json:"at_hash,omitempty"`
+	AuthTime          int64  `json:"auth_time,omitempty"`
+	AuthorizedParty   string `json:"azp,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     bool   `json:"email_verified,omitempty"`
+	Name              string `json:"name,omitempty"`
+	Nonce             string `json:"nonce,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	jwt.RegisteredClaims
+}
+
+func (i *IDTokenClaims) GetRegisteredClaims() *jwt.RegisteredClaims {
+	return &i.RegisteredClaims
+}
+
+func (i *IDTokenClaims) Valid() error {
+	if i.Subject == "" {
+		return fmt.Errorf("%w: missing sub", ErrIDToken)
+	}
+
+	if i.ExpiresAt == 0 {
+		return fmt.Errorf("%w: missing exp", ErrIDToken)
+	}
+
+	return nil
+}
+
+// VerifyIDToken verifies the signature of an ID token using the provider JWKS and validates the iss, aud, azp, exp, and nonce claims.  If accessToken is not empty, it is checked against at_hash.
+func (c *Client) VerifyIDToken(ctx context.Context, idToken, nonce, accessToken string) (*IDTokenClaims, error) {
+	d, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v := jwt.Verifier{
+		KeySource: c.jwks,
+	}
+
+	for i := range d.IDTokenSigningAlgValuesSupported {
+		v.Algorithms = append(v.Algorithms, jwt.Algorithm(d.IDTokenSigningAlgValuesSupported[i]))
+	}
+
+	if len(v.Algorithms) == 0 {
+		v.Algorithms = []jwt.Algorithm{
+			jwt.AlgorithmRS256,
+		}
+	}
+
+	t, _, err := v.Parse(ctx, idToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrIDToken, err)
+	}
+
+	i := &IDTokenClaims{}
+
+	if err := t.ParsePayload(i, "", "", ""); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrIDToken, err)
+	}
+
+	if i.Issuer != d.Issuer {
+		return nil, fmt.Errorf("%w: iss does not match", ErrIDToken)
+	}
+
+	aud := false
+
+	for j := range i.Audience {
+		if i.Audience[j] == c.ClientID {
+			aud = true
+		}
+	}
+
+	if !aud {
+		return nil, fmt.Errorf("%w: aud does not contain client ID", ErrIDToken)
+	}
+
+	if (i.AuthorizedParty != "" || len(i.Audience) > 1) && i.AuthorizedParty != c.ClientID {
+		return nil, fmt.Errorf("%w: azp does not match client ID", ErrIDToken)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(i.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce does not match", ErrIDToken)
+	}
+
+	if accessToken != "" && i.AccessTokenHash != "" {
+		h, err := atHash(t.Header.Algorithm, accessToken)
+		if err != nil {
+			return nil, err
+		}
+
+		if subtle.ConstantTimeCompare([]byte(h), []byte(i.AccessTokenHash)) != 1 {
+			return nil, fmt.Errorf("%w: at_hash does not match", ErrIDToken)
+		}
+	}
+
+	return i, nil
+}
+
+// atHash returns the at_hash of an access token: the base64url encoding of the left half of its hash, using the hash of the ID token Algorithm.
+func atHash(a jwt.Algorithm, accessToken string) (string, error) {
+	var h crypto.Hash
+
+	switch a {
+	case jwt.AlgorithmES256, jwt.AlgorithmPS256, jwt.AlgorithmRS256:
+		h = crypto.SHA256
+	case jwt.AlgorithmES384, jwt.AlgorithmRS384:
+		h = crypto.SHA384
+	case jwt.AlgorithmEdDSA, jwt.AlgorithmRS512:
+		h = crypto.SHA512
+	default:
+		return "", fmt.Errorf("%w: unsupported at_hash algorithm %s", ErrIDToken, a)
+	}
+
+	s := h.New()
+	s.Write([]byte(accessToken))
+	b := s.Sum(nil)
+
+	return base64.RawURLEncoding.EncodeToString(b[:len(b)/2]), nil
+}
@@ -0,0 +1,270 @@
+// Package oidc contains an OpenID Connect relying party client.
+package oidc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/candiddev/shared/go/get"
+	"github.com/candiddev/shared/go/jwt"
+)
+
+var (
+	ErrDiscovery     = errors.New("error discovering OIDC provider")
+	ErrIDToken       = errors.New("error validating ID token")
+	ErrProvider      = errors.New("OIDC provider returned an error")
+	ErrState         = errors.New("OIDC state does not match")
+	ErrTokenExchange = errors.New("error exchanging OIDC code")
+	ErrUserinfo      = errors.New("error getting OIDC userinfo")
+)
+
+// Client is an OpenID Connect relying party using the authorization code flow with PKCE.
+type Client struct {
+	ClientID     string
+	ClientSecret string
+
+	// HTTPClient is used for token and userinfo requests.  Defaults to a client with a 10 second timeout.
+	HTTPClient *http.Client
+
+	// Issuer is the URL of the provider, used to discover the other endpoints.
+	Issuer      string
+	RedirectURL string
+
+	// Scopes are requested in addition to openid.
+	Scopes []string
+
+	discovery *Discovery
+	jwks      *jwt.JWKS
+	mutex     sync.Mutex
+}
+
+// Discovery is an OpenID Provider configuration document.
+type Discovery struct {
+	AuthorizationEndpoint            string   `json:"authorization_endpoint"`
+	CodeChallengeMethodsSupported    []string `json:"code_challenge_methods_supported,omitempty"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported,omitempty"`
+	Issuer                           string   `json:"issuer"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	TokenEndpoint                    string   `json:"token_endpoint"`
+	UserinfoEndpoint                 string   `json:"userinfo_endpoint,omitempty"`
+}
+
+// AuthRequest is an authorization request.  State, Nonce, and CodeVerifier must be stored, like in a cookie, until the callback.
+type AuthRequest struct {
+	CodeVerifier string
+	Nonce        string
+	State        string
+	URL          string
+}
+
+// Tokens is a successful token response.
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int    `json:"expires_in,omitempty"`
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+	TokenType    string `json:"token_type"`
+}
+
+type tokenError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Discover retrieves the discovery document of the Issuer.  The document is cached after the first successful request.
+func (c *Client) Discover(ctx context.Context) (*Discovery, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.discovery != nil {
+		return c.discovery, nil
+	}
+
+	b := bytes.Buffer{}
+
+	if _, err := get.FileHTTP(ctx, strings.TrimSuffix(c.Issuer, "/")+"/.well-known/openid-configuration", &b, get.HTTPCache{}); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDiscovery, err)
+	}
+
+	d := &Discovery{}
+
+	if err := json.Unmarshal(b.Bytes(), d); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDiscovery, err)
+	}
+
+	if d.Issuer != c.Issuer {
+		return nil, fmt.Errorf("%w: issuer %s does not match %s", ErrDiscovery, d.Issuer, c.Issuer)
+	}
+
+	if d.AuthorizationEndpoint == "" || d.JWKSURI == "" || d.TokenEndpoint == "" {
+		return nil, fmt.Errorf("%w: missing endpoints", ErrDiscovery)
+	}
+
+	c.discovery = d
+	c.jwks = &jwt.JWKS{
+		URL: d.JWKSURI,
+	}
+
+	return d, nil
+}
+
+// AuthRequest creates a new authorization request with a random state, nonce, and PKCE code verifier.
+func (c *Client) AuthRequest(ctx context.Context) (*AuthRequest, error) {
+	d, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &AuthRequest{}
+
+	for _, s := range []*string{&a.CodeVerifier, &a.Nonce, &a.State} {
+		*s, err = random()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	h := sha256.Sum256([]byte(a.CodeVerifier))
+
+	v := url.Values{}
+	v.Set("client_id", c.ClientID)
+	v.Set("code_challenge", base64.RawURLEncoding.EncodeToString(h[:]))
+	v.Set("code_challenge_method", "S256")
+	v.Set("nonce", a.Nonce)
+	v.Set("redirect_uri", c.RedirectURL)
+	v.Set("response_type", "code")
+	v.Set("scope", strings.Join(append([]string{"openid"}, c.Scopes...), " "))
+	v.Set("state", a.State)
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	a.URL = d.AuthorizationEndpoint + sep + v.Encode()
+
+	return a, nil
+}
+
+// Callback handles the query of the redirect from the provider.  It checks the state, exchanges the code, and validates the ID token.
+func (c *Client) Callback(ctx context.Context, query url.Values, a *AuthRequest) (*IDTokenClaims, *Tokens, error) {
+	if e := query.Get("error"); e != "" {
+		return nil, nil, fmt.Errorf("%w: %s: %s", ErrProvider, e, query.Get("error_description"))
+	}
+
+	if a == nil || a.State == "" || query.Get("state") != a.State {
+		return nil, nil, ErrState
+	}
+
+	t, err := c.Exchange(ctx, query.Get("code"), a.CodeVerifier)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	i, err := c.VerifyIDToken(ctx, t.IDToken, a.Nonce, t.AccessToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return i, t, nil
+}
+
+// Exchange exchanges an authorization code for Tokens.
+func (c *Client) Exchange(ctx context.Context, code, codeVerifier string) (*Tokens, error) {
+	d, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v := url.Values{}
+	v.Set("code", code)
+	v.Set("code_verifier", codeVerifier)
+	v.Set("grant_type", "authorization_code")
+	v.Set("redirect_uri", c.RedirectURL)
+
+	if c.ClientSecret == "" {
+		v.Set("client_id", c.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenExchange, err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if c.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+	}
+
+	b, status, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenExchange, err)
+	}
+
+	if status != http.StatusOK {
+		e := tokenError{}
+		json.Unmarshal(b, &e) //nolint:errcheck
+
+		return nil, fmt.Errorf("%w: bad response from server: %d: %s %s", ErrTokenExchange, status, e.Error, e.ErrorDescription)
+	}
+
+	t := &Tokens{}
+
+	if err := json.Unmarshal(b, t); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenExchange, err)
+	}
+
+	if t.IDToken == "" {
+		return nil, fmt.Errorf("%w: response has no id_token", ErrTokenExchange)
+	}
+
+	return t, nil
+}
+
+func (c *Client) do(req *http.Request) ([]byte, int, error) {
+	client := c.HTTPClient
+	if client == nil {
+		client = &http.Client{
+			Timeout: 10 * time.Second,
+		}
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return b, res.StatusCode, nil
+}
+
+func random() (string, error) {
+	b := make([]byte, 32)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
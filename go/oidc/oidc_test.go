@@ -0,0 +1,289 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/cryptolib"
+	"github.com/candiddev/shared/go/jwt"
+)
+
+// provider is a stand-in OpenID provider.
+type provider struct {
+	audience    []string
+	azp         string
+	atHash      string
+	codes       map[string]url.Values
+	key         cryptolib.Key[cryptolib.KeyProviderPrivate]
+	mutex       sync.Mutex
+	public      cryptolib.Key[cryptolib.KeyProviderPublic]
+	server      *httptest.Server
+	userinfoSub string
+}
+
+func newProvider(t *testing.T) *provider {
+	t.Helper()
+
+	prv, pub, err := cryptolib.NewRSA2048()
+	assert.HasErr(t, err, nil)
+
+	p := &provider{
+		codes: map[string]url.Values{},
+		key: cryptolib.Key[cryptolib.KeyProviderPrivate]{
+			ID:  "1",
+			Key: prv,
+		},
+		public: cryptolib.Key[cryptolib.KeyProviderPublic]{
+			ID:  "1",
+			Key: pub,
+		},
+	}
+
+	m := http.NewServeMux()
+	m.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(Discovery{
+			AuthorizationEndpoint:            p.server.URL + "/authorize",
+			CodeChallengeMethodsSupported:    []string{"S256"},
+			IDTokenSigningAlgValuesSupported: []string{"RS256"},
+			Issuer:                           p.server.URL,
+			JWKSURI:                          p.server.URL + "/jwks",
+			TokenEndpoint:                    p.server.URL + "/token",
+			UserinfoEndpoint:                 p.server.URL + "/userinfo",
+		})
+	})
+	m.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		s, _ := jwt.NewJWKSet(cryptolib.Keys[cryptolib.KeyProviderPublic]{
+			p.public,
+		})
+		json.NewEncoder(w).Encode(s)
+	})
+	m.HandleFunc("/token", p.token)
+	m.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		w.Write([]byte(`{"sub":"` + p.userinfoSub + `","email":"jane@example.com","groups":["admins"]}`))
+	})
+
+	p.server = httptest.NewServer(m)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+// authorize simulates the user signing in at the authorization endpoint.
+func (p *provider) authorize(t *testing.T, u string) url.Values {
+	t.Helper()
+
+	r, err := url.Parse(u)
+	assert.HasErr(t, err, nil)
+
+	q := r.Query()
+
+	p.mutex.Lock()
+	p.codes["code"] = q
+	p.mutex.Unlock()
+
+	return url.Values{
+		"code":  []string{"code"},
+		"state": []string{q.Get("state")},
+	}
+}
+
+func (p *provider) token(w http.ResponseWriter, r *http.Request) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	id, secret, _ := r.BasicAuth()
+	r.ParseForm()
+
+	q, ok := p.codes[r.PostForm.Get("code")]
+	h := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+
+	if !ok || id != "client" || secret != "secret" || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != q.Get("redirect_uri") || base64.RawURLEncoding.EncodeToString(h[:]) != q.Get("code_challenge") {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant","error_description":"bad code"}`))
+
+		return
+	}
+
+	delete(p.codes, "code")
+
+	aud := p.audience
+	if aud == nil {
+		aud = []string{"client"}
+	}
+
+	c := IDTokenClaims{
+		AccessTokenHash: p.atHash,
+		AuthorizedParty: p.azp,
+		Email:           "jane@example.com",
+		Nonce:           q.Get("nonce"),
+	}
+
+	if c.AccessTokenHash == "" {
+		c.AccessTokenHash, _ = atHash(jwt.AlgorithmRS256, "access")
+	}
+
+	tok, _ := jwt.New(&c, time.Now().Add(time.Minute), aud, "", p.server.URL, "jane")
+	tok.Sign(p.key)
+
+	json.NewEncoder(w).Encode(Tokens{
+		AccessToken: "access",
+		IDToken:     tok.String(),
+		TokenType:   "Bearer",
+	})
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	p := newProvider(t)
+
+	c := Client{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Issuer:       p.server.URL,
+		RedirectURL:  "https://app.example.com/callback",
+		Scopes:       []string{"email"},
+	}
+
+	tests := map[string]struct {
+		audience    []string
+		atHash      string
+		azp         string
+		err         error
+		modifyQuery func(q url.Values)
+		userinfoSub string
+		userinfoErr error
+	}{
+		"good": {
+			userinfoSub: "jane",
+		},
+		"good azp": {
+			audience:    []string{"client", "other"},
+			azp:         "client",
+			userinfoSub: "jane",
+		},
+		"wrong userinfo sub": {
+			userinfoSub: "john",
+			userinfoErr: ErrUserinfo,
+		},
+		"wrong state": {
+			err: ErrState,
+			modifyQuery: func(q url.Values) {
+				q.Set("state", "wrong")
+			},
+		},
+		"provider error": {
+			err: ErrProvider,
+			modifyQuery: func(q url.Values) {
+				q.Set("error", "access_denied")
+			},
+		},
+		"wrong code": {
+			err: ErrTokenExchange,
+			modifyQuery: func(q url.Values) {
+				q.Set("code", "wrong")
+			},
+		},
+		"wrong audience": {
+			audience: []string{"other"},
+			err:      ErrIDToken,
+		},
+		"missing azp": {
+			audience: []string{"client", "other"},
+			err:      ErrIDToken,
+		},
+		"wrong azp": {
+			azp: "other",
+			err: ErrIDToken,
+		},
+		"wrong at_hash": {
+			atHash: "wrong",
+			err:    ErrIDToken,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p.audience = tc.audience
+			p.atHash = tc.atHash
+			p.azp = tc.azp
+			p.userinfoSub = tc.userinfoSub
+
+			a, err := c.AuthRequest(ctx)
+			assert.HasErr(t, err, nil)
+
+			u, _ := url.Parse(a.URL)
+			assert.Equal(t, u.Query().Get("code_challenge_method"), "S256")
+			assert.Equal(t, u.Query().Get("scope"), "openid email")
+
+			q := p.authorize(t, a.URL)
+			if tc.modifyQuery != nil {
+				tc.modifyQuery(q)
+			}
+
+			i, tokens, err := c.Callback(ctx, q, a)
+			assert.HasErr(t, err, tc.err)
+
+			if tc.err == nil {
+				assert.Equal(t, i.Email, "jane@example.com")
+				assert.Equal(t, i.Subject, "jane")
+
+				info, err := c.Userinfo(ctx, tokens.AccessToken, i.Subject)
+				assert.HasErr(t, err, tc.userinfoErr)
+
+				if tc.userinfoErr == nil {
+					assert.Equal(t, info.Email, "jane@example.com")
+					assert.Equal[any](t, info.Claims["groups"], []any{"admins"})
+				}
+			}
+		})
+	}
+
+	// Nonce replay
+	a, _ := c.AuthRequest(ctx)
+	q := p.authorize(t, a.URL)
+	a.Nonce = "other"
+	_, _, err := c.Callback(ctx, q, a)
+	assert.HasErr(t, err, ErrIDToken)
+}
+
+func TestDiscover(t *testing.T) {
+	p := newProvider(t)
+
+	c := Client{
+		Issuer: p.server.URL + "/other",
+	}
+
+	_, err := c.Discover(context.Background())
+	assert.HasErr(t, err, ErrDiscovery)
+
+	c.Issuer = p.server.URL
+
+	d, err := c.Discover(context.Background())
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, d.TokenEndpoint, p.server.URL+"/token")
+}
+
+func TestATHash(t *testing.T) {
+	// https://openid.net/specs/openid-connect-core-1_0.html#id_token-tokenExample
+	got, err := atHash(jwt.AlgorithmRS256, "jHkWEdUXMU1BwAsC4vtUsZwnNvTIxEl0z9K3vx5KF0Y")
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, got, "77QmUPtjPfzWtF2AnpK9RQ")
+
+	_, err = atHash("none", "")
+	assert.HasErr(t, err, ErrIDToken)
+}
@@ -0,0 +1,64 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Userinfo is the response of the userinfo endpoint.  Claims contains every claim, including those without a field.
+type Userinfo struct {
+	Claims            map[string]any `json:"-"`
+	Email             string         `json:"email,omitempty"`
+	EmailVerified     bool           `json:"email_verified,omitempty"`
+	Name              string         `json:"name,omitempty"`
+	Picture           string         `json:"picture,omitempty"`
+	PreferredUsername string         `json:"preferred_username,omitempty"`
+	Subject           string         `json:"sub"`
+}
+
+// Userinfo retrieves the claims about the user of an access token.  If subject is not empty, the userinfo sub must match it, like the sub of the ID token.
+func (c *Client) Userinfo(ctx context.Context, accessToken, subject string) (*Userinfo, error) {
+	d, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.UserinfoEndpoint == "" {
+		return nil, fmt.Errorf("%w: provider has no userinfo endpoint", ErrUserinfo)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.UserinfoEndpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUserinfo, err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	b, status, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUserinfo, err)
+	}
+
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("%w: bad response from server: %d", ErrUserinfo, status)
+	}
+
+	u := &Userinfo{}
+
+	if err := json.Unmarshal(b, u); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUserinfo, err)
+	}
+
+	if err := json.Unmarshal(b, &u.Claims); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUserinfo, err)
+	}
+
+	if u.Subject == "" || (subject != "" && u.Subject != subject) {
+		return nil, fmt.Errorf("%w: sub does not match", ErrUserinfo)
+	}
+
+	return u, nil
+}
@@ -0,0 +1,178 @@
+package paseto
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/candiddev/shared/go/cryptolib"
+	"github.com/candiddev/shared/go/jwt"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20"
+)
+
+const (
+	localKeySize   = 32
+	localNonceSize = 32
+	localTagSize   = 32
+)
+
+// Encrypt creates a v4.local PASETO from claims using a ChaCha20 key.  The key ID is added to the footer.
+func Encrypt(claims jwt.CustomClaims, k cryptolib.Key[cryptolib.KeyProviderSymmetric]) (string, error) {
+	key, err := localKey(k.Key)
+	if err != nil {
+		return "", err
+	}
+
+	m, err := marshalClaims(claims)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := newFooter(k.ID)
+	if err != nil {
+		return "", err
+	}
+
+	n := make([]byte, localNonceSize)
+	if _, err := io.ReadFull(rand.Reader, n); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrKey, err)
+	}
+
+	return encryptLocal(m, f, nil, key, n)
+}
+
+func localKey(k cryptolib.KeyProviderSymmetric) ([]byte, error) {
+	c, ok := k.(cryptolib.ChaCha20Key)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s can't encrypt %s", ErrKey, k.Algorithm(), HeaderV4Local)
+	}
+
+	b, err := base64.StdEncoding.DecodeString(string(c))
+	if err != nil || len(b) != localKeySize {
+		return nil, fmt.Errorf("%w: invalid ChaCha20 key", ErrKey)
+	}
+
+	return b, nil
+}
+
+// localKeys derives the encryption key, XChaCha20 nonce, and authentication key from the key and nonce.
+func localKeys(key, n []byte) (ek, n2, ak []byte, err error) {
+	h, err := blake2b.New(56, key)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %w", ErrKey, err)
+	}
+
+	h.Write([]byte("paseto-encryption-key"))
+	h.Write(n)
+	tmp := h.Sum(nil)
+
+	h, err = blake2b.New(32, key)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %w", ErrKey, err)
+	}
+
+	h.Write([]byte("paseto-auth-key-for-aead"))
+	h.Write(n)
+
+	return tmp[:32], tmp[32:], h.Sum(nil), nil
+}
+
+func localTag(ak []byte, pieces ...[]byte) ([]byte, error) {
+	h, err := blake2b.New(localTagSize, ak)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrKey, err)
+	}
+
+	h.Write(pae(pieces...))
+
+	return h.Sum(nil), nil
+}
+
+func encryptLocal(m, f, i, key, n []byte) (string, error) {
+	ek, n2, ak, err := localKeys(key, n)
+	if err != nil {
+		return "", err
+	}
+
+	s, err := chacha20.NewUnauthenticatedCipher(ek, n2)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrKey, err)
+	}
+
+	c := make([]byte, len(m))
+	s.XORKeyStream(c, m)
+
+	t, err := localTag(ak, []byte(HeaderV4Local), n, c, f, i)
+	if err != nil {
+		return "", err
+	}
+
+	body := append(append(append([]byte{}, n...), c...), t...)
+
+	return join(HeaderV4Local, body, f), nil
+}
+
+func decryptLocal(token string, i []byte, keys cryptolib.Keys[cryptolib.KeyProviderSymmetric]) (*Token, error) {
+	body, f, err := split(token, HeaderV4Local)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) < localNonceSize+localTagSize {
+		return nil, fmt.Errorf("%w: token is too short", ErrFormat)
+	}
+
+	t := &Token{
+		Header: HeaderV4Local,
+	}
+
+	t.Footer, err = parseFooter(f)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err = selectKeys(keys, t.Footer.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	n := body[:localNonceSize]
+	c := body[localNonceSize : len(body)-localTagSize]
+	tag := body[len(body)-localTagSize:]
+
+	for j := range keys {
+		key, err := localKey(keys[j].Key)
+		if err != nil {
+			continue
+		}
+
+		ek, n2, ak, err := localKeys(key, n)
+		if err != nil {
+			return nil, err
+		}
+
+		t2, err := localTag(ak, []byte(HeaderV4Local), n, c, f, i)
+		if err != nil {
+			return nil, err
+		}
+
+		if !hmac.Equal(tag, t2) {
+			continue
+		}
+
+		s, err := chacha20.NewUnauthenticatedCipher(ek, n2)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrKey, err)
+		}
+
+		t.Payload = make([]byte, len(c))
+		s.XORKeyStream(t.Payload, c)
+
+		return t, nil
+	}
+
+	return nil, ErrVerify
+}
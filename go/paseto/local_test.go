@@ -0,0 +1,155 @@
+package paseto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/cryptolib"
+)
+
+func TestLocal(t *testing.T) {
+	k, _ := cryptolib.NewChaCha20Key(rand.Reader)
+	keys := cryptolib.Keys[cryptolib.KeyProviderSymmetric]{
+		{
+			Key: k,
+		},
+	}
+
+	key, err := localKey(k)
+	assert.HasErr(t, err, nil)
+
+	m := []byte(`{"data":"this is a secret message"}`)
+	f := []byte(`{"kid":"1"}`)
+	n := make([]byte, localNonceSize)
+
+	got1, err := encryptLocal(m, nil, nil, key, n)
+	assert.HasErr(t, err, nil)
+
+	got2, _ := encryptLocal(m, nil, nil, key, n)
+	assert.Equal(t, got1, got2)
+	assert.Equal(t, bytes.Contains([]byte(got1), m), false)
+
+	tok, err := decryptLocal(got1, nil, keys)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, tok.Payload, m)
+
+	_, err = decryptLocal(got1, []byte("implicit"), keys)
+	assert.HasErr(t, err, ErrVerify)
+
+	_, err = decryptLocal(got1[:len(got1)-2]+"AA", nil, keys)
+	assert.HasErr(t, err, ErrVerify)
+
+	got3, _ := encryptLocal(m, f, nil, key, n)
+
+	_, err = decryptLocal(got3, nil, keys)
+	assert.HasErr(t, err, ErrKeyID)
+
+	keys[0].ID = "1"
+
+	tok, err = decryptLocal(got3, nil, keys)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, tok.Footer.KeyID, "1")
+
+	// Footer is authenticated.
+	_, err = decryptLocal(got1+".eyJraWQiOiIxIn0", nil, keys)
+	assert.HasErr(t, err, ErrVerify)
+
+	_, err = localKey(cryptolib.AES128Key(""))
+	assert.HasErr(t, err, ErrKey)
+}
+
+func TestLocalVectors(t *testing.T) {
+	// https://github.com/paseto-standard/test-vectors/blob/master/v4.json
+	key, _ := hex.DecodeString("707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f")
+	keys := cryptolib.Keys[cryptolib.KeyProviderSymmetric]{
+		{
+			ID:  "zVhMiPBP9fRf2snEcT7gFTioeA9COcNy9DfgL1W60haN",
+			Key: cryptolib.ChaCha20Key(base64.StdEncoding.EncodeToString(key)),
+		},
+	}
+
+	secret := `{"data":"this is a secret message","exp":"2022-01-01T00:00:00+00:00"}`
+	hidden := `{"data":"this is a hidden message","exp":"2022-01-01T00:00:00+00:00"}`
+	footer := `{"kid":"zVhMiPBP9fRf2snEcT7gFTioeA9COcNy9DfgL1W60haN"}`
+	nonce := "df654812bac492663825520ba2f6e67cf5ca5bdc13d4e7507a98cc4c2fcc3ad8"
+
+	tests := map[string]struct {
+		footer   string
+		implicit string
+		nonce    string
+		payload  string
+		token    string
+	}{
+		"4-E-1": {
+			nonce:   "0000000000000000000000000000000000000000000000000000000000000000",
+			payload: secret,
+			token:   "v4.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAr68PS4AXe7If_ZgesdkUMvSwscFlAl1pk5HC0e8kApeaqMfGo_7OpBnwJOAbY9V7WU6abu74MmcUE8YWAiaArVI8XJ5hOb_4v9RmDkneN0S92dx0OW4pgy7omxgf3S8c3LlQg",
+		},
+		"4-E-2": {
+			nonce:   "0000000000000000000000000000000000000000000000000000000000000000",
+			payload: hidden,
+			token:   "v4.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAr68PS4AXe7If_ZgesdkUMvS2csCgglvpk5HC0e8kApeaqMfGo_7OpBnwJOAbY9V7WU6abu74MmcUE8YWAiaArVI8XIemu9chy3WVKvRBfg6t8wwYHK0ArLxxfZP73W_vfwt5A",
+		},
+		"4-E-3": {
+			nonce:   nonce,
+			payload: secret,
+			token:   "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WkwMsYXw6FSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t6-tyebyWG6Ov7kKvBdkrrAJ837lKP3iDag2hzUPHuMKA",
+		},
+		"4-E-4": {
+			nonce:   nonce,
+			payload: hidden,
+			token:   "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WiA8rd3wgFSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t4gt6TiLm55vIH8c_lGxxZpE3AWlH4WTR0v45nsWoU3gQ",
+		},
+		"4-E-5": {
+			footer:  footer,
+			nonce:   nonce,
+			payload: secret,
+			token:   "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WkwMsYXw6FSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t4x-RMNXtQNbz7FvFZ_G-lFpk5RG3EOrwDL6CgDqcerSQ.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9",
+		},
+		"4-E-6": {
+			footer:  footer,
+			nonce:   nonce,
+			payload: hidden,
+			token:   "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WiA8rd3wgFSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t6pWSA5HX2wjb3P-xLQg5K5feUCX4P2fpVK3ZLWFbMSxQ.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9",
+		},
+		"4-E-7": {
+			footer:   footer,
+			implicit: `{"test-vector":"4-E-7"}`,
+			nonce:    nonce,
+			payload:  secret,
+			token:    "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WkwMsYXw6FSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t40KCCWLA7GYL9KFHzKlwY9_RnIfRrMQpueydLEAZGGcA.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9",
+		},
+		"4-E-8": {
+			footer:   footer,
+			implicit: `{"test-vector":"4-E-8"}`,
+			nonce:    nonce,
+			payload:  hidden,
+			token:    "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WiA8rd3wgFSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t5uvqQbMGlLLNYBc7A6_x7oqnpUK5WLvj24eE4DVPDZjw.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9",
+		},
+		"4-E-9": {
+			footer:   "arbitrary-string-that-isn't-json",
+			implicit: `{"test-vector":"4-E-9"}`,
+			nonce:    nonce,
+			payload:  hidden,
+			token:    "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WiA8rd3wgFSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t6tybdlmnMwcDMw0YxA_gFSE_IUWl78aMtOepFYSWYfQA.YXJiaXRyYXJ5LXN0cmluZy10aGF0LWlzbid0LWpzb24",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, _ := hex.DecodeString(tc.nonce)
+
+			got, err := encryptLocal([]byte(tc.payload), []byte(tc.footer), []byte(tc.implicit), key, n)
+			assert.HasErr(t, err, nil)
+			assert.Equal(t, got, tc.token)
+
+			tok, err := decryptLocal(tc.token, []byte(tc.implicit), keys)
+			assert.HasErr(t, err, nil)
+			assert.Equal(t, string(tok.Payload), tc.payload)
+		})
+	}
+}
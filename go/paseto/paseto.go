@@ -0,0 +1,187 @@
+// Package paseto contains functions for creating and verifying PASETO v4 tokens.
+package paseto
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/candiddev/shared/go/jwt"
+)
+
+const (
+	HeaderV4Local  = "v4.local."
+	HeaderV4Public = "v4.public."
+)
+
+var (
+	ErrFormat    = errors.New("paseto has invalid format")
+	ErrKey       = errors.New("paseto key is not supported")
+	ErrKeyID     = errors.New("no key matches the paseto key ID")
+	ErrMarshal   = errors.New("error converting claims to JSON")
+	ErrNoKeys    = errors.New("can't verify paseto without keys")
+	ErrUnmarshal = errors.New("error unmarshaling paseto")
+	ErrVerify    = errors.New("error verifying paseto")
+)
+
+// Footer is the unencrypted, authenticated footer of a PASETO.
+type Footer struct {
+	KeyID string `json:"kid,omitempty"`
+}
+
+// Token is a verified PASETO.
+type Token struct {
+	Footer  Footer
+	Header  string
+	Payload []byte
+}
+
+// ParsePayload parses the Token payload into claims and validates them using jwt.ValidateClaims.
+func (t *Token) ParsePayload(claims jwt.CustomClaims, audRegex string, jidRegex string, subRegex string) error {
+	if err := unmarshalClaims(t.Payload, claims); err != nil {
+		return err
+	}
+
+	return jwt.ValidateClaims(claims, audRegex, jidRegex, subRegex)
+}
+
+// marshalClaims converts claims to JSON, using RFC 3339 strings for the time claims as PASETO requires.
+func marshalClaims(claims jwt.CustomClaims) ([]byte, error) {
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMarshal, err)
+	}
+
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMarshal, err)
+	}
+
+	for _, c := range []string{"exp", "iat", "nbf"} {
+		var i int64
+
+		if v, ok := m[c]; ok && json.Unmarshal(v, &i) == nil {
+			m[c], _ = json.Marshal(time.Unix(i, 0).UTC().Format(time.RFC3339))
+		}
+	}
+
+	b, err = json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMarshal, err)
+	}
+
+	return b, nil
+}
+
+func unmarshalClaims(payload []byte, claims jwt.CustomClaims) error {
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(payload, &m); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshal, err)
+	}
+
+	for _, c := range []string{"exp", "iat", "nbf"} {
+		var s string
+
+		if v, ok := m[c]; ok && json.Unmarshal(v, &s) == nil {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return fmt.Errorf("%w: %s: %w", ErrUnmarshal, c, err)
+			}
+
+			m[c], _ = json.Marshal(t.Unix())
+		}
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshal, err)
+	}
+
+	if err := json.Unmarshal(b, claims); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshal, err)
+	}
+
+	return nil
+}
+
+func newFooter(keyID string) ([]byte, error) {
+	if keyID == "" {
+		return nil, nil
+	}
+
+	f, err := json.Marshal(Footer{
+		KeyID: keyID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMarshal, err)
+	}
+
+	return f, nil
+}
+
+// parseFooter parses a JSON footer.  Footers that aren't JSON objects are allowed by PASETO, and don't have a key ID.
+func parseFooter(f []byte) (Footer, error) {
+	footer := Footer{}
+
+	if len(f) == 0 || f[0] != '{' {
+		return footer, nil
+	}
+
+	if err := json.Unmarshal(f, &footer); err != nil {
+		return footer, fmt.Errorf("%w: %w", ErrFormat, err)
+	}
+
+	return footer, nil
+}
+
+// pae is the Pre-Authentication Encoding of pieces.
+func pae(pieces ...[]byte) []byte {
+	out := binary.LittleEndian.AppendUint64(nil, uint64(len(pieces)))
+
+	for i := range pieces {
+		out = binary.LittleEndian.AppendUint64(out, uint64(len(pieces[i]))&^(1<<63))
+		out = append(out, pieces[i]...)
+	}
+
+	return out
+}
+
+// split returns the decoded body and footer of a token with header.
+func split(token, header string) (body, footer []byte, err error) {
+	if !strings.HasPrefix(token, header) {
+		return nil, nil, fmt.Errorf("%w: header is not %s", ErrFormat, header)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(token, header), ".")
+	if len(parts) > 2 {
+		return nil, nil, ErrFormat
+	}
+
+	body, err = base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrFormat, err)
+	}
+
+	if len(parts) == 2 {
+		footer, err = base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrFormat, err)
+		}
+	}
+
+	return body, footer, nil
+}
+
+func join(header string, body, footer []byte) string {
+	t := header + base64.RawURLEncoding.EncodeToString(body)
+
+	if len(footer) > 0 {
+		t += "." + base64.RawURLEncoding.EncodeToString(footer)
+	}
+
+	return t
+}
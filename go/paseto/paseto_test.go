@@ -0,0 +1,52 @@
+package paseto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/jwt"
+)
+
+type claims struct {
+	Name string `json:"name"`
+	jwt.RegisteredClaims
+}
+
+func (c *claims) GetRegisteredClaims() *jwt.RegisteredClaims {
+	return &c.RegisteredClaims
+}
+
+func (*claims) Valid() error {
+	return nil
+}
+
+func TestPAE(t *testing.T) {
+	// https://github.com/paseto-standard/paseto-spec/blob/master/docs/01-Protocol-Versions/Common.md#pae-definition
+	assert.Equal(t, pae(), []byte("\x00\x00\x00\x00\x00\x00\x00\x00"))
+	assert.Equal(t, pae([]byte{}), []byte("\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"))
+	assert.Equal(t, pae([]byte("test")), []byte("\x01\x00\x00\x00\x00\x00\x00\x00\x04\x00\x00\x00\x00\x00\x00\x00test"))
+}
+
+func TestClaims(t *testing.T) {
+	c := claims{
+		Name: "name",
+	}
+
+	jwt.SetRegisteredClaims(&c, time.Date(2039, 1, 1, 0, 0, 0, 0, time.UTC), []string{"audience"}, "id", "issuer", "subject")
+	c.IssuedAt = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	c.NotBefore = c.IssuedAt
+
+	b, err := marshalClaims(&c)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, string(b), `{"aud":"audience","exp":"2039-01-01T00:00:00Z","iat":"2022-01-01T00:00:00Z","iss":"issuer","jti":"id","name":"name","nbf":"2022-01-01T00:00:00Z","sub":"subject"}`)
+
+	out := claims{}
+	assert.HasErr(t, unmarshalClaims(b, &out), nil)
+	assert.Equal(t, out, c)
+
+	assert.HasErr(t, unmarshalClaims([]byte(`{"exp":"2022-01-01T00:00:00+00:00"}`), &out), nil)
+	assert.Equal(t, out.ExpiresAt, c.IssuedAt)
+
+	assert.HasErr(t, unmarshalClaims([]byte(`{"exp":"tomorrow"}`), &out), ErrUnmarshal)
+}
@@ -0,0 +1,78 @@
+package paseto
+
+import (
+	"crypto/ed25519"
+	"fmt"
+
+	"github.com/candiddev/shared/go/cryptolib"
+	"github.com/candiddev/shared/go/jwt"
+)
+
+// Sign creates a v4.public PASETO from claims using an Ed25519 private key.  The key ID is added to the footer.
+func Sign(claims jwt.CustomClaims, k cryptolib.Key[cryptolib.KeyProviderPrivate]) (string, error) {
+	if _, ok := k.Key.(cryptolib.Ed25519PrivateKey); !ok {
+		return "", fmt.Errorf("%w: %s can't sign %s", ErrKey, k.Key.Algorithm(), HeaderV4Public)
+	}
+
+	m, err := marshalClaims(claims)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := newFooter(k.ID)
+	if err != nil {
+		return "", err
+	}
+
+	return signPublic(m, f, nil, k.Key)
+}
+
+func signPublic(m, f, i []byte, k cryptolib.KeyProviderPrivate) (string, error) {
+	sig, err := k.Sign(pae([]byte(HeaderV4Public), m, f, i), 0)
+	if err != nil {
+		return "", err
+	}
+
+	return join(HeaderV4Public, append(m, sig...), f), nil
+}
+
+func verifyPublic(token string, i []byte, keys cryptolib.Keys[cryptolib.KeyProviderPublic]) (*Token, error) {
+	body, f, err := split(token, HeaderV4Public)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) < ed25519.SignatureSize {
+		return nil, fmt.Errorf("%w: signature is too short", ErrFormat)
+	}
+
+	t := &Token{
+		Header:  HeaderV4Public,
+		Payload: body[:len(body)-ed25519.SignatureSize],
+	}
+
+	t.Footer, err = parseFooter(f)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err = selectKeys(keys, t.Footer.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	m := pae([]byte(HeaderV4Public), t.Payload, f, i)
+	sig := body[len(body)-ed25519.SignatureSize:]
+
+	for j := range keys {
+		if _, ok := keys[j].Key.(cryptolib.Ed25519PublicKey); !ok {
+			continue
+		}
+
+		if keys[j].Key.Verify(m, 0, sig) == nil {
+			return t, nil
+		}
+	}
+
+	return nil, ErrVerify
+}
@@ -0,0 +1,58 @@
+package paseto
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/cryptolib"
+)
+
+func TestPublic(t *testing.T) {
+	// https://github.com/paseto-standard/test-vectors/blob/master/v4.json 4-S-1
+	sk, _ := hex.DecodeString("b4cbfb43df4ce210727d953e4a713307fa19bb7d9f85041438d9e11b942a37741eb9dbbbbc047c03fd70604e0071f0987e16b28b757225c11f00415d0e20b1a2")
+	s := ed25519.NewKeyFromSeed(sk[:32])
+	prvB, _ := x509.MarshalPKCS8PrivateKey(s)
+	pubB, _ := x509.MarshalPKIXPublicKey(s.Public())
+	prv := cryptolib.Ed25519PrivateKey(base64.StdEncoding.EncodeToString(prvB))
+	pub := cryptolib.Ed25519PublicKey(base64.StdEncoding.EncodeToString(pubB))
+	m := `{"data":"this is a signed message","exp":"2022-01-01T00:00:00+00:00"}`
+	want := "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9bg_XBBzds8lTZShVlwwKSgeKpLT3yukTw6JUz3W4h_ExsQV-P0V54zemZDcAxFaSeef1QlXEFtkqxT1ciiQEDA"
+
+	got, err := signPublic([]byte(m), nil, nil, prv)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, got, want)
+
+	keys := cryptolib.Keys[cryptolib.KeyProviderPublic]{
+		{
+			Key: pub,
+		},
+	}
+
+	tok, err := verifyPublic(want, nil, keys)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, string(tok.Payload), m)
+
+	_, err = verifyPublic(want, []byte("implicit"), keys)
+	assert.HasErr(t, err, ErrVerify)
+
+	_, err = verifyPublic(want[:len(want)-2]+"AA", nil, keys)
+	assert.HasErr(t, err, ErrVerify)
+
+	_, err = verifyPublic(want[:20], nil, keys)
+	assert.HasErr(t, err, ErrFormat)
+
+	_, err = verifyPublic("v4.local."+want[10:], nil, keys)
+	assert.HasErr(t, err, ErrFormat)
+
+	_, err = verifyPublic(want, nil, nil)
+	assert.HasErr(t, err, ErrNoKeys)
+
+	_, err = Sign(&claims{}, cryptolib.Key[cryptolib.KeyProviderPrivate]{
+		Key: cryptolib.ECP256PrivateKey(""),
+	})
+	assert.HasErr(t, err, ErrKey)
+}
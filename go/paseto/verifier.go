@@ -0,0 +1,65 @@
+package paseto
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/candiddev/shared/go/cryptolib"
+	"github.com/candiddev/shared/go/jwt"
+)
+
+// Verifier verifies v4.public PASETOs using PublicKeys and decrypts v4.local PASETOs using LocalKeys.  It can be used as a jwt.ClaimsParser.
+type Verifier struct {
+	// LocalKeys are ChaCha20 keys used to decrypt v4.local tokens.  If empty, v4.local tokens are rejected.
+	LocalKeys cryptolib.Keys[cryptolib.KeyProviderSymmetric]
+
+	// PublicKeys are Ed25519 keys used to verify v4.public tokens.  If empty, v4.public tokens are rejected.
+	PublicKeys cryptolib.Keys[cryptolib.KeyProviderPublic]
+}
+
+// Parse verifies or decrypts a token based on its header.  Keys are selected using the footer kid.
+func (v *Verifier) Parse(token string) (*Token, error) {
+	switch {
+	case strings.HasPrefix(token, HeaderV4Local):
+		return decryptLocal(token, nil, v.LocalKeys)
+	case strings.HasPrefix(token, HeaderV4Public):
+		return verifyPublic(token, nil, v.PublicKeys)
+	}
+
+	return nil, fmt.Errorf("%w: unsupported header", ErrFormat)
+}
+
+// ParseClaims verifies a token and parses the payload into claims.
+func (v *Verifier) ParseClaims(_ context.Context, token string, claims jwt.CustomClaims, audRegex, jidRegex, subRegex string) error {
+	t, err := v.Parse(token)
+	if err != nil {
+		return err
+	}
+
+	return t.ParsePayload(claims, audRegex, jidRegex, subRegex)
+}
+
+func selectKeys[T cryptolib.KeyProvider](keys cryptolib.Keys[T], keyID string) (cryptolib.Keys[T], error) {
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	if keyID == "" {
+		return keys, nil
+	}
+
+	k := cryptolib.Keys[T]{}
+
+	for i := range keys {
+		if keys[i].ID == keyID {
+			k = append(k, keys[i])
+		}
+	}
+
+	if len(k) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrKeyID, keyID)
+	}
+
+	return k, nil
+}
@@ -0,0 +1,97 @@
+package paseto
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/cryptolib"
+	"github.com/candiddev/shared/go/jwt"
+)
+
+var _ jwt.ClaimsParser = &Verifier{}
+
+func TestVerifier(t *testing.T) {
+	ctx := context.Background()
+
+	prv, pub, _ := cryptolib.NewEd25519()
+	sym, _ := cryptolib.NewChaCha20Key(rand.Reader)
+
+	v := Verifier{
+		LocalKeys: cryptolib.Keys[cryptolib.KeyProviderSymmetric]{
+			{
+				ID:  "local",
+				Key: sym,
+			},
+		},
+		PublicKeys: cryptolib.Keys[cryptolib.KeyProviderPublic]{
+			{
+				ID:  "public",
+				Key: pub,
+			},
+		},
+	}
+
+	c := claims{
+		Name: "name",
+	}
+	jwt.SetRegisteredClaims(&c, time.Now().Add(time.Minute), []string{"audience"}, "id", "issuer", "subject")
+
+	public, err := Sign(&c, cryptolib.Key[cryptolib.KeyProviderPrivate]{
+		ID:  "public",
+		Key: prv,
+	})
+	assert.HasErr(t, err, nil)
+
+	local, err := Encrypt(&c, v.LocalKeys[0])
+	assert.HasErr(t, err, nil)
+
+	jwt.SetRegisteredClaims(&c, time.Now().Add(-1*time.Minute), nil, "", "", "")
+
+	expired, _ := Sign(&c, cryptolib.Key[cryptolib.KeyProviderPrivate]{
+		ID:  "public",
+		Key: prv,
+	})
+
+	tests := map[string]struct {
+		err      error
+		subRegex string
+		token    string
+	}{
+		"local": {
+			subRegex: "^subject$",
+			token:    local,
+		},
+		"public": {
+			subRegex: "^subject$",
+			token:    public,
+		},
+		"expired": {
+			err:   jwt.ErrTokenParsePayloadValidation,
+			token: expired,
+		},
+		"wrong subject": {
+			err:      jwt.ErrTokenParsePayloadValidation,
+			subRegex: "^other$",
+			token:    public,
+		},
+		"unsupported": {
+			err:   ErrFormat,
+			token: "v3.public.a",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := claims{}
+			assert.HasErr(t, v.ParseClaims(ctx, tc.token, &out, "", "", tc.subRegex), tc.err)
+
+			if tc.err == nil {
+				assert.Equal(t, out.Name, "name")
+				assert.Equal(t, out.Subject, "subject")
+			}
+		})
+	}
+}
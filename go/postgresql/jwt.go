@@ -0,0 +1,164 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"time"
+
+	"github.com/candiddev/shared/go/errs"
+	"github.com/candiddev/shared/go/logger"
+)
+
+//go:embed migrations_jwt/*.sql
+var migrationsJWT embed.FS
+
+// MigrateJWT creates the tables used by the jwt.RevocationStore and jwt.RefreshStore methods of Config.
+func (c *Config) MigrateJWT(ctx context.Context) errs.Err {
+	return c.Migrate(ctx, "jwt", embed.FS{}, migrationsJWT)
+}
+
+// CreateRefreshFamily creates a new refresh token family that expires at expiresAt.  A zero expiresAt never expires.
+func (c *Config) CreateRefreshFamily(ctx context.Context, familyID, tokenID string, expiresAt time.Time) error {
+	return c.Exec(ctx, `
+INSERT INTO jwt_refresh_family (
+	  expires
+	, id
+	, token_id
+) VALUES (
+	  :expires
+	, :id
+	, :token_id
+)
+`, map[string]any{
+		"expires": sql.NullTime{
+			Time:  expiresAt,
+			Valid: !expiresAt.IsZero(),
+		},
+		"id":       familyID,
+		"token_id": tokenID,
+	})
+}
+
+// IsJWTRevoked returns whether a JWT ID has been revoked.
+func (c *Config) IsJWTRevoked(ctx context.Context, id string) (bool, error) {
+	var r bool
+
+	if err := c.Query(ctx, false, &r, `
+SELECT EXISTS (
+	SELECT 1
+	FROM jwt_revocation
+	WHERE id = $1
+	AND (
+		expires IS NULL
+		OR expires > now()
+	)
+)
+`, nil, id); err != nil {
+		return false, err
+	}
+
+	return r, nil
+}
+
+// PurgeJWT deletes expired JWT revocations and refresh token families.
+func (c *Config) PurgeJWT(ctx context.Context) errs.Err {
+	ctx = logger.Trace(ctx)
+
+	for _, query := range []string{
+		"DELETE FROM jwt_revocation WHERE expires < now()",
+		"DELETE FROM jwt_refresh_family WHERE expires < now()",
+	} {
+		if err := c.Exec(ctx, query, nil); err != nil && !err.Like(errs.ErrSenderNoContent) {
+			return logger.Error(ctx, err)
+		}
+	}
+
+	return logger.Error(ctx, nil)
+}
+
+// RunPurgeJWT runs PurgeJWT every interval until ctx is done.
+func (c *Config) RunPurgeJWT(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			c.PurgeJWT(ctx) //nolint:errcheck
+		}
+	}
+}
+
+// RevokeJWT revokes a JWT ID until expiresAt.  A zero expiresAt never expires.
+func (c *Config) RevokeJWT(ctx context.Context, id string, expiresAt time.Time) error {
+	return c.Exec(ctx, `
+INSERT INTO jwt_revocation (
+	  expires
+	, id
+) VALUES (
+	  :expires
+	, :id
+)
+ON CONFLICT (
+	id
+) DO UPDATE SET expires = :expires
+`, map[string]any{
+		"expires": sql.NullTime{
+			Time:  expiresAt,
+			Valid: !expiresAt.IsZero(),
+		},
+		"id": id,
+	})
+}
+
+// RevokeRefreshFamily revokes a refresh token family.
+func (c *Config) RevokeRefreshFamily(ctx context.Context, familyID string) error {
+	if err := c.Exec(ctx, `
+UPDATE jwt_refresh_family
+SET revoked = true
+WHERE id = :id
+`, map[string]any{
+		"id": familyID,
+	}); err != nil && !err.Like(errs.ErrSenderNoContent) {
+		return err
+	}
+
+	return nil
+}
+
+// RotateRefreshFamily replaces the current token ID of a refresh token family if tokenID is current and the family is not revoked or expired.  A zero expiresAt never expires.
+func (c *Config) RotateRefreshFamily(ctx context.Context, familyID, tokenID, nextTokenID string, expiresAt time.Time) (bool, error) {
+	err := c.Exec(ctx, `
+UPDATE jwt_refresh_family
+SET
+	  expires = :expires
+	, token_id = :next_token_id
+WHERE id = :id
+AND token_id = :token_id
+AND revoked IS FALSE
+AND (
+	expires IS NULL
+	OR expires > now()
+)
+`, map[string]any{
+		"expires": sql.NullTime{
+			Time:  expiresAt,
+			Valid: !expiresAt.IsZero(),
+		},
+		"id":            familyID,
+		"next_token_id": nextTokenID,
+		"token_id":      tokenID,
+	})
+	if err != nil {
+		if err.Like(errs.ErrSenderNoContent) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,70 @@
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/jwt"
+	"github.com/candiddev/shared/go/logger"
+)
+
+var (
+	_ jwt.RefreshStore    = &Config{}
+	_ jwt.RevocationStore = &Config{}
+)
+
+func TestJWT(t *testing.T) {
+	logger.UseTestLogger(t)
+
+	assert.HasErr(t, p.MigrateJWT(ctx), nil)
+
+	assert.HasErr(t, p.RevokeJWT(ctx, "expired", time.Now().Add(-1*time.Minute)), nil)
+	assert.HasErr(t, p.RevokeJWT(ctx, "forever", time.Time{}), nil)
+	assert.HasErr(t, p.RevokeJWT(ctx, "valid", time.Now().Add(time.Minute)), nil)
+
+	for id, want := range map[string]bool{
+		"expired": false,
+		"forever": true,
+		"missing": false,
+		"valid":   true,
+	} {
+		got, err := p.IsJWTRevoked(ctx, id)
+		assert.HasErr(t, err, nil)
+		assert.Equal(t, got, want)
+	}
+
+	assert.HasErr(t, p.CreateRefreshFamily(ctx, "expired", "1", time.Now().Add(-1*time.Minute)), nil)
+	assert.HasErr(t, p.CreateRefreshFamily(ctx, "family", "1", time.Now().Add(time.Minute)), nil)
+	assert.HasErr(t, p.CreateRefreshFamily(ctx, "forever", "1", time.Time{}), nil)
+
+	ok, err := p.RotateRefreshFamily(ctx, "expired", "1", "2", time.Now().Add(time.Minute))
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, ok, false)
+
+	ok, _ = p.RotateRefreshFamily(ctx, "family", "1", "2", time.Now().Add(time.Minute))
+	assert.Equal(t, ok, true)
+
+	ok, _ = p.RotateRefreshFamily(ctx, "family", "1", "3", time.Now().Add(time.Minute))
+	assert.Equal(t, ok, false)
+
+	ok, _ = p.RotateRefreshFamily(ctx, "forever", "1", "2", time.Time{})
+	assert.Equal(t, ok, true)
+
+	ok, _ = p.RotateRefreshFamily(ctx, "forever", "2", "3", time.Time{})
+	assert.Equal(t, ok, true)
+
+	assert.HasErr(t, p.RevokeRefreshFamily(ctx, "family"), nil)
+
+	ok, _ = p.RotateRefreshFamily(ctx, "family", "2", "3", time.Now().Add(time.Minute))
+	assert.Equal(t, ok, false)
+
+	assert.HasErr(t, p.PurgeJWT(ctx), nil)
+
+	var n int
+
+	assert.HasErr(t, p.Query(ctx, false, &n, "SELECT COUNT(*) FROM jwt_revocation", nil), nil)
+	assert.Equal(t, n, 2)
+	assert.HasErr(t, p.Query(ctx, false, &n, "SELECT COUNT(*) FROM jwt_refresh_family", nil), nil)
+	assert.Equal(t, n, 2)
+}
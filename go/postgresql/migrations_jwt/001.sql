@@ -0,0 +1,15 @@
+CREATE TABLE jwt_revocation (
+	  id text primary key
+	, expires timestamp with time zone
+);
+
+CREATE INDEX jwt_revocation_expires ON jwt_revocation (expires);
+
+CREATE TABLE jwt_refresh_family (
+	  id text primary key
+	, expires timestamp with time zone
+	, revoked boolean not null default false
+	, token_id text not null
+);
+
+CREATE INDEX jwt_refresh_family_expires ON jwt_refresh_family (expires);
@@ -0,0 +1,431 @@
+// Package selfupdate contains a command for updating a CLI binary from signed releases.
+package selfupdate
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/candiddev/shared/go/cli"
+	"github.com/candiddev/shared/go/cryptolib"
+	"github.com/candiddev/shared/go/errs"
+	"github.com/candiddev/shared/go/get"
+	"github.com/candiddev/shared/go/logger"
+)
+
+var (
+	ErrDownload   = errors.New("error downloading binary")
+	ErrManifest   = errors.New("error getting release manifest")
+	ErrNoBinary   = errors.New("release has no binary for this platform")
+	ErrNoKeys     = errors.New("no public keys configured")
+	ErrReplace    = errors.New("error replacing executable")
+	ErrRollback   = errors.New("error rolling back executable")
+	ErrSHA256     = errors.New("binary SHA-256 does not match release manifest")
+	ErrSignature  = errors.New("binary signature is not valid")
+	ErrVerifyExec = errors.New("updated executable failed verification, rolled back")
+)
+
+// Config configures a self-update.
+type Config struct {
+	/* Executable is the path to replace, defaults to os.Executable */
+	Executable string
+
+	/* ManifestURL is the location of the release Manifest, passed to get.File */
+	ManifestURL string
+
+	/* PublicKeys verify the Signature of the binary, and should be embedded in the application */
+	PublicKeys cryptolib.Keys[cryptolib.KeyProviderPublic]
+
+	/* VerifyArgs are the arguments used to run the updated executable, defaults to version.  The output must contain the release version, otherwise the update is rolled back */
+	VerifyArgs []string
+}
+
+// Manifest is a release manifest.
+type Manifest struct {
+	/* Binaries are keyed by platform, like linux_amd64 */
+	Binaries map[string]ManifestBinary `json:"binaries"`
+	Version  string                    `json:"version"`
+}
+
+// ManifestBinary is a binary within a Manifest.
+type ManifestBinary struct {
+	/* SHA256 is the hex encoded SHA-256 of the binary */
+	SHA256 string `json:"sha256"`
+
+	/* Signature of the SignaturePayload of the binary, created using NewManifestBinary */
+	Signature cryptolib.Signature `json:"signature"`
+
+	/* URL of the binary, relative to the manifest URL if it isn't absolute */
+	URL string `json:"url"`
+}
+
+// Result is the result of an update.
+type Result struct {
+	Current string `json:"current"`
+	Latest  string `json:"latest"`
+	Updated bool   `json:"updated"`
+}
+
+// Command returns a self-update command for use with cli.App.
+func Command[T cli.AppConfig[any]](c Config) cli.Command[T] {
+	return cli.Command[T]{
+		Flags: cli.Flags{
+			"check": {
+				Default: false,
+				Usage:   "Check for an update without installing it",
+			},
+			"force": {
+				Default: false,
+				Usage:   "Install the latest release even if it isn't newer",
+			},
+		},
+		Name: "self-update",
+		Run: func(ctx context.Context, args []string, config T) errs.Err {
+			var r Result
+
+			var err errs.Err
+
+			if cli.GetFlag[bool](ctx, "check") {
+				r, err = c.Check(ctx)
+			} else {
+				r, err = c.Update(ctx, config.CLIConfig(), cli.GetFlag[bool](ctx, "force"))
+			}
+
+			if err != nil {
+				return err
+			}
+
+			return cli.Print(r)
+		},
+		Usage: "Update this binary to the latest release",
+	}
+}
+
+// NewManifestBinary creates a signed ManifestBinary for a release of binary.  The signature covers the version, platform, and SHA-256 of the binary, so a Manifest can't claim a different version for a signed binary.
+func NewManifestBinary(key cryptolib.Key[cryptolib.KeyProviderPrivate], version, platform, url string, binary []byte) (ManifestBinary, error) {
+	s := sha256.Sum256(binary)
+
+	m := ManifestBinary{
+		SHA256: hex.EncodeToString(s[:]),
+		URL:    url,
+	}
+
+	sig, err := cryptolib.NewSignature(key, SignaturePayload(version, platform, m.SHA256))
+	if err != nil {
+		return m, err
+	}
+
+	m.Signature = sig
+
+	return m, nil
+}
+
+// SignaturePayload returns the message signed by ManifestBinary.Signature.
+func SignaturePayload(version, platform, sha256 string) []byte {
+	return []byte(fmt.Sprintf("%s\n%s\n%s", version, platform, strings.ToLower(sha256)))
+}
+
+// Platform returns the Manifest.Binaries key of the current platform.
+func Platform() string {
+	return runtime.GOOS + "_" + runtime.GOARCH
+}
+
+// Check gets the Manifest and returns whether it has a newer version than cli.BuildVersion.  The version is only trusted if the binary for the current platform has a valid Signature.
+func (c Config) Check(ctx context.Context) (Result, errs.Err) {
+	r, _, err := c.check(ctx)
+
+	return r, err
+}
+
+// Update gets the Manifest and, if it has a newer version than cli.BuildVersion or force is true, replaces the executable with the binary for the current platform.  The Signature of the version, platform, and SHA-256 must be valid, and the binary must match the SHA-256.  If the updated executable can't be run using VerifyArgs, the previous executable is restored.
+func (c Config) Update(ctx context.Context, config *cli.Config, force bool) (Result, errs.Err) {
+	r, m, err := c.check(ctx)
+	if err != nil {
+		return r, err
+	}
+
+	if !r.Updated && !force {
+		logger.Info(ctx, "Already up to date: "+r.Current)
+
+		return r, nil
+	}
+
+	r.Updated = false
+
+	b, err := c.download(ctx, m.Binaries[Platform()])
+	if err != nil {
+		return r, err
+	}
+
+	exe := c.Executable
+	if exe == "" {
+		e, err := os.Executable()
+		if err != nil {
+			return r, logger.Error(ctx, errs.ErrReceiver.Wrap(ErrReplace, err))
+		}
+
+		exe, err = filepath.EvalSymlinks(e)
+		if err != nil {
+			return r, logger.Error(ctx, errs.ErrReceiver.Wrap(ErrReplace, err))
+		}
+	}
+
+	if err := c.replace(ctx, config, exe, b, m.Version); err != nil {
+		return r, err
+	}
+
+	logger.Info(ctx, fmt.Sprintf("Updated %s from %s to %s", exe, r.Current, r.Latest))
+
+	r.Updated = true
+
+	return r, nil
+}
+
+func (c Config) check(ctx context.Context) (Result, Manifest, errs.Err) {
+	r := Result{
+		Current: cli.BuildVersion,
+	}
+
+	var m Manifest
+
+	b := &bytes.Buffer{}
+
+	if _, err := get.File(ctx, c.ManifestURL, b, time.Time{}); err != nil {
+		return r, m, logger.Error(ctx, errs.ErrReceiver.Wrap(ErrManifest, err))
+	}
+
+	if err := json.Unmarshal(b.Bytes(), &m); err != nil {
+		return r, m, logger.Error(ctx, errs.ErrReceiver.Wrap(ErrManifest, err))
+	}
+
+	if len(c.PublicKeys) == 0 {
+		return r, m, logger.Error(ctx, errs.ErrReceiver.Wrap(ErrNoKeys))
+	}
+
+	bin, ok := m.Binaries[Platform()]
+	if !ok {
+		return r, m, logger.Error(ctx, errs.ErrReceiver.Wrap(ErrNoBinary, errors.New(Platform())))
+	}
+
+	// The manifest isn't signed, so the version is verified using the signature of the binary to prevent downgrades.
+	if err := bin.Signature.Verify(SignaturePayload(m.Version, Platform(), bin.SHA256), c.PublicKeys); err != nil {
+		return r, m, logger.Error(ctx, errs.ErrReceiver.Wrap(ErrSignature, err))
+	}
+
+	r.Latest = m.Version
+	r.Updated = compareVersions(m.Version, cli.BuildVersion) > 0
+
+	return r, m, nil
+}
+
+// download gets the binary and verifies it matches the SHA-256 verified by check.
+func (c Config) download(ctx context.Context, bin ManifestBinary) ([]byte, errs.Err) {
+	src, e := c.resolve(bin.URL)
+	if e != nil {
+		return nil, logger.Error(ctx, errs.ErrReceiver.Wrap(ErrDownload, e))
+	}
+
+	logger.Info(ctx, "Downloading "+src)
+
+	b := &bytes.Buffer{}
+
+	if _, err := get.File(ctx, src, b, time.Time{}); err != nil {
+		return nil, logger.Error(ctx, errs.ErrReceiver.Wrap(ErrDownload, err))
+	}
+
+	s := sha256.Sum256(b.Bytes())
+	if !strings.EqualFold(hex.EncodeToString(s[:]), bin.SHA256) {
+		return nil, logger.Error(ctx, errs.ErrReceiver.Wrap(ErrSHA256))
+	}
+
+	return b.Bytes(), nil
+}
+
+// replace atomically replaces exe with b, and restores exe if b can't be run.
+func (c Config) replace(ctx context.Context, config *cli.Config, exe string, b []byte, version string) errs.Err { //nolint:gocognit
+	s, err := os.Stat(exe)
+	if err != nil {
+		return logger.Error(ctx, errs.ErrReceiver.Wrap(ErrReplace, err))
+	}
+
+	dir := filepath.Dir(exe)
+	old := filepath.Join(dir, "."+filepath.Base(exe)+".old")
+
+	// The new binary is written to the same directory so it can be renamed over exe.
+	f, err := os.CreateTemp(dir, "."+filepath.Base(exe)+".new*")
+	if err != nil {
+		return logger.Error(ctx, errs.ErrReceiver.Wrap(ErrReplace, err))
+	}
+
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(b)
+	if err == nil {
+		err = f.Chmod(s.Mode().Perm())
+	}
+
+	if e := f.Close(); err == nil {
+		err = e
+	}
+
+	if err != nil {
+		return logger.Error(ctx, errs.ErrReceiver.Wrap(ErrReplace, err))
+	}
+
+	// exe is kept as old for rollback without removing it, so the rename over exe is the only change to exe.
+	if err := backup(exe, old); err != nil {
+		return logger.Error(ctx, errs.ErrReceiver.Wrap(ErrReplace, err))
+	}
+
+	if err := os.Rename(f.Name(), exe); err != nil {
+		os.Remove(old)
+
+		return logger.Error(ctx, errs.ErrReceiver.Wrap(ErrReplace, err))
+	}
+
+	args := c.VerifyArgs
+	if args == nil {
+		args = []string{
+			"version",
+		}
+	}
+
+	out, e := config.Run(ctx, cli.RunOpts{
+		Args:       args,
+		Command:    exe,
+		NoErrorLog: true,
+	})
+	if e == nil && version != "" && !strings.Contains(out.String(), version) {
+		e = errs.ErrReceiver.Wrap(fmt.Errorf("output does not contain version %s: %s", version, out.String()))
+	}
+
+	if e != nil {
+		if err := os.Rename(old, exe); err != nil {
+			return logger.Error(ctx, errs.ErrReceiver.Wrap(ErrRollback, err))
+		}
+
+		return logger.Error(ctx, errs.ErrReceiver.Wrap(ErrVerifyExec, e))
+	}
+
+	if err := os.Remove(old); err != nil {
+		logger.Error(ctx, errs.ErrReceiver.Wrap(ErrReplace, err)) //nolint:errcheck
+	}
+
+	return nil
+}
+
+// backup hard links exe to old, or copies it if hard links aren't supported.
+func backup(exe, old string) error {
+	if err := os.Remove(old); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.Link(exe, old); err == nil {
+		return nil
+	}
+
+	s, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+
+	b, err := os.ReadFile(exe)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(old, b, s.Mode().Perm())
+}
+
+// resolve returns the location of a binary URL relative to the manifest.
+func (c Config) resolve(src string) (string, error) {
+	if strings.HasPrefix(src, "http") || filepath.IsAbs(src) {
+		return src, nil
+	}
+
+	if !strings.HasPrefix(c.ManifestURL, "http") {
+		return filepath.Join(filepath.Dir(c.ManifestURL), src), nil
+	}
+
+	base, err := url.Parse(strings.Split(c.ManifestURL, "#")[0])
+	if err != nil {
+		return "", err
+	}
+
+	ref, err := url.Parse(src)
+	if err != nil {
+		return "", err
+	}
+
+	return base.ResolveReference(ref).String(), nil
+}
+
+// compareVersions compares the numeric components of two versions, like v1.2.3 or 2024.01.02, returning 1 if a is newer, -1 if b is newer, and 0 otherwise.  A pre-release, like 1.2.3-rc1, is older than the version without it, and pre-releases of the same version are compared using their numeric components.
+func compareVersions(a, b string) int {
+	a, preA, okA := strings.Cut(strings.SplitN(a, "+", 2)[0], "-")
+	b, preB, okB := strings.Cut(strings.SplitN(b, "+", 2)[0], "-")
+
+	if c := compareParts(versionParts(a), versionParts(b)); c != 0 {
+		return c
+	}
+
+	switch {
+	case okA && !okB:
+		return -1
+	case !okA && okB:
+		return 1
+	}
+
+	return compareParts(versionParts(preA), versionParts(preB))
+}
+
+func compareParts(pa, pb []int) int {
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+
+		if i < len(pa) {
+			x = pa[i]
+		}
+
+		if i < len(pb) {
+			y = pb[i]
+		}
+
+		switch {
+		case x > y:
+			return 1
+		case x < y:
+			return -1
+		}
+	}
+
+	return 0
+}
+
+func versionParts(v string) []int {
+	out := []int{}
+
+	for _, s := range strings.FieldsFunc(v, func(r rune) bool {
+		return r < '0' || r > '9'
+	}) {
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			break
+		}
+
+		out = append(out, i)
+	}
+
+	return out
+}
@@ -0,0 +1,330 @@
+package selfupdate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/candiddev/shared/go/assert"
+	"github.com/candiddev/shared/go/cli"
+	"github.com/candiddev/shared/go/cryptolib"
+	"github.com/candiddev/shared/go/get"
+	"github.com/candiddev/shared/go/logger"
+)
+
+func TestUpdate(t *testing.T) {
+	ctx := logger.UseTestLogger(t)
+
+	cli.BuildVersion = "1.0.0"
+
+	prv, pub, err := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmEd25519)
+	assert.HasErr(t, err, nil)
+
+	prvOther, _, err := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmEd25519)
+	assert.HasErr(t, err, nil)
+
+	good := []byte("#!/bin/sh\necho 'Build Version: 1.1.0'\n")
+	bad := []byte("#!/bin/sh\nexit 1\n")
+	old := []byte("#!/bin/sh\necho 'Build Version: 1.0.0'\n")
+	reinstall := []byte("#!/bin/sh\necho 'Build Version: 1.0.0' >&2\n")
+
+	tests := map[string]struct {
+		binary      []byte
+		force       bool
+		key         cryptolib.Key[cryptolib.KeyProviderPrivate]
+		platform    string
+		sha         []byte
+		signVersion string
+		version     string
+		wantErr     error
+		wantResult  Result
+		wantUpdated bool
+	}{
+		"current": {
+			binary:  good,
+			key:     prv,
+			version: "1.0.0",
+			wantResult: Result{
+				Current: "1.0.0",
+				Latest:  "1.0.0",
+			},
+		},
+		"older": {
+			binary:  good,
+			key:     prv,
+			version: "0.9.9",
+			wantResult: Result{
+				Current: "1.0.0",
+				Latest:  "0.9.9",
+			},
+		},
+		"no_binary": {
+			binary:   good,
+			key:      prv,
+			platform: "plan9_mips",
+			version:  "1.1.0",
+			wantErr:  ErrNoBinary,
+			wantResult: Result{
+				Current: "1.0.0",
+			},
+		},
+		"bad_sha": {
+			binary:  good,
+			key:     prv,
+			sha:     bad,
+			version: "1.1.0",
+			wantErr: ErrSHA256,
+			wantResult: Result{
+				Current: "1.0.0",
+				Latest:  "1.1.0",
+			},
+		},
+		"bad_signature": {
+			binary:  good,
+			key:     prvOther,
+			version: "1.1.0",
+			wantErr: ErrSignature,
+			wantResult: Result{
+				Current: "1.0.0",
+			},
+		},
+		"downgrade": {
+			binary:      old,
+			key:         prv,
+			signVersion: "0.9.0",
+			version:     "1.1.0",
+			wantErr:     ErrSignature,
+			wantResult: Result{
+				Current: "1.0.0",
+			},
+		},
+		"rollback": {
+			binary:  bad,
+			key:     prv,
+			version: "1.1.0",
+			wantErr: ErrVerifyExec,
+			wantResult: Result{
+				Current: "1.0.0",
+				Latest:  "1.1.0",
+			},
+		},
+		"force": {
+			binary:  reinstall,
+			force:   true,
+			key:     prv,
+			version: "1.0.0",
+			wantResult: Result{
+				Current: "1.0.0",
+				Latest:  "1.0.0",
+				Updated: true,
+			},
+			wantUpdated: true,
+		},
+		"newer": {
+			binary:  good,
+			key:     prv,
+			version: "1.1.0",
+			wantResult: Result{
+				Current: "1.0.0",
+				Latest:  "1.1.0",
+				Updated: true,
+			},
+			wantUpdated: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			bin := get.NewHTTPMock([]string{"/bin/app"}, tc.binary, time.Time{})
+			defer bin.Close()
+
+			sha := tc.sha
+			if sha == nil {
+				sha = tc.binary
+			}
+
+			platform := tc.platform
+			if platform == "" {
+				platform = Platform()
+			}
+
+			version := tc.signVersion
+			if version == "" {
+				version = tc.version
+			}
+
+			mb, err := NewManifestBinary(tc.key, version, platform, bin.URL()+"/bin/app", sha)
+			assert.HasErr(t, err, nil)
+
+			m, err := json.Marshal(Manifest{
+				Binaries: map[string]ManifestBinary{
+					platform: mb,
+				},
+				Version: tc.version,
+			})
+			assert.HasErr(t, err, nil)
+
+			manifest := get.NewHTTPMock([]string{"/manifest.json"}, m, time.Time{})
+			defer manifest.Close()
+
+			exe := filepath.Join(t.TempDir(), "app")
+			assert.HasErr(t, os.WriteFile(exe, old, 0700), nil)
+
+			c := Config{
+				Executable:  exe,
+				ManifestURL: manifest.URL() + "/manifest.json",
+				PublicKeys: cryptolib.Keys[cryptolib.KeyProviderPublic]{
+					pub,
+				},
+			}
+
+			r, err := c.Update(ctx, &cli.Config{}, tc.force)
+			assert.HasErr(t, err, tc.wantErr)
+			assert.Equal(t, r, tc.wantResult)
+
+			b, _ := os.ReadFile(exe)
+			if tc.wantUpdated {
+				assert.Equal(t, b, tc.binary)
+			} else {
+				assert.Equal(t, b, old)
+			}
+
+			f, _ := os.ReadDir(filepath.Dir(exe))
+			assert.Equal(t, len(f), 1)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	ctx := logger.UseTestLogger(t)
+
+	cli.BuildVersion = "v1.2.3"
+
+	prv, pub, err := cryptolib.NewKeysAsymmetric(cryptolib.AlgorithmEd25519)
+	assert.HasErr(t, err, nil)
+
+	mb, err := NewManifestBinary(prv, "v1.10.0", Platform(), "app", []byte("app"))
+	assert.HasErr(t, err, nil)
+
+	b, err := json.Marshal(Manifest{
+		Binaries: map[string]ManifestBinary{
+			Platform(): mb,
+		},
+		Version: "v1.10.0",
+	})
+	assert.HasErr(t, err, nil)
+
+	m := get.NewHTTPMock([]string{"/manifest.json"}, b, time.Time{})
+	defer m.Close()
+
+	c := Config{
+		ManifestURL: m.URL() + "/manifest.json",
+	}
+
+	_, err = c.Check(ctx)
+	assert.HasErr(t, err, ErrNoKeys)
+
+	c.PublicKeys = cryptolib.Keys[cryptolib.KeyProviderPublic]{
+		pub,
+	}
+
+	r, err := c.Check(ctx)
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, r, Result{
+		Current: "v1.2.3",
+		Latest:  "v1.10.0",
+		Updated: true,
+	})
+
+	c.ManifestURL = m.URL() + "/missing.json"
+
+	_, err = c.Check(ctx)
+	assert.HasErr(t, err, ErrManifest)
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{
+			a:    "1.0.0",
+			b:    "1.0.0",
+			want: 0,
+		},
+		{
+			a:    "v1.10.0",
+			b:    "v1.9.0",
+			want: 1,
+		},
+		{
+			a:    "2024.01",
+			b:    "2024.01.1",
+			want: -1,
+		},
+		{
+			a:    "1.0",
+			b:    "1.0.0",
+			want: 0,
+		},
+		{
+			a:    "1.0.0",
+			b:    "",
+			want: 1,
+		},
+		{
+			a:    "1.2.3-rc1",
+			b:    "1.2.3",
+			want: -1,
+		},
+		{
+			a:    "v1.2.3",
+			b:    "v1.2.3-rc1",
+			want: 1,
+		},
+		{
+			a:    "1.2.3-rc2",
+			b:    "1.2.3-rc1",
+			want: 1,
+		},
+		{
+			a:    "1.2.4-rc1",
+			b:    "1.2.3",
+			want: 1,
+		},
+		{
+			a:    "1.2.3+build2",
+			b:    "1.2.3",
+			want: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.a+"_"+tc.b, func(t *testing.T) {
+			assert.Equal(t, compareVersions(tc.a, tc.b), tc.want)
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	c := Config{
+		ManifestURL: "https://example.com/releases/manifest.json#header:value",
+	}
+
+	s, err := c.resolve("app_linux_amd64")
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, s, "https://example.com/releases/app_linux_amd64")
+
+	s, err = c.resolve("https://cdn.example.com/app")
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, s, "https://cdn.example.com/app")
+
+	c.ManifestURL = "/releases/manifest.json"
+
+	s, err = c.resolve("app")
+	assert.HasErr(t, err, nil)
+	assert.Equal(t, s, "/releases/app")
+}